@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"github.com/ddev/ddev/pkg/ddevapp"
+	"github.com/ddev/ddev/pkg/output"
+	"github.com/ddev/ddev/pkg/util"
+	"github.com/spf13/cobra"
+)
+
+// CloneCmd implements the ddev clone command
+var CloneCmd = &cobra.Command{
+	Use:   "clone <newname>",
+	Short: "Copy a project with its database and upload directories under a new name",
+	Long: `Copy a project, with its code, database and upload directories, to a new project with another name and URL,
+and start it next to the original, which isn't changed. The code is copied to a directory next to the original one
+by default, or checked out in a git worktree with --git-worktree. The new name is set in .ddev/` + ddevapp.CloneConfigFileName + `,
+which also drops the additional hostnames and fixed host ports of the original.`,
+	Example: `ddev clone my-site-upgrade
+ddev clone my-site-upgrade --dir ~/tmp/upgrade
+ddev clone my-site-upgrade --git-worktree upgrade/drupal-11
+ddev clone my-site-copy --project my-site`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		projectName, _ := cmd.Flags().GetString("project")
+		dir, _ := cmd.Flags().GetString("dir")
+		branch, _ := cmd.Flags().GetString("git-worktree")
+
+		app, err := ddevapp.GetActiveApp(projectName)
+		if err != nil {
+			util.Failed("Failed to get project: %v", err)
+		}
+		clone, err := app.Clone(ddevapp.CloneOptions{
+			Name:        args[0],
+			Dir:         dir,
+			GitWorktree: branch,
+		})
+		if err != nil {
+			util.Failed("Failed to clone %s: %v", app.Name, err)
+		}
+		url := clone.GetPrimaryURL()
+		output.UserOut.WithField("raw", map[string]string{
+			"name":    clone.Name,
+			"approot": clone.AppRoot,
+			"url":     url,
+		}).Printf("Cloned %s to %s in %s, it's running at %s", app.Name, clone.Name, clone.AppRoot, url)
+	},
+}
+
+func init() {
+	CloneCmd.Flags().String("dir", "", "Directory of the new project, next to the original one by default")
+	CloneCmd.Flags().String("git-worktree", "", "Check out this branch in a git worktree instead of copying the code, creating the branch if needed")
+	CloneCmd.Flags().StringP("project", "p", "", "Project to clone, defaults to the one for the current directory")
+	_ = CloneCmd.RegisterFlagCompletionFunc("project", ddevapp.GetProjectNamesFunc("all", 0))
+	RootCmd.AddCommand(CloneCmd)
+}
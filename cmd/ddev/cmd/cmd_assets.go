@@ -4,5 +4,5 @@ import (
 	"embed"
 )
 
-//go:embed scripts/test_ddev.sh scripts/diagnose_ddev.sh
+//go:embed scripts/test_ddev.sh
 var bundledAssets embed.FS
@@ -209,6 +209,18 @@ func addCustomCommandsFromDir(rootCmd *cobra.Command, app *ddevapp.DdevApp, serv
 			}
 		}
 
+		// Run a web command with a PHP version from php_pools
+		phpVersion := ""
+		if val, ok := directives["PHPVersion"]; ok {
+			if service != "web" {
+				if isCustomCommandInArgs(commandName) {
+					util.Warning("Command '%s' has a PHPVersion annotation, which can only be used with web container commands, skipping %s", commandName, onHostFullPath)
+				}
+				continue
+			}
+			phpVersion = val
+		}
+
 		// Run the command with mutagen sync or not
 		mutagenSync := false
 		if val, ok := directives["MutagenSync"]; ok {
@@ -339,7 +351,7 @@ func addCustomCommandsFromDir(rootCmd *cobra.Command, app *ddevapp.DdevApp, serv
 				containerBasePath = path.Join("/mnt/ddev-global-cache/global-commands/", service)
 			}
 			inContainerFullPath := path.Join(containerBasePath, commandName)
-			commandToAdd.Run = makeContainerCmd(app, inContainerFullPath, commandName, service, execRaw, relative, mutagenSync)
+			commandToAdd.Run = makeContainerCmd(app, inContainerFullPath, commandName, service, execRaw, relative, mutagenSync, phpVersion)
 			if fileutil.FileExists(autocompletePathOnHost) {
 				// Make sure autocomplete script can be executed
 				_ = util.Chmod(autocompletePathOnHost, 0755)
@@ -488,7 +500,7 @@ func makeHostCmd(app *ddevapp.DdevApp, fullPath, name string, mutagenSync bool)
 }
 
 // makeContainerCmd creates the command which will app.Exec to a container command
-func makeContainerCmd(app *ddevapp.DdevApp, fullPath, name, service string, execRaw bool, relative bool, mutagenSync bool) func(*cobra.Command, []string) {
+func makeContainerCmd(app *ddevapp.DdevApp, fullPath, name, service string, execRaw bool, relative bool, mutagenSync bool, phpVersion string) func(*cobra.Command, []string) {
 	s := service
 	if s[0:1] == "." {
 		s = s[1:]
@@ -523,10 +535,26 @@ func makeContainerCmd(app *ddevapp.DdevApp, fullPath, name, service string, exec
 			opts.Dir = path.Join(app.GetAbsAppRoot(true), app.GetRelativeWorkingDirectory())
 		}
 
+		// Prepend the directory of the PHP version to $PATH, as 'ddev exec --php' does
+		phpBinDir, err := app.GetPHPBinDir(phpVersion)
+		if err != nil {
+			util.Failed("Failed to run %s: %v", name, err)
+		}
+		if phpBinDir != "" {
+			containerPath, _, err := app.Exec(&ddevapp.ExecOpts{
+				Service: s,
+				Cmd:     "echo $PATH",
+			})
+			containerPath = strings.Trim(containerPath, "\n")
+			if err == nil && containerPath != "" {
+				opts.Env = append(opts.Env, "PATH="+phpBinDir+":"+containerPath)
+			}
+		}
+
 		if execRaw {
 			opts.RawCmd = append([]string{fullPath}, osArgs...)
 		}
-		_, _, err := app.Exec(opts)
+		_, _, err = app.Exec(opts)
 
 		if err != nil {
 			util.Failed("Failed to run %s %v: %v", name, strings.Join(osArgs, " "), err)
@@ -29,7 +29,7 @@ var (
 var configGlobalCommand = &cobra.Command{
 	Use:     "global [flags]",
 	Short:   "Change global configuration",
-	Example: "ddev config global --instrumentation-opt-in=false\nddev config global --omit-containers=ddev-ssh-agent",
+	Example: "ddev config global --instrumentation-opt-in=false\nddev config global --omit-containers=ddev-ssh-agent\nddev config global --show-origin",
 	Run:     handleGlobalConfig,
 }
 
@@ -138,6 +138,39 @@ func handleGlobalConfig(cmd *cobra.Command, _ []string) {
 		dirty = true
 	}
 
+	if cmd.Flag("xdebug-proxy").Changed {
+		globalconfig.DdevGlobalConfig.XdebugProxy, _ = cmd.Flags().GetBool("xdebug-proxy")
+		dirty = true
+	}
+
+	if cmd.Flag("idle-timeout").Changed {
+		globalconfig.DdevGlobalConfig.IdleTimeout, _ = cmd.Flags().GetString("idle-timeout")
+		dirty = true
+		// The router only reports the requests of each project after a restart
+		util.Warning("Run 'ddev poweroff' and start your projects for the change of idle_timeout to take effect")
+	}
+
+	if cmd.Flag("keep-running").Changed {
+		val, _ := cmd.Flags().GetString("keep-running")
+		val = strings.ReplaceAll(val, " ", "")
+		if val == "" || val == `""` || val == `''` {
+			globalconfig.DdevGlobalConfig.KeepRunning = nil
+		} else {
+			globalconfig.DdevGlobalConfig.KeepRunning = strings.Split(val, ",")
+		}
+		dirty = true
+	}
+
+	if cmd.Flag("idle-snapshot").Changed {
+		globalconfig.DdevGlobalConfig.IdleSnapshot, _ = cmd.Flags().GetBool("idle-snapshot")
+		dirty = true
+	}
+
+	if cmd.Flag("secrets-command").Changed {
+		globalconfig.DdevGlobalConfig.SecretsCommand, _ = cmd.Flags().GetString("secrets-command")
+		dirty = true
+	}
+
 	if cmd.Flag("router-bind-all-interfaces").Changed {
 		globalconfig.DdevGlobalConfig.RouterBindAllInterfaces, _ = cmd.Flags().GetBool("router-bind-all-interfaces")
 		dirty = true
@@ -166,6 +199,18 @@ func handleGlobalConfig(cmd *cobra.Command, _ []string) {
 		dirty = true
 	}
 
+	if cmd.Flag("instrumentation-file").Changed {
+		val, _ := cmd.Flags().GetString("instrumentation-file")
+		globalconfig.DdevGlobalConfig.InstrumentationFile = val
+		dirty = true
+	}
+
+	if cmd.Flag("instrumentation-otlp-endpoint").Changed {
+		val, _ := cmd.Flags().GetString("instrumentation-otlp-endpoint")
+		globalconfig.DdevGlobalConfig.InstrumentationOTLPEndpoint = val
+		dirty = true
+	}
+
 	if cmd.Flag("table-style").Changed {
 		val, _ := cmd.Flags().GetString("table-style")
 		if nodeps.ArrayContainsString(globalconfig.ValidTableStyleList(), val) {
@@ -257,6 +302,9 @@ func handleGlobalConfig(cmd *cobra.Command, _ []string) {
 	}
 
 	if dirty {
+		if err = globalconfig.CheckPolicyLocks(globalconfig.DdevGlobalConfig); err != nil {
+			util.Failed("Unable to change the global configuration: %v", err)
+		}
 		err = globalconfig.ValidateGlobalConfig()
 		if err != nil {
 			util.Failed("Invalid configuration in %s: %v", globalconfig.GetGlobalConfigPath(), err)
@@ -278,13 +326,22 @@ func handleGlobalConfig(cmd *cobra.Command, _ []string) {
 		tag = parts[0]
 		//name := typeOfVal.Field(i).Name
 		fieldValue := v.Field(i).Interface()
-		if tag != "build info" && tag != "web_environment" && tag != "project_info" && tag != "remote_config" && tag != "messages" && tag != "router" {
+		if tag != "build info" && tag != "web_environment" && tag != "project_info" && tag != "remote_config" && tag != "messages" && tag != "router" && tag != "instrumentation_otlp_headers" {
 			tagWithDashes := strings.ReplaceAll(tag, "_", "-")
 			valMap[tagWithDashes] = fmt.Sprintf("%v", fieldValue)
 			keys = append(keys, tagWithDashes)
 		}
 	}
 
+	// Header values are often API keys, so only show the names
+	headerNames := make([]string, 0, len(globalconfig.DdevGlobalConfig.InstrumentationOTLPHeaders))
+	for name := range globalconfig.DdevGlobalConfig.InstrumentationOTLPHeaders {
+		headerNames = append(headerNames, name)
+	}
+	sort.Strings(headerNames)
+	valMap["instrumentation-otlp-headers"] = fmt.Sprintf("%v", headerNames)
+	keys = append(keys, "instrumentation-otlp-headers")
+
 	// Add remote config URLs to the display
 	valMap["remote-config-url"] = globalconfig.DdevGlobalConfig.RemoteConfig.RemoteConfigURL
 	keys = append(keys, "remote-config-url")
@@ -296,15 +353,41 @@ func handleGlobalConfig(cmd *cobra.Command, _ []string) {
 	keys = append(keys, "remote-config-update-interval")
 
 	sort.Strings(keys)
+	showOrigin, _ := cmd.Flags().GetBool("show-origin")
+	origins := map[string]string{}
+	if showOrigin {
+		for _, label := range keys {
+			origins[label] = globalconfig.GetConfigOrigin(globalConfigKey(label)).String()
+		}
+	}
 	if !output.JSONOutput {
 		for _, label := range keys {
-			output.UserOut.Printf("%s=%v", label, valMap[label])
+			if showOrigin {
+				output.UserOut.Printf("%s\t%s=%v", origins[label], label, valMap[label])
+			} else {
+				output.UserOut.Printf("%s=%v", label, valMap[label])
+			}
 		}
+	} else if showOrigin {
+		output.UserOut.WithField("raw", valMap).WithField("origins", origins).Println("")
 	} else {
 		output.UserOut.WithField("raw", valMap).Println("")
 	}
 }
 
+// globalConfigKey returns the global config key shown as label, like
+// remote_config.addon_data_url for addon-data-url
+func globalConfigKey(label string) string {
+	key := strings.ReplaceAll(label, "-", "_")
+	switch label {
+	case "remote-config-url", "sponsorship-data-url", "addon-data-url":
+		return "remote_config." + key
+	case "remote-config-update-interval":
+		return "remote_config.update_interval"
+	}
+	return key
+}
+
 func init() {
 	configGlobalCommand.Flags().StringVarP(&omitContainers, "omit-containers", "", "", `For example, --omit-containers=ddev-ssh-agent or --omit-containers=""`)
 	_ = configGlobalCommand.RegisterFlagCompletionFunc("omit-containers", configCompletionFuncWithCommas(globalconfig.GetValidOmitContainers()))
@@ -312,6 +395,9 @@ func init() {
 	configGlobalCommand.Flags().StringVarP(&webEnvironmentGlobal, "web-environment-add", "", "", `Append environment variables to the web container: --web-environment-add="TYPO3_CONTEXT=Development,SOMEENV=someval"`)
 	configGlobalCommand.Flags().BoolVarP(&instrumentationOptIn, "instrumentation-opt-in", "", true, "Whether to allow instrumentation reporting with --instrumentation-opt-in=true")
 	_ = configGlobalCommand.RegisterFlagCompletionFunc("instrumentation-opt-in", configCompletionFunc([]string{"true", "false"}))
+	configGlobalCommand.Flags().String("instrumentation-file", "", "Append local instrumentation spans to a JSONL file, relative to the global config directory, use --instrumentation-file=\"\" to disable")
+	configGlobalCommand.Flags().String("instrumentation-otlp-endpoint", "", "Send local instrumentation spans to an OpenTelemetry collector using OTLP/HTTP, like --instrumentation-otlp-endpoint=http://localhost:4318")
+	configGlobalCommand.Flags().Bool("show-origin", false, "Show where each value comes from: DDEV's default, the user's global config, or the organization policy")
 	configGlobalCommand.Flags().Bool("router-bind-all-interfaces", false, "Bind host router ports on all interfaces, not only on the localhost network interface")
 	_ = configGlobalCommand.RegisterFlagCompletionFunc("router-bind-all-interfaces", configCompletionFunc([]string{"true", "false"}))
 	configGlobalCommand.Flags().Int("internet-detection-timeout-ms", nodeps.InternetDetectionTimeoutDefault, "Increase timeout when checking internet timeout, in milliseconds")
@@ -343,6 +429,13 @@ func init() {
 	configGlobalCommand.Flags().Bool("no-bind-mounts", false, "If true, don't use bind-mounts. Useful for environments like remote Docker where bind-mounts are impossible")
 	_ = configGlobalCommand.RegisterFlagCompletionFunc("no-bind-mounts", configCompletionFunc([]string{"true", "false"}))
 	configGlobalCommand.Flags().String("xdebug-ide-location", "", "For less usual IDE locations specify where the IDE is running for Xdebug to reach it (for advanced use only)")
+	configGlobalCommand.Flags().Bool("xdebug-proxy", false, "Route Xdebug connections through a DBGp proxy on the host, so several IDEs can debug at the same time")
+	_ = configGlobalCommand.RegisterFlagCompletionFunc("xdebug-proxy", configCompletionFunc([]string{"true", "false"}))
+	configGlobalCommand.Flags().String("idle-timeout", "", `Stop projects without HTTP requests, 'ddev exec' or 'ddev ssh' for this long, like 2h, --idle-timeout="" disables it`)
+	configGlobalCommand.Flags().String("keep-running", "", `Projects the idle manager never stops, names or patterns, for example --keep-running=important,client-* or --keep-running=""`)
+	configGlobalCommand.Flags().Bool("idle-snapshot", false, "Take a database snapshot of idle projects before the idle manager stops them")
+	_ = configGlobalCommand.RegisterFlagCompletionFunc("idle-snapshot", configCompletionFunc([]string{"true", "false"}))
+	configGlobalCommand.Flags().String("secrets-command", "", `Command printing a secret that isn't in the local store, {name} is replaced with its name, for example --secrets-command="pass show ddev/{name}"`)
 	configGlobalCommand.Flags().Bool("wsl2-no-windows-hosts-mgt", false, "WSL2 only; make DDEV ignore Windows-side hosts file (for advanced use only)")
 	configGlobalCommand.Flags().String("router-http-port", nodeps.DdevDefaultRouterHTTPPort, "The default router HTTP port for all projects, can be overridden by project configuration")
 	_ = configGlobalCommand.RegisterFlagCompletionFunc("router-http-port", configCompletionFunc([]string{nodeps.DdevDefaultRouterHTTPPort}))
@@ -234,6 +234,10 @@ func init() {
 	_ = ConfigCommand.RegisterFlagCompletionFunc("router-https-port", configCompletionFunc([]string{nodeps.DdevDefaultRouterHTTPSPort}))
 	ConfigCommand.Flags().BoolVar(&xdebugEnabledArg, "xdebug-enabled", false, "Whether Xdebug is enabled in the web container")
 	_ = ConfigCommand.RegisterFlagCompletionFunc("xdebug-enabled", configCompletionFunc([]string{"true", "false"}))
+	ConfigCommand.Flags().String(types.FlagXdebugModeName, "", types.FlagXdebugModeDescription())
+	_ = ConfigCommand.RegisterFlagCompletionFunc(types.FlagXdebugModeName, configCompletionFunc(types.ValidXdebugModeOptions()))
+	ConfigCommand.Flags().String(types.FlagXdebugStartWithRequestName, "", types.FlagXdebugStartWithRequestDescription())
+	_ = ConfigCommand.RegisterFlagCompletionFunc(types.FlagXdebugStartWithRequestName, configCompletionFunc(types.ValidXdebugStartWithRequestOptions()))
 	ConfigCommand.Flags().BoolVar(&noProjectMountArg, "no-project-mount", false, "Whether to skip mounting project code into the web container")
 	_ = ConfigCommand.RegisterFlagCompletionFunc("no-project-mount", configCompletionFunc([]string{"true", "false"}))
 	ConfigCommand.Flags().StringVar(&additionalHostnamesArg, "additional-hostnames", "", `Comma-delimited list of project hostnames or --additional-hostnames="" to remove any configured additional hostnames`)
@@ -246,6 +250,7 @@ func init() {
 	ConfigCommand.Flags().StringSlice("upload-dirs", []string{}, `Set the project's upload directories, the destination directories of the 'ddev import-files' command, or --upload-dirs="" to remove previously configured values`)
 	ConfigCommand.Flags().StringVar(&webserverTypeArg, "webserver-type", nodeps.WebserverDefault, fmt.Sprintf("Set the project's desired webserver type: %s", strings.Join(nodeps.GetValidWebserverTypes(), "/")))
 	_ = ConfigCommand.RegisterFlagCompletionFunc("webserver-type", configCompletionFunc(nodeps.GetValidWebserverTypes()))
+	ConfigCommand.Flags().String("frankenphp-worker", "", `Run FrankenPHP in worker mode with this script, relative to the project root, or --frankenphp-worker="" to disable worker mode`)
 	ConfigCommand.Flags().StringVar(&webImageArg, "web-image", "", "Set the web container image (for advanced use only)")
 	ConfigCommand.Flags().BoolVar(&webImageDefaultArg, "web-image-default", false, `Sets the default web container image, the same as --web-image=""`)
 	ConfigCommand.Flags().StringVar(&dbImageArg, "db-image", "", "Set the db container image (for advanced use only)")
@@ -312,6 +317,10 @@ func init() {
 	ConfigCommand.Flags().Bool("disable-upload-dirs-warning", false, `Disable warnings about upload-dirs not being set when using --performance-mode=mutagen`)
 	_ = ConfigCommand.RegisterFlagCompletionFunc("disable-upload-dirs-warning", configCompletionFunc([]string{"true", "false"}))
 	ConfigCommand.Flags().StringVar(&ddevVersionConstraint, "ddev-version-constraint", "", `Specify a ddev_version_constraint to validate ddev against`)
+	ConfigCommand.Flags().String("name-from", "", `Derive the project name from the git checkout, "git-branch" or "worktree", so each branch or worktree is a separate project`)
+	_ = ConfigCommand.RegisterFlagCompletionFunc("name-from", configCompletionFunc(ddevapp.ValidNameFromValues))
+	ConfigCommand.Flags().Bool("worktree-seed-db", false, `With --name-from, seed the database of a new worktree or branch from the latest snapshot of the main worktree`)
+	_ = ConfigCommand.RegisterFlagCompletionFunc("worktree-seed-db", configCompletionFunc([]string{"true", "false"}))
 	ConfigCommand.Flags().Bool("corepack-enable", false, `Whether to run 'corepack enable' on Node.js configuration`)
 	_ = ConfigCommand.RegisterFlagCompletionFunc("corepack-enable", configCompletionFunc([]string{"true", "false"}))
 	ConfigCommand.Flags().Bool("update", false, `Update project settings based on detection and project-type overrides (except for 'generic' type)`)
@@ -532,6 +541,26 @@ func handleMainConfigArgs(cmd *cobra.Command, _ []string, app *ddevapp.DdevApp)
 		app.XdebugEnabled = xdebugEnabledArg
 	}
 
+	if cmd.Flag(types.FlagXdebugModeName).Changed {
+		xdebugMode, _ := cmd.Flags().GetString(types.FlagXdebugModeName)
+
+		if err := types.CheckValidXdebugMode(xdebugMode); err != nil {
+			util.Error("%s. Not changing value of `xdebug_mode` option.", err)
+		} else {
+			app.XdebugMode = xdebugMode
+		}
+	}
+
+	if cmd.Flag(types.FlagXdebugStartWithRequestName).Changed {
+		startWithRequest, _ := cmd.Flags().GetString(types.FlagXdebugStartWithRequestName)
+
+		if err := types.CheckValidXdebugStartWithRequest(startWithRequest); err != nil {
+			util.Error("%s. Not changing value of `xdebug_start_with_request` option.", err)
+		} else {
+			app.XdebugStartWithRequest = startWithRequest
+		}
+	}
+
 	// This bool flag is false by default, so only use the value if the flag was explicitly set.
 	if cmd.Flag("no-project-mount").Changed {
 		app.NoProjectMount = noProjectMountArg
@@ -683,6 +712,14 @@ func handleMainConfigArgs(cmd *cobra.Command, _ []string, app *ddevapp.DdevApp)
 
 	if cmd.Flag("webserver-type").Changed {
 		app.WebserverType = webserverTypeArg
+		// FrankenPHP only runs its embedded PHP
+		if app.WebserverType == nodeps.WebserverFrankenPHP && !cmd.Flag("php-version").Changed {
+			app.PHPVersion = nodeps.FrankenPHPPHPVersion
+		}
+	}
+
+	if cmd.Flag("frankenphp-worker").Changed {
+		app.FrankenPHPWorker, _ = cmd.Flags().GetString("frankenphp-worker")
 	}
 
 	if cmd.Flag("web-image").Changed {
@@ -732,6 +769,17 @@ func handleMainConfigArgs(cmd *cobra.Command, _ []string, app *ddevapp.DdevApp)
 		app.DdevVersionConstraint = ddevVersionConstraint
 	}
 
+	if cmd.Flag("name-from").Changed {
+		app.NameFrom, _ = cmd.Flags().GetString("name-from")
+		if err := app.ApplyNameFrom(); err != nil {
+			return err
+		}
+	}
+
+	if cmd.Flag("worktree-seed-db").Changed {
+		app.WorktreeSeedDB, _ = cmd.Flags().GetBool("worktree-seed-db")
+	}
+
 	// Ensure the configuration passes validation before writing config file.
 	if err := app.ValidateConfig(); err != nil {
 		return fmt.Errorf("failed to validate config: %v", err)
@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/ddev/ddev/pkg/ddevapp"
+	"github.com/ddev/ddev/pkg/globalconfig"
+	"github.com/ddev/ddev/pkg/heredoc"
+	"github.com/ddev/ddev/pkg/nodeps"
+	"github.com/ddev/ddev/pkg/output"
+	"github.com/ddev/ddev/pkg/styles"
+	"github.com/ddev/ddev/pkg/util"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+)
+
+var slowlogThreshold time.Duration
+var slowlogLimit int
+var slowlogSort string
+
+// DBSlowlogCmd implements the ddev db slowlog command
+var DBSlowlogCmd = &cobra.Command{
+	ValidArgsFunction: configCompletionFunc([]string{"on", "off", "show", "status", "reset"}),
+	Use:               "slowlog [on|off|show|status|reset]",
+	Short:             "Enables, disables or shows the database slow query log",
+	Long: heredoc.DocI2S(`
+			Enables, disables or shows the slow query log of the db service.
+			On MariaDB/MySQL the slow query log is written to the mysql.slow_log table.
+			On Postgres log_min_duration_statement and pg_stat_statements are used;
+			the first 'ddev db slowlog on' may require a 'ddev restart' to load pg_stat_statements.
+			'ddev db slowlog show' prints the slowest queries, aggregated by normalized query text.
+			Use the global --json-output flag for machine-readable output.`),
+	Example: heredoc.DocI2S(`
+		ddev db slowlog on
+		ddev db slowlog on --threshold=100ms
+		ddev db slowlog show
+		ddev db slowlog show --sort=count --limit=20
+		ddev db slowlog show -j
+		ddev db slowlog reset
+		ddev db slowlog off
+	`),
+	Args: cobra.MaximumNArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		app, err := ddevapp.GetActiveApp("")
+		if err != nil {
+			util.Failed("Unable to get project: %v", err)
+		}
+		instrumentationApp = app
+
+		if nodeps.ArrayContainsString(app.GetOmittedContainers(), "db") {
+			util.Failed("Database is omitted for project %s, there is no slow query log", app.GetName())
+		}
+		if err = app.StartAppIfNotRunning(); err != nil {
+			util.Failed("Failed to start project %s: %v", app.GetName(), err)
+		}
+
+		action := "status"
+		if len(args) == 1 {
+			action = args[0]
+		}
+
+		switch action {
+		case "on", "enable", "true":
+			restartNeeded, err := ddevapp.SlowQueryLogEnable(app, slowlogThreshold)
+			if err != nil {
+				util.Failed("Failed to enable slow query log: %v", err)
+			}
+			util.Success("Enabled slow query log for queries taking longer than %v.", slowlogThreshold)
+			if restartNeeded {
+				util.Warning("Query statistics need pg_stat_statements, which is loaded after 'ddev restart'.")
+			}
+
+		case "off", "disable", "false":
+			if err = ddevapp.SlowQueryLogDisable(app); err != nil {
+				util.Failed("Failed to disable slow query log: %v", err)
+			}
+			util.Success("Disabled slow query log.")
+			if app.Database.Type == nodeps.Postgres {
+				util.Warning("pg_stat_statements stays loaded until 'ddev restart'.")
+			}
+
+		case "reset":
+			if err = ddevapp.SlowQueryLogReset(app); err != nil {
+				util.Failed("Failed to reset slow query log: %v", err)
+			}
+			util.Success("Discarded collected slow query statistics.")
+
+		case "status":
+			status, err := ddevapp.GetSlowQueryLogStatus(app)
+			if err != nil {
+				util.Failed("Failed to get slow query log status: %v", err)
+			}
+			msg := "Slow query log is disabled."
+			if status.Enabled {
+				msg = fmt.Sprintf("Slow query log is enabled for queries taking longer than %v.", status.Threshold)
+			}
+			output.UserOut.WithField("raw", status).Println(msg)
+
+		case "show":
+			digest, err := ddevapp.GetSlowQueryDigest(app, slowlogSort, slowlogLimit)
+			if err != nil {
+				util.Failed("Failed to read slow query log: %v", err)
+			}
+			renderSlowQueryDigest(digest, app.Database.Type == nodeps.Postgres)
+
+		default:
+			util.Failed("Invalid argument '%s', must be one of on, off, show, status, reset", action)
+		}
+	},
+}
+
+// renderSlowQueryDigest prints the digest as a table, or as raw JSON with --json-output.
+// Postgres counts shared blocks instead of examined rows.
+func renderSlowQueryDigest(digest []ddevapp.SlowQuery, blocks bool) {
+	if len(digest) == 0 {
+		output.UserOut.WithField("raw", digest).Println("No slow queries recorded. Use 'ddev db slowlog on' to enable the slow query log.")
+		return
+	}
+
+	var out bytes.Buffer
+	t := table.NewWriter()
+	t.SetOutputMirror(&out)
+	styles.SetGlobalTableStyle(t, false)
+	columns := table.Row{"Total", "Count", "Mean", "Max", "Rows Examined", "Rows Sent", "Query"}
+	if blocks {
+		columns[4] = "Blocks Read"
+	}
+	if !globalconfig.DdevGlobalConfig.SimpleFormatting {
+		t.SetColumnConfigs([]table.ColumnConfig{
+			{Name: "Query", WidthMax: 80},
+		})
+	}
+	t.AppendHeader(columns)
+	for _, q := range digest {
+		t.AppendRow(table.Row{
+			formatQuerySeconds(q.TotalTime),
+			q.Count,
+			formatQuerySeconds(q.MeanTime),
+			formatQuerySeconds(q.MaxTime),
+			q.RowsExamined + q.BlocksRead,
+			q.RowsSent,
+			q.Query,
+		})
+	}
+	t.Render()
+	output.UserOut.WithField("raw", digest).Println(out.String())
+}
+
+// formatQuerySeconds rounds a query duration for display.
+func formatQuerySeconds(seconds float64) string {
+	return time.Duration(seconds * float64(time.Second)).Round(time.Millisecond).String()
+}
+
+func init() {
+	DBSlowlogCmd.Flags().DurationVar(&slowlogThreshold, "threshold", ddevapp.SlowQueryLogDefaultThreshold, "Log queries taking longer than this, used with 'on'")
+	DBSlowlogCmd.Flags().IntVar(&slowlogLimit, "limit", 10, "Number of queries to show, 0 for all, used with 'show'")
+	DBSlowlogCmd.Flags().StringVar(&slowlogSort, "sort", "time", "Sort by total time, count or rows examined, blocks read on Postgres (time|count|rows), used with 'show'")
+	_ = DBSlowlogCmd.RegisterFlagCompletionFunc("sort", configCompletionFunc([]string{"time", "count", "rows"}))
+	DBCmd.AddCommand(DBSlowlogCmd)
+}
@@ -0,0 +1,20 @@
+package cmd
+
+import (
+	"github.com/ddev/ddev/pkg/util"
+	"github.com/spf13/cobra"
+)
+
+// DBCmd is the top-level "ddev db" command
+var DBCmd = &cobra.Command{
+	Use:   "db [command]",
+	Short: "Commands for inspecting the project database server",
+	Run: func(cmd *cobra.Command, _ []string) {
+		err := cmd.Usage()
+		util.CheckErr(err)
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(DBCmd)
+}
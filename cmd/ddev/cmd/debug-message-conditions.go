@@ -1,7 +1,13 @@
 package cmd
 
 import (
+	"path"
+	"strings"
+
 	"github.com/ddev/ddev/pkg/config/remoteconfig"
+	"github.com/ddev/ddev/pkg/config/remoteconfig/types"
+	"github.com/ddev/ddev/pkg/config/state/storage/yaml"
+	"github.com/ddev/ddev/pkg/globalconfig"
 	"github.com/ddev/ddev/pkg/output"
 	"github.com/ddev/ddev/pkg/styles"
 	"github.com/jedib0t/go-pretty/v6/table"
@@ -10,9 +16,27 @@ import (
 
 // DebugMessageConditionsCmd implements the ddev utility message-conditions command
 var DebugMessageConditionsCmd = &cobra.Command{
-	Use:   "message-conditions",
-	Short: "Show message conditions of this version of ddev",
-	Run: func(_ *cobra.Command, _ []string) {
+	Use:   "message-conditions [condition ...]",
+	Short: "Show message conditions of this version of ddev, or test conditions and messages",
+	Long: `Show message conditions of this version of ddev.
+
+With conditions as arguments, evaluate them in the current directory, so
+conditions about the project, like ProjectType:drupal11, can be tested.
+With --messages, show the messages of the remote config and of the extra
+sources in the global "messages.sources" and whether each would be shown.`,
+	Example: `ddev utility message-conditions
+ddev utility message-conditions WSL2 '!Colima' ProjectType:drupal11 Addon:ddev/ddev-redis@<2.1.0
+ddev utility message-conditions --messages`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if cmd.Flag("messages").Changed {
+			showMessages()
+			return
+		}
+		if len(args) > 0 {
+			checkMessageConditions(args)
+			return
+		}
+
 		conditions := remoteconfig.ListConditions()
 
 		t := table.NewWriter()
@@ -33,6 +57,83 @@ var DebugMessageConditionsCmd = &cobra.Command{
 	Hidden: true,
 }
 
+// conditionResult is the result of a condition for the raw output
+type conditionResult struct {
+	Condition string `json:"condition"`
+	Result    bool   `json:"result"`
+	Known     bool   `json:"known"`
+}
+
+// checkMessageConditions shows the result of each condition
+func checkMessageConditions(conditions []string) {
+	t := table.NewWriter()
+	styles.SetGlobalTableStyle(t, false)
+	t.AppendHeader(table.Row{"Condition", "Result"})
+
+	var results []conditionResult
+	for _, condition := range conditions {
+		result, known := remoteconfig.CheckCondition(condition)
+		results = append(results, conditionResult{Condition: condition, Result: result, Known: known})
+		status := "false"
+		if result {
+			status = "true"
+		}
+		if !known {
+			status += " (unknown condition)"
+		}
+		t.AppendRow(table.Row{condition, status})
+	}
+
+	output.UserOut.WithField("raw", results).Print(t.Render())
+}
+
+// showMessages shows the messages of every message source
+func showMessages() {
+	state := yaml.NewState(path.Join(globalconfig.GetGlobalDdevDir(), ".state.yaml"))
+	rc := remoteconfig.New(
+		&remoteconfig.Config{
+			Local: remoteconfig.Local{
+				Path: globalconfig.GetGlobalDdevDir(),
+			},
+			URL:            globalconfig.DdevGlobalConfig.RemoteConfig.RemoteConfigURL,
+			UpdateInterval: globalconfig.DdevGlobalConfig.RemoteConfig.UpdateInterval,
+			TickerInterval: globalconfig.DdevGlobalConfig.Messages.TickerInterval,
+			Sources:        globalconfig.DdevGlobalConfig.Messages.Sources,
+		},
+		state,
+		globalconfig.IsInternetActive,
+	)
+	sources := rc.GetMessageSources()
+
+	t := table.NewWriter()
+	styles.SetGlobalTableStyle(t, false)
+	t.AppendHeader(table.Row{"Source", "Type", "Message", "Conditions", "Versions", "Shown"})
+	t.SetColumnConfigs([]table.ColumnConfig{{Name: "Message", WidthMax: 60}})
+
+	for _, source := range sources {
+		messageTypes := []struct {
+			name     string
+			messages []types.Message
+		}{
+			{"info", source.Messages.Notifications.Infos},
+			{"warning", source.Messages.Notifications.Warnings},
+			{"ticker", source.Messages.Ticker.Messages},
+		}
+		for _, messageType := range messageTypes {
+			for _, message := range messageType.messages {
+				shown := "no"
+				if remoteconfig.MessageApplies(message) {
+					shown = "yes"
+				}
+				t.AppendRow(table.Row{source.Location, messageType.name, message.Message, strings.Join(message.Conditions, ", "), message.Versions, shown})
+			}
+		}
+	}
+
+	output.UserOut.WithField("raw", sources).Print(t.Render())
+}
+
 func init() {
+	DebugMessageConditionsCmd.Flags().Bool("messages", false, "Show the messages of all message sources and whether each would be shown")
 	DebugCmd.AddCommand(DebugMessageConditionsCmd)
 }
@@ -2,11 +2,17 @@ package cmd
 
 import (
 	"errors"
+	"fmt"
+	"maps"
 	"os"
+	"slices"
 	"strings"
 
+	"github.com/ddev/ddev/pkg/config/types"
 	"github.com/ddev/ddev/pkg/ddevapp"
+	"github.com/ddev/ddev/pkg/idlemanager"
 	"github.com/ddev/ddev/pkg/output"
+	"github.com/ddev/ddev/pkg/secrets"
 	"github.com/ddev/ddev/pkg/util"
 	"github.com/docker/cli/cli"
 	"github.com/spf13/cobra"
@@ -27,7 +33,13 @@ ddev exec -s db
 ddev exec -s solr (assuming an add-on service named 'solr')
 ddev exec -p my-project -s db (assuming a project exists named 'my-project')
 ddev exec --raw -- ls -lR
-ddev exec -s db -u root ls -la /root`,
+ddev exec -s db -u root ls -la /root
+ddev exec --php=7.4 php -v (assuming php_pools includes PHP 7.4)
+ddev exec --xdebug drush cr
+ddev exec --xdebug=profile php script.php
+ddev exec -e APP_ENV=test -e HOME php bin/console cache:clear
+ddev exec --env-file .env.testing vendor/bin/phpunit
+ddev exec -e API_TOKEN=secret:api-token ./deploy.sh`,
 	Run: func(cmd *cobra.Command, args []string) {
 		activeApp, err := cmd.Flags().GetString("project")
 		if err != nil {
@@ -59,6 +71,18 @@ ddev exec -s db -u root ls -la /root`,
 
 		_ = app.DockerEnv()
 
+		phpVersion, _ := cmd.Flags().GetString("php")
+		phpBinDir, err := app.GetPHPBinDir(phpVersion)
+		if err != nil {
+			util.Failed("Failed to exec command: %v", err)
+		}
+		if phpBinDir != "" && serviceType != "web" {
+			util.Failed("The --php flag can only be used with the web service")
+		}
+		if cmd.Flag("xdebug").Changed && serviceType != "web" {
+			util.Failed("The --xdebug flag can only be used with the web service")
+		}
+
 		opts := &ddevapp.ExecOpts{
 			Service: serviceType,
 			Dir:     execDirArg,
@@ -69,7 +93,8 @@ ddev exec -s db -u root ls -la /root`,
 
 		// If they've chosen raw, use the actual passed values.
 		// Also, retrieve and preserve the current $PATH to ensure the environment is consistent.
-		if cmd.Flag("raw").Changed {
+		// With --php, prepend the directory of that PHP version to $PATH.
+		if cmd.Flag("raw").Changed || phpBinDir != "" {
 			var env []string
 			path, _, err := app.Exec(&ddevapp.ExecOpts{
 				Service: serviceType,
@@ -78,14 +103,35 @@ ddev exec -s db -u root ls -la /root`,
 			})
 			path = strings.Trim(path, "\n")
 			if err == nil && path != "" {
+				if phpBinDir != "" {
+					path = phpBinDir + ":" + path
+				}
 				env = append(env, "PATH="+path)
 			}
+			opts.Env = env
+		}
+		// With --xdebug, run only this command with Xdebug
+		if cmd.Flag("xdebug").Changed {
+			xdebugMode, _ := cmd.Flags().GetString("xdebug")
+			xdebugEnv, err := app.XdebugExecEnv(xdebugMode, phpVersion)
+			if err != nil {
+				util.Failed("Failed to exec command: %v", err)
+			}
+			opts.Env = append(opts.Env, xdebugEnv...)
+		}
+		userEnv, err := execEnvFromFlags(cmd)
+		if err != nil {
+			util.Failed("Failed to exec command: %v", err)
+		}
+		opts.Env = append(opts.Env, userEnv...)
+		if cmd.Flag("raw").Changed {
 			// opts.RawCmd is used instead of opts.Cmd
 			opts.RawCmd = args
-			opts.Env = env
 		}
 
+		doneActive := idlemanager.KeepActive(app.Name)
 		_, _, err = app.Exec(opts)
+		doneActive()
 		quiet, _ := cmd.Flags().GetBool("quiet")
 
 		if err != nil {
@@ -124,14 +170,58 @@ func quoteArgs(args []string) string {
 	return b.String()
 }
 
+// execEnvFromFlags returns the environment variables given with --env-file
+// and -e, in this order, with the references to secrets resolved. Like with
+// 'docker exec', -e KEY without a value uses the value of KEY on the host.
+func execEnvFromFlags(cmd *cobra.Command) ([]string, error) {
+	var env []string
+	envFiles, _ := cmd.Flags().GetStringArray("env-file")
+	for _, envFile := range envFiles {
+		envMap, _, err := ddevapp.ReadProjectEnvFile(envFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read env file %s: %v", envFile, err)
+		}
+		for _, k := range slices.Sorted(maps.Keys(envMap)) {
+			env = append(env, k+"="+envMap[k])
+		}
+	}
+	envVars, _ := cmd.Flags().GetStringArray("env")
+	for _, e := range envVars {
+		key, _, hasValue := strings.Cut(e, "=")
+		if key == "" {
+			return nil, fmt.Errorf("invalid environment variable '%s', use KEY=VALUE or KEY", e)
+		}
+		if !hasValue {
+			value, ok := os.LookupEnv(key)
+			if !ok {
+				continue
+			}
+			e = key + "=" + value
+		}
+		env = append(env, e)
+	}
+	return secrets.ResolveEnv(env)
+}
+
+// addExecEnvFlags adds the -e and --env-file flags to cmd
+func addExecEnvFlags(cmd *cobra.Command) {
+	cmd.Flags().StringArrayP("env", "e", nil, "Set an environment variable, KEY=VALUE, KEY=secret:NAME or KEY to use the value from the host; can be repeated")
+	cmd.Flags().StringArray("env-file", nil, "Read environment variables from a file in .env format; can be repeated")
+}
+
 func init() {
 	DdevExecCmd.Flags().StringVarP(&serviceType, "service", "s", "web", "Define the service to connect to. [e.g. web, db]")
 	_ = DdevExecCmd.RegisterFlagCompletionFunc("service", ddevapp.GetServiceNamesFunc(true))
 	DdevExecCmd.Flags().StringVarP(&execDirArg, "dir", "d", "", "Define the execution directory within the container")
 	DdevExecCmd.Flags().Bool("raw", true, "Use raw exec (do not interpret with Bash inside container)")
 	DdevExecCmd.Flags().BoolP("quiet", "q", false, "Suppress detailed error output")
+	DdevExecCmd.Flags().String("php", "", "Run the command with this PHP version from php_version or php_pools [e.g. 7.4]")
+	DdevExecCmd.Flags().String("xdebug", "", "Run the command with Xdebug in this mode, debug if no mode is given [e.g. --xdebug=profile]")
+	DdevExecCmd.Flags().Lookup("xdebug").NoOptDefVal = "debug"
+	_ = DdevExecCmd.RegisterFlagCompletionFunc("xdebug", configCompletionFunc(types.ValidXdebugModeOptions()))
 	DdevExecCmd.Flags().StringVarP(&serviceUser, "user", "u", "", "Defines the user to use within the container")
 	DdevExecCmd.Flags().StringP("project", "p", "", "Project to use, defaults to the one for the current directory")
+	addExecEnvFlags(DdevExecCmd)
 	_ = DdevExecCmd.RegisterFlagCompletionFunc("project", ddevapp.GetProjectNamesFunc("all", 0))
 	// This requires flags for exec to be specified prior to any arguments, allowing for
 	// flags to be ignored by cobra for commands that are to be executed in a container.
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ddev/ddev/pkg/ddevapp"
+	"github.com/ddev/ddev/pkg/output"
+	"github.com/ddev/ddev/pkg/util"
+	"github.com/spf13/cobra"
+)
+
+// HealthCmd implements the ddev health command
+var HealthCmd = &cobra.Command{
+	ValidArgsFunction: ddevapp.GetProjectNamesFunc("active", 1),
+	Use:               "health [projectname]",
+	Short:             "Run the HTTP health checks of a project",
+	Long: `Request the URLs of the 'health_checks' in .ddev/config.yaml and check their status code and
+body, showing a snippet of the response of the failing ones. The same checks run at the end of
+'ddev start'.`,
+	Example: `ddev health
+ddev health my-project`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		apps, err := getRequestedProjects(args, false)
+		if err != nil {
+			util.Failed("Failed to get project: %v", err)
+		}
+		app := apps[0]
+		if err = app.ValidateConfig(); err != nil {
+			util.Failed("Invalid configuration of %s: %v", app.Name, err)
+		}
+		if len(app.HealthChecks) == 0 {
+			util.Failed("Project %s has no health_checks in its configuration", app.Name)
+		}
+		if status, _ := app.SiteStatus(); status != ddevapp.SiteRunning {
+			util.Failed("Project %s isn't running, start it with 'ddev start %s'", app.Name, app.Name)
+		}
+
+		results := app.RunHealthChecks(ddevapp.HealthCheckDefaultTimeout * time.Second)
+		var raw []map[string]any
+		var lines []string
+		for _, r := range results {
+			result := map[string]any{"url": r.URL, "status": r.Status, "passed": r.Err == nil}
+			if r.Err != nil {
+				result["error"] = r.Err.Error()
+				result["snippet"] = r.Snippet
+			} else {
+				lines = append(lines, fmt.Sprintf("%s: OK (%d)", r.URL, r.Status))
+			}
+			raw = append(raw, result)
+		}
+		if failures := ddevapp.FormatHealthCheckFailures(results); failures != "" {
+			lines = append(lines, failures)
+			output.UserOut.WithField("raw", raw).Print(strings.Join(lines, "\n"))
+			util.Failed("Health checks of %s failed", app.Name)
+		}
+		output.UserOut.WithField("raw", raw).Print(strings.Join(lines, "\n"))
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(HealthCmd)
+}
@@ -1,7 +1,12 @@
 package cmd
 
 import (
+	"fmt"
+	"slices"
+	"strings"
+
 	"github.com/ddev/ddev/pkg/ddevapp"
+	"github.com/ddev/ddev/pkg/util"
 	"github.com/spf13/cobra"
 )
 
@@ -17,8 +22,16 @@ var ListCmd = &cobra.Command{
 ddev list --active-only
 ddev list -A
 ddev list --type=cakephp
-ddev list -t typo3`,
-	Run: func(_ *cobra.Command, _ []string) {
+ddev list -t typo3
+ddev list --resources
+ddev list --resources --sort memory`,
+	Run: func(cmd *cobra.Command, _ []string) {
+		if cmd.Flags().Changed("sort") && !listCommandSettings.Resources {
+			util.Failed("--sort can only be used with --resources")
+		}
+		if !slices.Contains(ddevapp.ResourceSortKeys, listCommandSettings.SortBy) {
+			util.Failed("Invalid --sort '%s', valid values are %s", listCommandSettings.SortBy, strings.Join(ddevapp.ResourceSortKeys, ", "))
+		}
 		ddevapp.List(listCommandSettings)
 	},
 }
@@ -29,6 +42,9 @@ func init() {
 	ListCmd.Flags().BoolVarP(&listCommandSettings.WrapTableText, "wrap-table", "W", false, "Display table with wrapped text if required.")
 	ListCmd.Flags().StringVarP(&listCommandSettings.TypeFilter, "type", "t", "", "Show only projects of this type")
 	ListCmd.Flags().IntVarP(&listCommandSettings.ContinuousSleepTime, "continuous-sleep-interval", "I", 1, "Time in seconds between ddev list --continuous output lists.")
+	ListCmd.Flags().BoolVarP(&listCommandSettings.Resources, "resources", "", false, "Show the CPU, memory and disk use of projects and their services.")
+	ListCmd.Flags().StringVarP(&listCommandSettings.SortBy, "sort", "", "name", fmt.Sprintf("Sort the --resources list by %s.", strings.Join(ddevapp.ResourceSortKeys, ", ")))
+	_ = ListCmd.RegisterFlagCompletionFunc("sort", configCompletionFunc(ddevapp.ResourceSortKeys))
 
 	RootCmd.AddCommand(ListCmd)
 }
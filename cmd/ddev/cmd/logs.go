@@ -1,27 +1,54 @@
 package cmd
 
 import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
 	"github.com/ddev/ddev/pkg/ddevapp"
+	"github.com/ddev/ddev/pkg/output"
 	"github.com/ddev/ddev/pkg/util"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/mattn/go-isatty"
 	"github.com/spf13/cobra"
 )
 
 var (
-	tail      string
-	follow    bool
-	timestamp bool
+	tail           string
+	follow         bool
+	timestamp      bool
+	logServices    []string
+	logAllServices bool
+	logSince       string
+	logUntil       string
+	logGrep        string
 )
 
+// logTimestampFormat is the fixed width format docker uses for log timestamps
+const logTimestampFormat = "2006-01-02T15:04:05.000000000Z07:00"
+
+// logServiceColors are the colors of the service prefixes, in turn
+var logServiceColors = []text.Color{text.FgCyan, text.FgGreen, text.FgMagenta, text.FgYellow, text.FgBlue, text.FgHiCyan, text.FgHiGreen, text.FgHiMagenta}
+
 // DdevLogsCmd contains the "ddev logs" command
 var DdevLogsCmd = &cobra.Command{
 	ValidArgsFunction: ddevapp.GetProjectNamesFunc("active", 1),
 	Use:               "logs [projectname]",
 	Short:             "Get the logs from your running services.",
-	Long:              `Uses 'docker logs' to display stdout from the running services.`,
+	Long: `Uses 'docker logs' to display stdout and stderr from the running services.
+With several services, or --all, the lines are interleaved by timestamp and
+prefixed with the service name. With --json-output every line is a JSON object
+with service, stream, timestamp and message fields.`,
 	Example: `ddev logs
 ddev logs -f
 ddev logs -s db
-ddev logs -s db [projectname]`,
+ddev logs -s db [projectname]
+ddev logs -s web,db --since 10m
+ddev logs --all -f --grep 'error|warning'
+ddev logs --all --since 2024-01-02T15:00:00 --until 2024-01-02T16:00:00 -j`,
 	Run: func(_ *cobra.Command, args []string) {
 		if len(args) > 1 {
 			util.Failed("Too many arguments provided. Please use 'ddev logs' or 'ddev logs [projectname]'")
@@ -33,34 +60,76 @@ ddev logs -s db [projectname]`,
 		}
 		project := projects[0]
 
-		err = project.Logs(serviceType, follow, timestamp, tail)
+		opts := ddevapp.LogsOptions{
+			Services: logServices,
+			Follow:   follow,
+			Tail:     tail,
+			Since:    logSince,
+			Until:    logUntil,
+		}
+		if logAllServices {
+			opts.Services, err = project.LogServices()
+			if err != nil {
+				util.Failed("Failed to list the services of %s: %v", project.GetName(), err)
+			}
+		}
+		if logGrep != "" {
+			opts.Grep, err = regexp.Compile(logGrep)
+			if err != nil {
+				util.Failed("Invalid --grep expression: %v", err)
+			}
+		}
+
+		err = project.StreamLogs(opts, logPrinter(opts.Services))
 		if err != nil {
 			util.Failed("Failed to retrieve logs for %s: %v", project.GetName(), err)
 		}
 	},
 }
 
+// logPrinter returns the function that writes a log line as JSON, or as
+// text prefixed with the service name when there are several services
+func logPrinter(services []string) func(ddevapp.LogEntry) {
+	if output.JSONOutput {
+		encoder := json.NewEncoder(output.UserOut.Out)
+		return func(entry ddevapp.LogEntry) {
+			_ = encoder.Encode(entry)
+		}
+	}
+
+	colors := output.ColorsEnabled() && isatty.IsTerminal(os.Stdout.Fd())
+	width := 0
+	for _, service := range services {
+		width = max(width, len(service))
+	}
+	return func(entry ddevapp.LogEntry) {
+		var line strings.Builder
+		if len(services) > 1 {
+			prefix := fmt.Sprintf("%-*s | ", width, entry.Service)
+			if colors {
+				prefix = logServiceColors[slices.Index(services, entry.Service)%len(logServiceColors)].Sprint(prefix)
+			}
+			line.WriteString(prefix)
+		}
+		if timestamp && !entry.Timestamp.IsZero() {
+			line.WriteString(entry.Timestamp.Format(logTimestampFormat) + " ")
+		}
+		line.WriteString(entry.Message)
+		_, _ = fmt.Fprintln(output.UserOut.Out, line.String())
+	}
+}
+
 func init() {
 	DdevLogsCmd.Flags().BoolVarP(&follow, "follow", "f", false, "Follow the logs in real time.")
 	DdevLogsCmd.Flags().BoolVarP(&timestamp, "time", "t", false, "Add timestamps to logs")
-	DdevLogsCmd.Flags().StringVarP(&serviceType, "service", "s", "web", "Defines the service to retrieve logs from. [e.g. web, db]")
+	DdevLogsCmd.Flags().StringSliceVarP(&logServices, "service", "s", []string{"web"}, "Defines the services to retrieve logs from, comma-separated or repeated. [e.g. web, db]")
 	_ = DdevLogsCmd.RegisterFlagCompletionFunc("service", ddevapp.GetServiceNamesFunc(true))
+	DdevLogsCmd.Flags().BoolVarP(&logAllServices, "all", "a", false, "Retrieve logs from all the services of the project")
+	DdevLogsCmd.MarkFlagsMutuallyExclusive("service", "all")
 	DdevLogsCmd.Flags().StringVarP(&tail, "tail", "", "", "How many lines to show")
-
-	// JSON formatted output isn't supported, so we should hide the global --json-output flag
-	// We have to do that within the help function, since the global flag hasn't been added yet during init()
-	originalHelpFunc := DdevLogsCmd.HelpFunc()
-	DdevLogsCmd.SetHelpFunc(func(command *cobra.Command, strings []string) {
-		_ = command.Flags().MarkHidden("json-output")
-		originalHelpFunc(command, strings)
-	})
-	// We need to do the same with the flag error function in case flags can't be parsed correctly,
-	// because that output (although identical to the help function) is generated separately.
-	originalErrorFunc := DdevLogsCmd.FlagErrorFunc()
-	DdevLogsCmd.SetFlagErrorFunc(func(command *cobra.Command, err error) error {
-		_ = command.Flags().MarkHidden("json-output")
-		return originalErrorFunc(command, err)
-	})
+	DdevLogsCmd.Flags().StringVarP(&logSince, "since", "", "", "Show logs since a timestamp (e.g. 2024-01-02T15:04:05) or a relative time (e.g. 10m)")
+	DdevLogsCmd.Flags().StringVarP(&logUntil, "until", "", "", "Show logs before a timestamp (e.g. 2024-01-02T15:04:05) or a relative time (e.g. 10m)")
+	DdevLogsCmd.Flags().StringVarP(&logGrep, "grep", "", "", "Show only the lines matching a regular expression")
 
 	RootCmd.AddCommand(DdevLogsCmd)
 }
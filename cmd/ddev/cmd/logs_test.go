@@ -1,8 +1,10 @@
 package cmd
 
 import (
+	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -71,4 +73,24 @@ func TestCmdLogs(t *testing.T) {
 	testcommon.CheckGoroutineOutput(t, out)
 	assert.Contains(string(out), "Server started")
 	assert.Contains(string(out), "Notice to demonstrate logging", "PHP notice not found for project %s output='%s", site.Name, string(out))
+
+	// Several services with a filter, as JSON lines
+	out, err = exec.RunHostCommand(DdevBin, "logs", "-s", "web,db", "--grep", "Notice to demonstrate", "-j")
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	require.NotEmpty(t, lines)
+	for _, line := range lines {
+		var entry ddevapp.LogEntry
+		err = json.Unmarshal([]byte(line), &entry)
+		require.NoError(t, err, "line isn't JSON: %s", line)
+		assert.Equal("web", entry.Service)
+		assert.Contains(entry.Message, "Notice to demonstrate logging")
+		assert.False(entry.Timestamp.IsZero())
+	}
+
+	// Every service is prefixed with its name
+	out, err = exec.RunHostCommand(DdevBin, "logs", "--all", "--tail", "10")
+	require.NoError(t, err)
+	assert.Regexp(`(?m)^web +\| `, out)
+	assert.Regexp(`(?m)^db +\| `, out)
 }
@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ddev/ddev/pkg/ddevapp"
+	"github.com/ddev/ddev/pkg/output"
+	"github.com/ddev/ddev/pkg/util"
+	"github.com/spf13/cobra"
+)
+
+// MutagenConflictsCmd implements the ddev mutagen conflicts command
+var MutagenConflictsCmd = &cobra.Command{
+	ValidArgsFunction: ddevapp.GetProjectNamesFunc("all", 1),
+	Use:               "conflicts",
+	Short:             "Shows the paths changed on both the host and the container",
+	Long: `Shows each path with a Mutagen sync conflict, with the state and the first lines of the host and container versions.
+Use 'ddev mutagen resolve <path> --keep host|container' to resolve a conflict without resetting Mutagen.`,
+	Example: `"ddev mutagen conflicts", "ddev mutagen conflicts <projectname>", "ddev mutagen conflicts -j"`,
+	Args:    cobra.MaximumNArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		projectName := ""
+		if len(args) == 1 {
+			projectName = args[0]
+		}
+
+		app, err := ddevapp.GetActiveApp(projectName)
+		if err != nil {
+			util.Failed("Failed to get active project: %v", err)
+		}
+		if !(app.IsMutagenEnabled()) {
+			util.Warning("Mutagen is not enabled on project %s", app.Name)
+			return
+		}
+		conflicts, err := app.MutagenConflicts()
+		if err != nil {
+			util.Failed("Unable to get Mutagen conflicts for project %s: %v", app.Name, err)
+		}
+		for i := range conflicts {
+			if err = app.MutagenConflictDetails(&conflicts[i]); err != nil {
+				util.Warning("Unable to read %s: %v", conflicts[i].Path, err)
+			}
+		}
+		if len(conflicts) == 0 {
+			output.UserOut.WithField("raw", conflicts).Printf("Project %s has no Mutagen sync conflicts.", app.Name)
+			return
+		}
+
+		var out strings.Builder
+		fmt.Fprintf(&out, "Project %s has %d Mutagen sync conflicts:\n", app.Name, len(conflicts))
+		for _, conflict := range conflicts {
+			fmt.Fprintf(&out, "\n%s\n", conflict.Path)
+			for _, side := range []struct {
+				name string
+				side ddevapp.MutagenConflictSide
+			}{{"host", conflict.Host}, {"container", conflict.Container}} {
+				if !side.side.Exists {
+					fmt.Fprintf(&out, "  %s: deleted\n", side.name)
+					continue
+				}
+				fmt.Fprintf(&out, "  %s: %s, %s, modified %s\n", side.name, side.side.Kind, util.FormatBytes(side.side.Size), side.side.ModTime.Format("2006-01-02 15:04:05"))
+				if side.side.Preview != "" {
+					fmt.Fprintf(&out, "    %s\n", strings.ReplaceAll(side.side.Preview, "\n", "\n    "))
+				}
+			}
+		}
+		fmt.Fprintf(&out, "\nResolve with 'ddev mutagen resolve <path> --keep %s' or '--keep %s'", ddevapp.MutagenKeepHost, ddevapp.MutagenKeepContainer)
+		output.UserOut.WithField("raw", conflicts).Print(out.String())
+	},
+}
+
+// MutagenResolveCmd implements the ddev mutagen resolve command
+var MutagenResolveCmd = &cobra.Command{
+	Use:   "resolve <path>",
+	Short: "Resolves a Mutagen sync conflict by keeping the host or the container version",
+	Long: `Resolves the Mutagen sync conflict of a path, relative to the project root, by copying the host or the container version
+to the other side, or deleting it there if the kept version was deleted, then syncing. The other version is lost.`,
+	Example: `"ddev mutagen resolve web/index.php --keep host", "ddev mutagen resolve var/cache --keep container"`,
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		keep, _ := cmd.Flags().GetString("keep")
+		if keep == "" {
+			util.Failed("Use --keep %s or --keep %s to choose the version to keep", ddevapp.MutagenKeepHost, ddevapp.MutagenKeepContainer)
+		}
+
+		app, err := ddevapp.GetActiveApp("")
+		if err != nil {
+			util.Failed("Failed to get active project: %v", err)
+		}
+		if !(app.IsMutagenEnabled()) {
+			util.Failed("Mutagen is not enabled on project %s", app.Name)
+		}
+		if err = app.ResolveMutagenConflict(args[0], keep); err != nil {
+			util.Failed("Unable to resolve the conflict of %s: %v", args[0], err)
+		}
+		util.Success("Resolved the conflict of %s with the %s version.", args[0], keep)
+	},
+}
+
+func init() {
+	MutagenCmd.AddCommand(MutagenConflictsCmd)
+	MutagenResolveCmd.Flags().String("keep", "", fmt.Sprintf("The version to keep, %s or %s", ddevapp.MutagenKeepHost, ddevapp.MutagenKeepContainer))
+	_ = MutagenResolveCmd.RegisterFlagCompletionFunc("keep", configCompletionFunc([]string{ddevapp.MutagenKeepHost, ddevapp.MutagenKeepContainer}))
+	MutagenCmd.AddCommand(MutagenResolveCmd)
+}
@@ -0,0 +1,210 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ddev/ddev/pkg/ddevapp"
+	ddevexec "github.com/ddev/ddev/pkg/exec"
+	"github.com/ddev/ddev/pkg/globalconfig"
+	"github.com/ddev/ddev/pkg/output"
+	"github.com/ddev/ddev/pkg/util"
+	"github.com/spf13/cobra"
+)
+
+// NewCmd implements the ddev new command
+var NewCmd = &cobra.Command{
+	Use:   "new <template> <directory>",
+	Short: "Create a project from a template",
+	Long: `Create a project in a new or empty directory from a template, a local directory or a git repository
+with a ` + ddevapp.ProjectTemplateFileName + ` manifest. The template configures the project, installs add-ons,
+creates the code with Composer, git or a command, imports a database or snapshot and runs post-create commands.
+A GitHub repository can be given as owner/repo.`,
+	Example: `ddev new my-org/drupal-starter my-site
+ddev new https://gitlab.example.com/templates/laravel.git shop
+ddev new ~/templates/wordpress blog --project-name=my-blog`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		verbose, _ := cmd.Flags().GetBool("verbose")
+		projectName, _ := cmd.Flags().GetString("project-name")
+		if err := newProject(args[0], args[1], projectName, verbose); err != nil {
+			util.Failed("%v", err)
+		}
+	},
+}
+
+// newProject creates a project in dir from a template, and removes what it
+// created if it fails
+func newProject(source string, dir string, projectName string, verbose bool) (err error) {
+	templateDir, cleanup, err := ddevapp.FetchProjectTemplate(source)
+	if err != nil {
+		return fmt.Errorf("unable to get template %s: %v", source, err)
+	}
+	defer cleanup()
+	t, err := ddevapp.ReadProjectTemplate(templateDir)
+	if err != nil {
+		return fmt.Errorf("unable to use template %s: %v", source, err)
+	}
+	if t.DdevVersionConstraint != "" {
+		if err = ddevapp.CheckDdevVersionConstraint(t.DdevVersionConstraint, fmt.Sprintf("Unable to use the '%s' template", t.Name), ""); err != nil {
+			return err
+		}
+	}
+
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return fmt.Errorf("unable to get the absolute path of %s: %v", dir, err)
+	}
+	dir = absDir
+	_, statErr := os.Stat(dir)
+	dirExisted := statErr == nil
+	if entries, err := os.ReadDir(dir); err == nil && len(entries) > 0 {
+		return fmt.Errorf("the directory %s isn't empty, 'ddev new' needs a new or empty directory", dir)
+	}
+	if projectName == "" {
+		projectName = ddevapp.NormalizeProjectName(filepath.Base(dir))
+	}
+	if p := globalconfig.GetProject(projectName); p != nil {
+		return fmt.Errorf("a project named '%s' already exists in %s, use --project-name to choose another name", projectName, p.AppRoot)
+	}
+	if err = ddevapp.HasAllowedLocation(&ddevapp.DdevApp{AppRoot: dir}); err != nil {
+		return fmt.Errorf("unable to create a project in %s: %v", dir, err)
+	}
+
+	// From here on, remove the project and the directory if anything fails
+	origDir, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	var startedApp *ddevapp.DdevApp
+	defer func() {
+		if err == nil {
+			return
+		}
+		util.Warning("Removing the project %s in %s after the failure", projectName, dir)
+		if startedApp != nil {
+			if stopErr := startedApp.Stop(true, false); stopErr != nil {
+				util.Warning("Unable to remove the containers and volumes of %s: %v", projectName, stopErr)
+			}
+		}
+		_ = globalconfig.RemoveProjectInfo(projectName)
+		_ = os.Chdir(origDir)
+		if dirExisted {
+			entries, _ := os.ReadDir(dir)
+			for _, entry := range entries {
+				_ = os.RemoveAll(filepath.Join(dir, entry.Name()))
+			}
+		} else {
+			_ = os.RemoveAll(dir)
+		}
+	}()
+
+	util.Success("Creating project %s in %s from template %s", projectName, dir, t.Name)
+	if t.Create.Git != "" {
+		out, err := ddevexec.RunHostCommand("git", "clone", "--quiet", t.Create.Git, dir)
+		if err != nil {
+			return fmt.Errorf("unable to clone %s: %v, output=%s", t.Create.Git, err, strings.TrimSpace(out))
+		}
+	} else if err = os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("unable to create %s: %v", dir, err)
+	}
+	if err = os.Chdir(dir); err != nil {
+		return fmt.Errorf("unable to change directory to %s: %v", dir, err)
+	}
+	// The other files are copied once the code is created, 'ddev composer create-project' needs an empty directory
+	if err = t.CopyFiles(dir, ".ddev"); err != nil {
+		return fmt.Errorf("unable to copy the .ddev files of the template: %v", err)
+	}
+
+	app, err := ddevapp.NewApp(dir, false)
+	if err != nil {
+		return fmt.Errorf("unable to create the project configuration: %v", err)
+	}
+	app.Name = projectName
+	app.Type = app.DetectAppType()
+	app.Docroot = ddevapp.DiscoverDefaultDocroot(app)
+	if err = t.ApplyConfig(app); err != nil {
+		return fmt.Errorf("unable to apply the configuration of the template: %v", err)
+	}
+	if err = app.CreateDocroot(); err != nil {
+		return fmt.Errorf("could not create docroot at %s: %v", app.GetAbsDocroot(false), err)
+	}
+	if err = app.WriteConfig(); err != nil {
+		return fmt.Errorf("failed to write config: %v", err)
+	}
+
+	for _, addOn := range t.AddOns {
+		util.Success("Installing add-on %s", addOn)
+		if err = ddevapp.InstallAddonFromGitHub(app, addOn, "", verbose); err != nil {
+			return fmt.Errorf("unable to install add-on %s: %v", addOn, err)
+		}
+	}
+
+	// Add-ons may have added configuration
+	app, err = ddevapp.NewApp(dir, true)
+	if err != nil {
+		return fmt.Errorf("unable to read the project configuration: %v", err)
+	}
+	startedApp = app
+	if err = app.Start(); err != nil {
+		return fmt.Errorf("failed to start %s: %v", app.Name, err)
+	}
+
+	switch {
+	case t.Create.Composer != "":
+		util.Success("Running 'ddev composer create-project %s'", t.Create.Composer)
+		if err = util.RunDdevCommand(dir, []string{"composer", "create-project"}, t.Create.Composer); err != nil {
+			return fmt.Errorf("failed to create the project with Composer: %v", err)
+		}
+	case t.Create.Exec != "":
+		util.Success("Running '%s'", t.Create.Exec)
+		if _, _, err = app.Exec(&ddevapp.ExecOpts{Cmd: t.Create.Exec, NoCapture: true}); err != nil {
+			return fmt.Errorf("failed to create the project with '%s': %v", t.Create.Exec, err)
+		}
+	}
+
+	if err = t.CopyFiles(dir, ""); err != nil {
+		return fmt.Errorf("unable to copy the files of the template: %v", err)
+	}
+
+	if dbFile := t.DatabasePath(); dbFile != "" {
+		util.Success("Importing database %s", t.Database)
+		if err = app.ImportDB(dbFile, "", true, false, ""); err != nil {
+			return fmt.Errorf("failed to import database %s: %v", t.Database, err)
+		}
+	}
+	snapshotName, err := t.InstallSnapshot(app)
+	if err != nil {
+		return fmt.Errorf("unable to copy snapshot %s: %v", t.Snapshot, err)
+	}
+	if snapshotName != "" {
+		util.Success("Restoring snapshot %s", snapshotName)
+		if err = app.RestoreSnapshot(snapshotName); err != nil {
+			return fmt.Errorf("failed to restore snapshot %s: %v", snapshotName, err)
+		}
+	}
+
+	for _, c := range t.PostCreate {
+		util.Success("Running '%s'", c)
+		if _, _, err = app.Exec(&ddevapp.ExecOpts{Cmd: c, NoCapture: true}); err != nil {
+			return fmt.Errorf("post-create command '%s' failed: %v", c, err)
+		}
+	}
+
+	url := app.GetPrimaryURL()
+	output.UserOut.WithField("raw", map[string]string{
+		"name":     app.Name,
+		"approot":  app.AppRoot,
+		"template": t.Name,
+		"url":      url,
+	}).Printf("Created project %s from template %s, it's running at %s", app.Name, t.Name, url)
+	return nil
+}
+
+func init() {
+	NewCmd.Flags().String("project-name", "", "Name of the project, the directory name by default")
+	NewCmd.Flags().BoolP("verbose", "v", false, "Show the details of the add-on installations")
+	RootCmd.AddCommand(NewCmd)
+}
@@ -12,6 +12,7 @@ import (
 	ddevImages "github.com/ddev/ddev/pkg/docker"
 	"github.com/ddev/ddev/pkg/dockerutil"
 	"github.com/ddev/ddev/pkg/globalconfig"
+	"github.com/ddev/ddev/pkg/instrumentation"
 	"github.com/ddev/ddev/pkg/nodeps"
 	"github.com/ddev/ddev/pkg/output"
 	"github.com/ddev/ddev/pkg/tui"
@@ -88,6 +89,12 @@ Support: https://docs.ddev.com/en/stable/users/support/`,
 		if !output.JSONOutput && cmdCopy.Name() != cobra.ShellCompRequestCmd {
 			amplitude.TrackCommand(&cmdCopy, argsCopy)
 		}
+		if cmdCopy.Name() != cobra.ShellCompRequestCmd {
+			instrumentation.StartSpan(cmdCopy.CommandPath(), map[string]string{
+				"ddev.command":   cmdCopy.CommandPath(),
+				"ddev.called_as": cmdCopy.CalledAs(),
+			})
+		}
 
 		// Skip Docker and other validation for most commands
 		if command != "start" && command != "restart" {
@@ -151,6 +158,7 @@ Support: https://docs.ddev.com/en/stable/users/support/`,
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
 	if err := RootCmd.Execute(); err != nil {
+		instrumentation.Exit(err)
 		os.Exit(-1)
 	}
 }
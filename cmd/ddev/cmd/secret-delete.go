@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"github.com/ddev/ddev/pkg/output"
+	"github.com/ddev/ddev/pkg/secrets"
+	"github.com/ddev/ddev/pkg/util"
+	"github.com/spf13/cobra"
+)
+
+// SecretDeleteCmd implements the "ddev secret delete" command
+var SecretDeleteCmd = &cobra.Command{
+	Use:     "delete <name>",
+	Short:   "Remove a secret from the local store",
+	Example: `ddev secret delete api-token`,
+	Args:    cobra.ExactArgs(1),
+	Aliases: []string{"rm"},
+	ValidArgsFunction: func(_ *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		names, _ := secrets.List()
+		return names, cobra.ShellCompDirectiveNoFileComp
+	},
+	Run: func(_ *cobra.Command, args []string) {
+		if err := secrets.Delete(args[0]); err != nil {
+			util.Failed("Unable to delete secret %s: %v", args[0], err)
+		}
+		output.UserOut.WithField("raw", args[0]).Printf("Deleted secret %s", args[0])
+	},
+}
+
+func init() {
+	SecretCmd.AddCommand(SecretDeleteCmd)
+}
@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/ddev/ddev/pkg/output"
+	"github.com/ddev/ddev/pkg/secrets"
+	"github.com/ddev/ddev/pkg/util"
+	"github.com/spf13/cobra"
+)
+
+// SecretListCmd implements the "ddev secret list" command
+var SecretListCmd = &cobra.Command{
+	Use:     "list",
+	Short:   "List the names of the secrets in the local store",
+	Example: `ddev secret list`,
+	Args:    cobra.NoArgs,
+	Aliases: []string{"ls"},
+	Run: func(_ *cobra.Command, _ []string) {
+		names, err := secrets.List()
+		if err != nil {
+			util.Failed("Unable to list the secrets: %v", err)
+		}
+		if len(names) == 0 {
+			output.UserOut.WithField("raw", names).Print("The local store has no secrets, add one with 'ddev secret set <name>'")
+			return
+		}
+		output.UserOut.WithField("raw", names).Print(strings.Join(names, "\n"))
+	},
+}
+
+func init() {
+	SecretCmd.AddCommand(SecretListCmd)
+}
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/ddev/ddev/pkg/output"
+	"github.com/ddev/ddev/pkg/secrets"
+	"github.com/ddev/ddev/pkg/util"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// SecretSetCmd implements the "ddev secret set" command
+var SecretSetCmd = &cobra.Command{
+	Use:   "set <name>",
+	Short: "Add or replace a secret in the local store",
+	Long: `Add or replace a secret in the local store. The value is prompted for without echo,
+or read from stdin when it isn't a terminal, so it doesn't end up in the shell history.`,
+	Example: `ddev secret set api-token
+pass show api-token | ddev secret set api-token`,
+	Args: cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		name := args[0]
+		if err := secrets.ValidateName(name); err != nil {
+			util.Failed("%v", err)
+		}
+		value, err := readSecretValue(name)
+		if err != nil {
+			util.Failed("Unable to read the value of secret %s: %v", name, err)
+		}
+		if err = secrets.Set(name, value); err != nil {
+			util.Failed("Unable to store secret %s: %v", name, err)
+		}
+		output.UserOut.WithField("raw", name).Printf("Stored secret %s, reference it with %s%s", name, secrets.Prefix, name)
+	},
+}
+
+// readSecretValue prompts for the value of a secret, or reads it from stdin
+func readSecretValue(name string) (string, error) {
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		value, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimRight(string(value), "\r\n"), nil
+	}
+	_, _ = fmt.Fprintf(os.Stderr, "Value of secret %s: ", name)
+	value, err := term.ReadPassword(int(os.Stdin.Fd()))
+	_, _ = fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", err
+	}
+	return string(value), nil
+}
+
+func init() {
+	SecretCmd.AddCommand(SecretSetCmd)
+}
@@ -0,0 +1,24 @@
+package cmd
+
+import (
+	"github.com/ddev/ddev/pkg/util"
+	"github.com/spf13/cobra"
+)
+
+// SecretCmd is the top-level "ddev secret" command
+var SecretCmd = &cobra.Command{
+	Use:   "secret [command]",
+	Short: "Commands for managing the local store of secrets",
+	Long: `Manage the secrets referenced with 'secret:NAME' in web_environment and 'ddev exec -e'.
+The store is encrypted in the global DDEV directory with a key kept in the OS keychain, or with
+the passphrase in DDEV_SECRETS_PASSPHRASE if it's set. Secrets are never written to the project.
+Secrets not in the store are read from the output of secrets_command in the global configuration.`,
+	Run: func(cmd *cobra.Command, _ []string) {
+		err := cmd.Usage()
+		util.CheckErr(err)
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(SecretCmd)
+}
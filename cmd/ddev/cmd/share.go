@@ -61,6 +61,10 @@ ddev share myproject`,
 			}
 		}
 
+		if !globalconfig.DdevPolicy.AllowsShareProvider(providerName) {
+			util.Failed("The '%s' share provider is not allowed by the organization policy in %s, use one of: %s", providerName, globalconfig.DdevPolicy.Source, strings.Join(globalconfig.DdevPolicy.AllowedShareProviders, ", "))
+		}
+
 		// Get provider script path
 		scriptPath, err := app.GetShareProviderScript(providerName)
 		if err != nil {
@@ -5,6 +5,7 @@ import (
 	"os"
 
 	"github.com/ddev/ddev/pkg/ddevapp"
+	"github.com/ddev/ddev/pkg/idlemanager"
 	"github.com/ddev/ddev/pkg/util"
 	"github.com/docker/cli/cli"
 	"github.com/spf13/cobra"
@@ -23,9 +24,10 @@ var DdevSSHCmd = &cobra.Command{
 ddev ssh -s db
 ddev ssh -s db -u root
 ddev ssh <projectname>
-ddev ssh -d /var/www/html`,
+ddev ssh -d /var/www/html
+ddev ssh -e APP_ENV=test --env-file .env.testing`,
 	Args: cobra.MaximumNArgs(1),
-	Run: func(_ *cobra.Command, args []string) {
+	Run: func(cmd *cobra.Command, args []string) {
 		projects, err := getRequestedProjects(args, false)
 		if err != nil || len(projects) == 0 {
 			util.Failed("Failed to ddev ssh: %v", err)
@@ -39,19 +41,27 @@ ddev ssh -d /var/www/html`,
 
 		_ = app.DockerEnv()
 
+		env, err := execEnvFromFlags(cmd)
+		if err != nil {
+			util.Failed("Failed to ddev ssh: %v", err)
+		}
+
 		// Use Bash for our containers, sh for 3rd-party containers
 		// that may not have Bash.
 		shell := app.GetXDdevExtension(serviceType).SSHShell
 
+		doneActive := idlemanager.KeepActive(app.Name)
 		_, _, err = app.Exec(&ddevapp.ExecOpts{
 			Service:   serviceType,
 			RawCmd:    []string{shell, "-l"},
 			Dir:       sshDirArg,
 			User:      serviceUser,
+			Env:       env,
 			Tty:       true,
 			NoCapture: true,
 			SkipHooks: true,
 		})
+		doneActive()
 		if err != nil {
 			if statusErr, ok := errors.AsType[cli.StatusError](err); ok {
 				os.Exit(statusErr.StatusCode)
@@ -66,5 +76,6 @@ func init() {
 	_ = DdevSSHCmd.RegisterFlagCompletionFunc("service", ddevapp.GetServiceNamesFunc(true))
 	DdevSSHCmd.Flags().StringVarP(&sshDirArg, "dir", "d", "", "Defines the destination directory within the container")
 	DdevSSHCmd.Flags().StringVarP(&serviceUser, "user", "u", "", "Defines the user to use within the container")
+	addExecEnvFlags(DdevSSHCmd)
 	RootCmd.AddCommand(DdevSSHCmd)
 }
@@ -52,6 +52,7 @@ ddev start --all`,
 				URL:            globalconfig.DdevGlobalConfig.RemoteConfig.RemoteConfigURL,
 				UpdateInterval: globalconfig.DdevGlobalConfig.RemoteConfig.UpdateInterval,
 				TickerInterval: globalconfig.DdevGlobalConfig.Messages.TickerInterval,
+				Sources:        globalconfig.DdevGlobalConfig.Messages.Sources,
 			},
 			state,
 			globalconfig.IsInternetActive,
@@ -0,0 +1,176 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/ddev/ddev/pkg/appimport"
+	"github.com/ddev/ddev/pkg/ddevapp"
+	"github.com/ddev/ddev/pkg/dockerutil"
+	"github.com/ddev/ddev/pkg/output"
+	"github.com/ddev/ddev/pkg/util"
+	"github.com/spf13/cobra"
+)
+
+// UpCmd implements the ddev up command
+var UpCmd = &cobra.Command{
+	Use:   "up",
+	Short: "Start the project and run the bootstrap steps it hasn't completed yet",
+	Long: `Start the project and run the steps of the 'bootstrap' section of .ddev/config.yaml, in order,
+which turns a fresh clone into a working site: installing add-ons, 'composer install', getting the
+database and the files, and other commands. Completed steps are recorded in .ddev/` + ddevapp.BootstrapStateFileName + `,
+so running 'ddev up' again only runs the new or changed steps, and continues after a failed one.
+'ddev delete' and --reset forget the completed steps.`,
+	Example: `ddev up
+ddev up --list
+ddev up --reset`,
+	Args: cobra.NoArgs,
+	PreRun: func(_ *cobra.Command, _ []string) {
+		dockerutil.EnsureDdevNetwork()
+	},
+	Run: func(cmd *cobra.Command, _ []string) {
+		list, _ := cmd.Flags().GetBool("list")
+		reset, _ := cmd.Flags().GetBool("reset")
+
+		app, err := ddevapp.GetActiveApp("")
+		if err != nil {
+			util.Failed("Failed to get project: %v", err)
+		}
+		if err = app.ValidateConfig(); err != nil {
+			util.Failed("Invalid configuration of %s: %v", app.Name, err)
+		}
+		if reset {
+			if err = app.ResetBootstrapState(); err != nil {
+				util.Failed("Unable to reset the bootstrap state: %v", err)
+			}
+		}
+		state, err := app.ReadBootstrapState()
+		if err != nil {
+			util.Failed("Unable to read the bootstrap state: %v", err)
+		}
+
+		if list {
+			listBootstrapSteps(app, state)
+			return
+		}
+
+		started := false
+		start := func() {
+			if err := app.Start(); err != nil {
+				util.Failed("Failed to start %s: %v", app.Name, err)
+			}
+			started = true
+		}
+		keys := ddevapp.BootstrapStepKeys(app.Bootstrap)
+		for i, step := range app.Bootstrap {
+			if state.IsCompleted(keys[i]) {
+				util.Debug("Skipping completed bootstrap step '%s'", step)
+				continue
+			}
+			if step.NeedsRunningProject() && !started {
+				start()
+			}
+			util.Success("Running bootstrap step '%s'", step)
+			if err = runBootstrapStep(app, step); err != nil {
+				util.Failed("Bootstrap step '%s' failed: %v\nRun 'ddev up' again to continue from this step.", step, err)
+			}
+			state.Complete(keys[i])
+			if err = app.WriteBootstrapState(state); err != nil {
+				util.Failed("Unable to write the bootstrap state: %v", err)
+			}
+			if step.AddOn != "" {
+				// The add-on may have changed the configuration, it's used
+				// when the project starts again
+				if app, err = ddevapp.NewApp(app.AppRoot, true); err != nil {
+					util.Failed("Unable to read the project configuration: %v", err)
+				}
+				started = false
+			}
+		}
+		if !started {
+			start()
+		}
+
+		url := app.GetPrimaryURL()
+		output.UserOut.WithField("raw", map[string]string{
+			"name":    app.Name,
+			"approot": app.AppRoot,
+			"url":     url,
+		}).Printf("Project %s is up at %s", app.Name, url)
+	},
+}
+
+// listBootstrapSteps shows the bootstrap steps and whether they're completed
+func listBootstrapSteps(app *ddevapp.DdevApp, state *ddevapp.BootstrapState) {
+	var raw []map[string]any
+	var lines []string
+	keys := ddevapp.BootstrapStepKeys(app.Bootstrap)
+	for i, step := range app.Bootstrap {
+		completed, ok := state.Completed[keys[i]]
+		raw = append(raw, map[string]any{"step": step.String(), "completed": ok, "completed_at": completed})
+		if ok {
+			lines = append(lines, fmt.Sprintf("[x] %s (completed %s)", step, completed.Format("2006-01-02 15:04:05")))
+		} else {
+			lines = append(lines, fmt.Sprintf("[ ] %s", step))
+		}
+	}
+	if len(lines) == 0 {
+		lines = append(lines, fmt.Sprintf("Project %s has no bootstrap steps in its configuration", app.Name))
+	}
+	output.UserOut.WithField("raw", raw).Print(strings.Join(lines, "\n"))
+}
+
+// runBootstrapStep runs one step of the bootstrap section
+func runBootstrapStep(app *ddevapp.DdevApp, step ddevapp.BootstrapStep) error {
+	switch {
+	case step.AddOn != "":
+		return util.RunDdevCommand(app.AppRoot, []string{"add-on", "get", step.AddOn}, "")
+	case step.Composer != "":
+		return util.RunDdevCommand(app.AppRoot, []string{"composer"}, step.Composer)
+	case step.Npm != "":
+		return util.RunDdevCommand(app.AppRoot, []string{"npm"}, step.Npm)
+	case step.Ddev != "":
+		return util.RunDdevCommand(app.AppRoot, nil, step.Ddev)
+	case step.Exec != "":
+		_, _, err := app.Exec(&ddevapp.ExecOpts{Cmd: step.Exec, NoCapture: true})
+		return err
+	case step.Database != nil:
+		source := step.Database
+		switch {
+		case source.Provider != "":
+			return bootstrapPull(app, source.Provider, false)
+		case source.Snapshot != "":
+			return app.RestoreSnapshot(source.Snapshot)
+		default:
+			return app.ImportDB(filepath.Join(app.AppRoot, source.File), "", true, false, "")
+		}
+	case step.Files != nil:
+		source := step.Files
+		if source.Provider != "" {
+			return bootstrapPull(app, source.Provider, true)
+		}
+		importPath, _, err := appimport.ValidateAsset(filepath.Join(app.AppRoot, source.File), "files")
+		if err != nil {
+			return err
+		}
+		return app.ImportFiles(source.Target, importPath, "")
+	}
+	return fmt.Errorf("nothing to do in bootstrap step '%s'", step)
+}
+
+// bootstrapPull pulls the database, or the files, from a provider
+func bootstrapPull(app *ddevapp.DdevApp, providerName string, files bool) error {
+	provider, err := app.GetProvider(providerName)
+	if err != nil {
+		return err
+	}
+	app.ProviderInstance = provider
+	return app.Pull(provider, files, !files, false)
+}
+
+func init() {
+	UpCmd.Flags().Bool("list", false, "Show the bootstrap steps and whether they're completed, without running them")
+	UpCmd.Flags().Bool("reset", false, "Forget the completed bootstrap steps and run all of them")
+	RootCmd.AddCommand(UpCmd)
+}
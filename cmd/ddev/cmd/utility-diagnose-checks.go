@@ -0,0 +1,744 @@
+package cmd
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ddev/ddev/pkg/ddevapp"
+	"github.com/ddev/ddev/pkg/diagnostics"
+	"github.com/ddev/ddev/pkg/dockerutil"
+	"github.com/ddev/ddev/pkg/exec"
+	"github.com/ddev/ddev/pkg/globalconfig"
+	"github.com/ddev/ddev/pkg/hostname"
+	"github.com/ddev/ddev/pkg/netutil"
+	"github.com/ddev/ddev/pkg/nodeps"
+	"github.com/ddev/ddev/pkg/version"
+	"github.com/ddev/ddev/pkg/versionconstants"
+	"github.com/moby/moby/api/types/container"
+)
+
+// diagnoseTimeout is how long the network checks wait for an answer
+const diagnoseTimeout = 10 * time.Second
+
+// runningApp returns the project of the context if it's running, or the
+// reason to skip a check that needs a running project
+func runningApp(ctx *diagnostics.Context) (*ddevapp.DdevApp, *diagnostics.Result) {
+	if ctx.App == nil {
+		r := diagnostics.Skip("not in a DDEV project")
+		return nil, &r
+	}
+	if status, _ := ctx.App.SiteStatus(); status != ddevapp.SiteRunning {
+		r := diagnostics.Skip("project %s is not running, start it with 'ddev start'", ctx.App.Name)
+		return nil, &r
+	}
+	return ctx.App, nil
+}
+
+// failWithOutput returns a failing result with details shown below the message
+func failWithOutput(details []string, format string, a ...any) diagnostics.Result {
+	r := diagnostics.Fail(format, a...)
+	r.Output = strings.Join(details, "\n")
+	return r
+}
+
+// unresolvableHostnames returns the project hostnames that neither resolve
+// to a local address nor are in the hosts file
+func unresolvableHostnames(app *ddevapp.DdevApp) []string {
+	var missing []string
+	for _, name := range app.GetHostnames() {
+		ips, err := net.DefaultResolver.LookupIP(context.Background(), "ip4", strings.TrimPrefix(name, "*."))
+		if err == nil && netutil.HasLocalIP(ips) {
+			continue
+		}
+		if exists, _ := hostname.IsHostnameInHostsFile(name); exists {
+			continue
+		}
+		missing = append(missing, name)
+	}
+	return missing
+}
+
+// portConflicts describes each port the project needs that's in use, with
+// the container or process holding it when it can be found without sudo
+func portConflicts(app *ddevapp.DdevApp) []string {
+	var conflicts []string
+	for _, np := range portsToDiagnose(app) {
+		if isPortFree(np.port) {
+			continue
+		}
+		owner := "an unknown process, see 'ddev utility port-diagnose'"
+		if name := findContainerForPort(np.port); name != "" {
+			owner = "container " + name
+		} else {
+			procs := findPortProcesses(np.port)
+			if nodeps.IsWSL2() {
+				procs = append(procs, findWindowsPortProcesses(np.port)...)
+			}
+			var names []string
+			for _, p := range suppressWSLRelayIfRedundant(deduplicateByName(procs)) {
+				names = append(names, fmt.Sprintf("%s (PID %d)", p.Name, p.PID))
+			}
+			if len(names) > 0 {
+				owner = strings.Join(names, ", ")
+			}
+		}
+		conflicts = append(conflicts, fmt.Sprintf("Port %s (%s) is in use by %s", np.port, np.label, owner))
+	}
+	return conflicts
+}
+
+// xdebugProblems checks that the web container can reach an IDE on the
+// host, as far as that's possible without an IDE listening
+func xdebugProblems(app *ddevapp.DdevApp) (listening bool, problems []string) {
+	isWSL2 := nodeps.IsWSL2()
+	ideLocation := globalconfig.DdevGlobalConfig.XdebugIDELocation
+
+	if isWSL2 && nodeps.IsWSL2MirroredMode() && !nodeps.IsWSL2HostAddressLoopbackEnabled() {
+		problems = append(problems, "WSL2 mirrored mode needs hostAddressLoopback=true in the [experimental] section of .wslconfig, then 'wsl --shutdown'")
+	}
+	// xdebug_ide_location is only right for VS Code with the WSL extension
+	if ideLocation != "" && (!isWSL2 || ideLocation != "wsl2") {
+		problems = append(problems, fmt.Sprintf("xdebug_ide_location is set to '%s', it should usually be empty: ddev config global --xdebug-ide-location=\"\"", ideLocation))
+	}
+
+	// An IDE that's already listening proves the connection works
+	listening, _ = testContainerToHostConnectivity(app, "host.docker.internal", 9003)
+	if !listening {
+		var connFailed bool
+		if isWSL2 && ideLocation != "wsl2" {
+			connFailed = testWSL2NATConnectionQuiet(app)
+		} else {
+			connFailed = testSimpleConnectionQuiet(app)
+		}
+		if connFailed {
+			problems = append(problems, fmt.Sprintf("The web container can't connect to port 9003 on the host (host.docker.internal is %s), check the firewall", dockerutil.GetHostDockerInternal().IPAddress))
+		}
+	}
+	return listening, problems
+}
+
+// mutagenProblems returns the issues and warnings of the Mutagen configuration
+func mutagenProblems(app *ddevapp.DdevApp) (issues []string, warnings []string) {
+	result := ddevapp.DiagnoseMutagenConfiguration(app)
+	if !result.LabelsMatch {
+		issues = append(issues, "Sync session and volume labels don't match")
+	}
+	issues = append(issues, result.Problems...)
+	issues = append(issues, result.IgnoreIssues...)
+	if result.VolumeCritical || result.VolumeWarning {
+		warnings = append(warnings, fmt.Sprintf("Volume %s is large (%s), consider excluding directories from the sync", ddevapp.GetMutagenVolumeName(app), result.VolumeSizeHuman))
+	}
+	if !result.UploadDirsConfigured && result.UploadDirsSuggestion != "" {
+		warnings = append(warnings, fmt.Sprintf("No upload_dirs configured, consider upload_dirs: [\"%s\"]", result.UploadDirsSuggestion))
+	}
+	warnings = append(warnings, result.IgnoreWarnings...)
+	return issues, warnings
+}
+
+// projectCertProblems checks the default and project certificates against
+// the mkcert CA
+func projectCertProblems(caRoot string, app *ddevapp.DdevApp) ([]string, error) {
+	caPool, err := loadCACertPool(caRoot)
+	if err != nil {
+		return nil, err
+	}
+	certs := map[string][]string{
+		filepath.Join(globalconfig.GetGlobalDdevDir(), "traefik", "certs", "default_cert.crt"): nil,
+	}
+	if app != nil {
+		certs[filepath.Join(app.GetConfigPath("traefik/certs"), app.Name+".crt")] = []string{app.GetHostname()}
+	}
+	var problems []string
+	for certPath, hostnames := range certs {
+		_, certProblems := certFileProblems(certPath, caPool, hostnames)
+		for _, problem := range certProblems {
+			problems = append(problems, fmt.Sprintf("%s: %s", filepath.Base(certPath), problem))
+		}
+	}
+	return problems, nil
+}
+
+// httpGet gets a URL with a timeout, returning the status and the body
+func httpGet(client *http.Client, url string) (int, string, error) {
+	resp, err := client.Get(url)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	return resp.StatusCode, string(body), err
+}
+
+// createTestProject configures and starts a throwaway PHP project in a
+// temporary directory and checks that it serves PHP. The project is kept
+// for investigation.
+func createTestProject() diagnostics.Result {
+	dir, err := os.MkdirTemp("", "ddev-diagnose-test-")
+	if err != nil {
+		return diagnostics.Fail("Unable to create a directory for the test project: %v", err)
+	}
+	if err = os.MkdirAll(filepath.Join(dir, "web"), 0755); err == nil {
+		err = os.WriteFile(filepath.Join(dir, "web", "index.php"), []byte("<?php phpinfo();\n"), 0644)
+	}
+	if err != nil {
+		return diagnostics.Fail("Unable to create the test project in %s: %v", dir, err)
+	}
+
+	app, err := ddevapp.NewApp(dir, false)
+	if err != nil {
+		return diagnostics.Fail("Unable to create the test project in %s: %v", dir, err)
+	}
+	app.Name = fmt.Sprintf("ddev-diagnose-test-%d", os.Getpid())
+	app.Type = nodeps.AppTypePHP
+	app.Docroot = "web"
+	app.DisableUploadDirsWarning = true
+	if err = app.WriteConfig(); err != nil {
+		return diagnostics.Fail("Unable to configure the test project in %s: %v", dir, err)
+	}
+	cleanup := fmt.Sprintf("Delete the test project with 'ddev delete -Oy %s && rm -rf %s'", app.Name, dir)
+	if err = app.Start(); err != nil {
+		return failWithOutput([]string{cleanup}, "Test project %s in %s failed to start: %v", app.Name, dir, err)
+	}
+
+	status, body, err := httpGet(&http.Client{Timeout: diagnoseTimeout}, app.GetHTTPURL())
+	if err != nil || !strings.Contains(body, "PHP Version") {
+		return failWithOutput([]string{cleanup}, "Test project %s doesn't serve PHP at %s (status %d, error %v)", app.Name, app.GetHTTPURL(), status, err)
+	}
+	r := diagnostics.Pass("Test project %s started and serves PHP at %s", app.Name, app.GetHTTPURL())
+	r.Output = cleanup
+	return r
+}
+
+// portFixes offers to stop the containers publishing the ports the project needs
+func portFixes(ctx *diagnostics.Context, _ diagnostics.Result) []diagnostics.Fix {
+	var fixes []diagnostics.Fix
+	seen := map[string]bool{}
+	for _, np := range portsToDiagnose(ctx.App) {
+		if isPortFree(np.port) {
+			continue
+		}
+		name := findContainerForPort(np.port)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		fixes = append(fixes, diagnostics.Fix{
+			Action: fmt.Sprintf("Stop container %s, which uses port %s (%s)", name, np.port, np.label),
+			Apply:  func() error { return dockerutil.StopContainer(name) },
+		})
+	}
+	return fixes
+}
+
+// tlsFixes offers to install the mkcert CA in the trust stores
+func tlsFixes(_ *diagnostics.Context, _ diagnostics.Result) []diagnostics.Fix {
+	if !hasCommand("mkcert") {
+		return nil
+	}
+	return []diagnostics.Fix{{
+		Action: "Run 'mkcert -install' to add the mkcert CA to the system and browser trust stores",
+		Apply:  func() error { return exec.RunInteractiveCommand("mkcert", []string{"-install"}) },
+	}}
+}
+
+// hostnameFixes offers to add the unresolvable hostnames to the hosts file
+func hostnameFixes(ctx *diagnostics.Context, _ diagnostics.Result) []diagnostics.Fix {
+	if ctx.App == nil {
+		return nil
+	}
+	missing := unresolvableHostnames(ctx.App)
+	if len(missing) == 0 {
+		return nil
+	}
+	return []diagnostics.Fix{{
+		Action: fmt.Sprintf("Add %s to the hosts file, which needs administrator privileges", strings.Join(missing, ", ")),
+		Apply:  ctx.App.AddHostsEntriesIfNeeded,
+	}}
+}
+
+// mutagenFixes offers to reset the sync, and to regenerate a DDEV-managed
+// mutagen.yml, for the failures that this repairs: a session or volume that
+// doesn't match the config, sync problems, and a missing or unreadable
+// mutagen.yml. A large volume or missing upload_dirs need a config change.
+func mutagenFixes(ctx *diagnostics.Context, _ diagnostics.Result) []diagnostics.Fix {
+	app := ctx.App
+	if app == nil || !app.IsMutagenEnabled() {
+		return nil
+	}
+	diagnosis := ddevapp.DiagnoseMutagenConfiguration(app)
+	// The user may have taken over mutagen.yml, so leave it alone then
+	rewrite := len(diagnosis.IgnoreIssues) > 0 && !diagnosis.MutagenYmlCustomized
+	if diagnosis.LabelsMatch && !diagnosis.HasProblems && !rewrite {
+		return nil
+	}
+	action := fmt.Sprintf("Reset the Mutagen sync and restart project %s", app.Name)
+	if rewrite {
+		action = fmt.Sprintf("Rewrite .ddev/mutagen/mutagen.yml with the current upload_dirs, reset the Mutagen sync and restart project %s", app.Name)
+	}
+	return []diagnostics.Fix{{
+		Action: action,
+		Apply: func() error {
+			if rewrite {
+				if err := app.GenerateMutagenYml(); err != nil {
+					return err
+				}
+			}
+			if err := ddevapp.MutagenReset(app); err != nil {
+				return err
+			}
+			return app.Start()
+		},
+	}}
+}
+
+// routerFixes offers to clear the router's cached health status, which
+// stays unhealthy after the errors that caused it are gone
+func routerFixes(_ *diagnostics.Context, _ diagnostics.Result) []diagnostics.Fix {
+	return []diagnostics.Fix{{
+		Action: fmt.Sprintf("Clear the health status of the %s container and run its healthcheck again", nodeps.RouterContainer),
+		Apply:  ddevapp.ClearRouterHealthcheck,
+	}}
+}
+
+func init() {
+	diagnostics.Register(&diagnostics.FuncCheck{
+		CheckID:          "docker.provider",
+		CheckCategory:    "docker",
+		CheckSeverity:    diagnostics.SeverityError,
+		CheckDescription: "Docker provider is running and new enough",
+		Remediation:      "Start or restart your Docker provider, see https://docs.ddev.com/en/stable/users/install/docker-installation/",
+		Fn: func(_ *diagnostics.Context) diagnostics.Result {
+			if err := dockerutil.CheckDockerVersion(dockerutil.DockerRequirements); err != nil {
+				if err.Error() == "no docker" {
+					return diagnostics.Fail("Docker is not installed or the Docker client is not in the $PATH")
+				}
+				return diagnostics.Fail("Problem with your Docker provider: %v", err)
+			}
+			dockerVersion, _ := dockerutil.GetDockerVersion()
+			apiVersion, _ := dockerutil.GetDockerAPIVersion()
+			platform, _ := version.GetDockerPlatform()
+			return diagnostics.Pass("Docker %s (API %s) on %s", dockerVersion, apiVersion, platform)
+		},
+	})
+
+	diagnostics.Register(&diagnostics.FuncCheck{
+		CheckID:          "docker.buildx",
+		CheckCategory:    "docker",
+		CheckSeverity:    diagnostics.SeverityError,
+		CheckDescription: "docker buildx can build and load an image",
+		Remediation:      "Run 'ddev utility dockercheck' for details, see https://docs.ddev.com/en/stable/users/install/docker-installation/",
+		Fn: func(_ *diagnostics.Context) diagnostics.Result {
+			if _, err := dockerutil.DownloadDockerBuildxIfNeeded(); err != nil {
+				return diagnostics.Fail("docker buildx is not available: %v", err)
+			}
+			if err := dockerutil.CheckDockerBuildxVersion(); err != nil {
+				return diagnostics.Fail("docker buildx version check: %v", err)
+			}
+			if out, err := checkBuildxBuild(); err != nil {
+				r := diagnostics.Fail("Unable to build and load a trivial image with buildx: %v", err)
+				r.Output = out
+				return r
+			}
+			buildxVersion, _ := dockerutil.GetDockerBuildxVersion()
+			return diagnostics.Pass("docker buildx %s builds and loads images", buildxVersion)
+		},
+	})
+
+	diagnostics.Register(&diagnostics.FuncCheck{
+		CheckID:          "docker.containers",
+		CheckCategory:    "docker",
+		CheckSeverity:    diagnostics.SeverityError,
+		CheckDescription: "Containers can run, mount volumes and reach the internet",
+		Remediation:      "Restart your Docker provider, and check firewall and VPN settings, see https://docs.ddev.com/en/stable/users/usage/troubleshooting/#network-issues",
+		Fn: func(_ *diagnostics.Context) diagnostics.Result {
+			if out, err := checkSimpleContainer(); err != nil {
+				r := diagnostics.Fail("Unable to run a container that mounts a volume: %v", err)
+				r.Output = out
+				return r
+			}
+			if err := checkContainerInternet(); err != nil {
+				return diagnostics.Fail("Containers can't reach the internet, many things will fail: %v", err)
+			}
+			return diagnostics.Pass("Containers run, mount volumes and reach the internet")
+		},
+	})
+
+	diagnostics.Register(&diagnostics.FuncCheck{
+		CheckID:          "docker.disk",
+		CheckCategory:    "docker",
+		CheckSeverity:    diagnostics.SeverityWarning,
+		CheckDescription: "Docker has enough disk space",
+		Remediation:      "Run 'ddev clean --all' or 'docker system prune', or increase the disk size of your Docker provider",
+		Fn: func(_ *diagnostics.Context) diagnostics.Result {
+			if err := dockerutil.CheckAvailableSpace(); err != nil {
+				return diagnostics.Fail("%v", err)
+			}
+			return diagnostics.Pass("Enough disk space available")
+		},
+	})
+
+	diagnostics.Register(&diagnostics.FuncCheck{
+		CheckID:          "docker.auth",
+		CheckCategory:    "docker",
+		CheckSeverity:    diagnostics.SeverityWarning,
+		CheckDescription: "Docker authentication is configured correctly",
+		Remediation:      "Check the credential helpers in ~/.docker/config.json",
+		Fn: func(_ *diagnostics.Context) diagnostics.Result {
+			if err := dockerutil.CheckDockerAuth(); err != nil {
+				return diagnostics.Fail("Docker authentication may have issues: %v", err)
+			}
+			return diagnostics.Pass("No problems found in the Docker config")
+		},
+	})
+
+	diagnostics.Register(&diagnostics.FuncCheck{
+		CheckID:          "docker.testproject",
+		CheckCategory:    "docker",
+		CheckSeverity:    diagnostics.SeverityError,
+		CheckDescription: "A new PHP project starts and serves pages, with DDEV_DIAGNOSE_FULL=true",
+		Remediation:      "Check 'ddev logs' in the test project",
+		Fn: func(_ *diagnostics.Context) diagnostics.Result {
+			if !nodeps.IsEnvTrue("DDEV_DIAGNOSE_FULL") {
+				return diagnostics.Skip("set DDEV_DIAGNOSE_FULL=true to create and start a test project")
+			}
+			return createTestProject()
+		},
+	})
+
+	diagnostics.Register(&diagnostics.FuncCheck{
+		CheckID:          "tls.mkcert",
+		CheckCategory:    "tls",
+		CheckSeverity:    diagnostics.SeverityWarning,
+		CheckDescription: "mkcert is installed and its CA exists",
+		Remediation:      "Install mkcert and run 'mkcert -install', see https://docs.ddev.com/en/stable/users/install/ddev-installation/",
+		Fn: func(_ *diagnostics.Context) diagnostics.Result {
+			caRoot, err := globalconfig.ReadCAROOTDetails()
+			if err != nil {
+				return diagnostics.Fail("%v", err)
+			}
+			return diagnostics.Pass("mkcert CA in %s", caRoot)
+		},
+		FixFn: tlsFixes,
+	})
+
+	diagnostics.Register(&diagnostics.FuncCheck{
+		CheckID:          "tls.trust",
+		CheckCategory:    "tls",
+		CheckSeverity:    diagnostics.SeverityWarning,
+		CheckDescription: "The mkcert CA is trusted by the OS",
+		Remediation:      "Run 'mkcert -install' and 'ddev restart', and see 'ddev utility tls-diagnose' for details",
+		Fn: func(_ *diagnostics.Context) diagnostics.Result {
+			caRoot, err := globalconfig.ReadCAROOTDetails()
+			if err != nil {
+				return diagnostics.Skip("mkcert CA is not available")
+			}
+			trusted, err := caTrustedByOS(caRoot)
+			if err != nil {
+				return diagnostics.Fail("Unable to check the OS trust store: %v", err)
+			}
+			if !trusted {
+				return diagnostics.Fail("The mkcert CA in %s is not trusted by the OS, 'mkcert -install' hasn't been run or was run as a different user", caRoot)
+			}
+			return diagnostics.Pass("The mkcert CA is trusted by the OS")
+		},
+		FixFn: tlsFixes,
+	})
+
+	diagnostics.Register(&diagnostics.FuncCheck{
+		CheckID:          "tls.certificates",
+		CheckCategory:    "tls",
+		CheckSeverity:    diagnostics.SeverityWarning,
+		CheckDescription: "Router certificates are valid, signed by the mkcert CA and cover the project hostname",
+		Remediation:      "Run 'ddev poweroff && ddev start' to regenerate the certificates",
+		Fn: func(ctx *diagnostics.Context) diagnostics.Result {
+			caRoot, err := globalconfig.ReadCAROOTDetails()
+			if err != nil {
+				return diagnostics.Skip("mkcert CA is not available")
+			}
+			problems, err := projectCertProblems(caRoot, ctx.App)
+			if err != nil {
+				return diagnostics.Fail("Unable to load the mkcert CA: %v", err)
+			}
+			if len(problems) > 0 {
+				return failWithOutput(problems, "%d certificate problem(s) found", len(problems))
+			}
+			return diagnostics.Pass("Certificates are valid")
+		},
+	})
+
+	diagnostics.Register(&diagnostics.FuncCheck{
+		CheckID:          "tls.connection",
+		CheckCategory:    "tls",
+		CheckSeverity:    diagnostics.SeverityWarning,
+		CheckDescription: "HTTPS connections to the project are trusted",
+		Remediation:      "Run 'mkcert -install' and 'ddev restart', and see 'ddev utility tls-diagnose' for details",
+		Fn: func(ctx *diagnostics.Context) diagnostics.Result {
+			app, skip := runningApp(ctx)
+			if skip != nil {
+				return *skip
+			}
+			caRoot, err := globalconfig.ReadCAROOTDetails()
+			if err != nil {
+				return diagnostics.Skip("mkcert CA is not available")
+			}
+			caPool, err := loadCACertPool(caRoot)
+			if err != nil {
+				return diagnostics.Fail("Unable to load the mkcert CA: %v", err)
+			}
+			httpsPort := app.GetPrimaryRouterHTTPSPort()
+			if httpsPort == "" {
+				httpsPort = "443"
+			}
+			addr := net.JoinHostPort("localhost", httpsPort)
+			dialer := &tls.Dialer{NetDialer: &net.Dialer{Timeout: diagnoseTimeout}, Config: &tls.Config{ServerName: app.GetHostname(), RootCAs: caPool}}
+			conn, err := dialer.DialContext(context.Background(), "tcp", addr)
+			if err != nil {
+				return diagnostics.Fail("TLS connection to %s with SNI %s failed: %v", addr, app.GetHostname(), err)
+			}
+			_ = conn.Close()
+			return diagnostics.Pass("TLS verified for %s on %s", app.GetHostname(), addr)
+		},
+	})
+
+	diagnostics.Register(&diagnostics.FuncCheck{
+		CheckID:          "network.ports",
+		CheckCategory:    "network",
+		CheckSeverity:    diagnostics.SeverityError,
+		CheckDescription: "Ports needed by the project are free",
+		Remediation:      "Stop the processes using the ports, or change router_http_port and router_https_port, see 'ddev utility port-diagnose'",
+		Fn: func(ctx *diagnostics.Context) diagnostics.Result {
+			// Running projects and the router hold the ports themselves
+			if reasons := activeDdevReasons(); len(reasons) > 0 {
+				return diagnostics.Skip("DDEV is active (%s), run 'ddev poweroff' to check the ports", strings.Join(reasons, "; "))
+			}
+			if conflicts := portConflicts(ctx.App); len(conflicts) > 0 {
+				return failWithOutput(conflicts, "%d port(s) in use", len(conflicts))
+			}
+			return diagnostics.Pass("All required ports are available")
+		},
+		FixFn: portFixes,
+	})
+
+	diagnostics.Register(&diagnostics.FuncCheck{
+		CheckID:          "network.hostnames",
+		CheckCategory:    "network",
+		CheckSeverity:    diagnostics.SeverityWarning,
+		CheckDescription: "Project hostnames resolve to this machine",
+		Remediation:      "Run 'ddev start' to add the hostnames to the hosts file, see https://docs.ddev.com/en/stable/users/usage/networking/",
+		Fn: func(ctx *diagnostics.Context) diagnostics.Result {
+			if ctx.App == nil {
+				return diagnostics.Skip("not in a DDEV project")
+			}
+			if missing := unresolvableHostnames(ctx.App); len(missing) > 0 {
+				return diagnostics.Fail("Hostnames don't resolve and aren't in the hosts file: %s", strings.Join(missing, ", "))
+			}
+			return diagnostics.Pass("All hostnames resolve")
+		},
+		FixFn: hostnameFixes,
+	})
+
+	diagnostics.Register(&diagnostics.FuncCheck{
+		CheckID:          "network.dns",
+		CheckCategory:    "network",
+		CheckSeverity:    diagnostics.SeverityWarning,
+		CheckDescription: "DNS resolves *." + nodeps.DdevDefaultTLD + " to this machine",
+		Remediation:      "See https://docs.ddev.com/en/stable/users/usage/networking/#restrictive-dns-servers",
+		Fn: func(_ *diagnostics.Context) diagnostics.Result {
+			name := "test." + nodeps.DdevDefaultTLD
+			ips, err := net.DefaultResolver.LookupIP(context.Background(), "ip4", name)
+			if err != nil {
+				return diagnostics.Fail("Unable to resolve %s: %v", name, err)
+			}
+			if !netutil.HasLocalIP(ips) {
+				return diagnostics.Fail("%s resolves to %v, not to this machine", name, ips)
+			}
+			return diagnostics.Pass("%s resolves to this machine", name)
+		},
+	})
+
+	diagnostics.Register(&diagnostics.FuncCheck{
+		CheckID:          "network.internet",
+		CheckCategory:    "network",
+		CheckSeverity:    diagnostics.SeverityWarning,
+		CheckDescription: "The internet is reachable from the host",
+		Remediation:      "Check your network connection, firewall and VPN",
+		Fn: func(_ *diagnostics.Context) diagnostics.Result {
+			if _, _, err := httpGet(&http.Client{Timeout: diagnoseTimeout}, "https://www.google.com"); err != nil {
+				return diagnostics.Fail("No internet access from the host: %v", err)
+			}
+			return diagnostics.Pass("The internet is reachable")
+		},
+	})
+
+	diagnostics.Register(&diagnostics.FuncCheck{
+		CheckID:          "network.proxy",
+		CheckCategory:    "network",
+		CheckSeverity:    diagnostics.SeverityInfo,
+		CheckDescription: "No proxy is configured in the environment",
+		Remediation:      "Proxies can interfere with DDEV, add the project hostnames to NO_PROXY, see https://docs.ddev.com/en/stable/users/usage/networking/",
+		Fn: func(_ *diagnostics.Context) diagnostics.Result {
+			var proxies []string
+			for _, name := range []string{"HTTP_PROXY", "http_proxy", "HTTPS_PROXY", "https_proxy"} {
+				if value := os.Getenv(name); value != "" {
+					proxies = append(proxies, fmt.Sprintf("%s=%s", name, value))
+				}
+			}
+			if len(proxies) > 0 {
+				return diagnostics.Fail("Proxy environment variables are set: %s", strings.Join(proxies, ", "))
+			}
+			return diagnostics.Pass("No proxy configured")
+		},
+	})
+
+	diagnostics.Register(&diagnostics.FuncCheck{
+		CheckID:          "project.location",
+		CheckCategory:    "project",
+		CheckSeverity:    diagnostics.SeverityWarning,
+		CheckDescription: "The project is in the home directory",
+		Remediation:      "See https://docs.ddev.com/en/stable/users/usage/troubleshooting/#project-location",
+		Fn: func(ctx *diagnostics.Context) diagnostics.Result {
+			if ctx.App == nil {
+				return diagnostics.Skip("not in a DDEV project")
+			}
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return diagnostics.Skip("unable to find the home directory: %v", err)
+			}
+			if rel, err := filepath.Rel(home, ctx.App.AppRoot); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+				return diagnostics.Fail("The project is in %s, it should usually be in a subdirectory of your home directory", ctx.App.AppRoot)
+			}
+			return diagnostics.Pass("The project is in the home directory")
+		},
+	})
+
+	diagnostics.Register(&diagnostics.FuncCheck{
+		CheckID:          "project.customizations",
+		CheckCategory:    "project",
+		CheckSeverity:    diagnostics.SeverityInfo,
+		CheckDescription: "The project has no custom configuration",
+		Remediation:      "Customizations can cause issues, try temporarily removing them, see 'ddev utility check-custom-config --all'",
+		Fn: func(ctx *diagnostics.Context) diagnostics.Result {
+			if ctx.App == nil {
+				return diagnostics.Skip("not in a DDEV project")
+			}
+			if message, hasWarnings := ctx.App.CheckCustomConfig(false); hasWarnings {
+				return failWithOutput([]string{message}, "Custom configuration detected")
+			}
+			return diagnostics.Pass("No custom configuration detected")
+		},
+	})
+
+	diagnostics.Register(&diagnostics.FuncCheck{
+		CheckID:          "project.http",
+		CheckCategory:    "project",
+		CheckSeverity:    diagnostics.SeverityWarning,
+		CheckDescription: "The project containers respond and the project is reachable over HTTP",
+		Remediation:      "Check 'ddev logs' and 'ddev logs -s router', or try 'ddev restart'",
+		Fn: func(ctx *diagnostics.Context) diagnostics.Result {
+			app, skip := runningApp(ctx)
+			if skip != nil {
+				return *skip
+			}
+			if _, _, err := app.Exec(&ddevapp.ExecOpts{Cmd: "true"}); err != nil {
+				return diagnostics.Fail("The web container doesn't respond: %v", err)
+			}
+			url := app.GetHTTPURL()
+			status, _, err := httpGet(&http.Client{Timeout: diagnoseTimeout}, url)
+			if err != nil {
+				return diagnostics.Fail("Unable to connect to %s: %v", url, err)
+			}
+			if status >= http.StatusInternalServerError {
+				return diagnostics.Fail("%s returned HTTP %d", url, status)
+			}
+			return diagnostics.Pass("%s returned HTTP %d", url, status)
+		},
+	})
+
+	diagnostics.Register(&diagnostics.FuncCheck{
+		CheckID:          "xdebug.connectivity",
+		CheckCategory:    "xdebug",
+		CheckSeverity:    diagnostics.SeverityWarning,
+		CheckDescription: "Web container can reach the IDE for Xdebug",
+		Remediation:      "Start listening in your IDE and run 'ddev utility xdebug-diagnose --interactive'",
+		Fn: func(ctx *diagnostics.Context) diagnostics.Result {
+			app, skip := runningApp(ctx)
+			if skip != nil {
+				return *skip
+			}
+			listening, problems := xdebugProblems(app)
+			if len(problems) > 0 {
+				return failWithOutput(problems, "%d Xdebug problem(s) found", len(problems))
+			}
+			if listening {
+				return diagnostics.Pass("An IDE is listening on port 9003")
+			}
+			return diagnostics.Pass("The web container can connect to port 9003 on the host")
+		},
+	})
+
+	diagnostics.Register(&diagnostics.FuncCheck{
+		CheckID:          "mutagen.sync",
+		CheckCategory:    "mutagen",
+		CheckSeverity:    diagnostics.SeverityWarning,
+		CheckDescription: "Mutagen sync sessions, volumes and upload_dirs",
+		Remediation:      "Run 'ddev mutagen reset' and see 'ddev utility mutagen-diagnose' for details",
+		Fn: func(ctx *diagnostics.Context) diagnostics.Result {
+			app, skip := runningApp(ctx)
+			if skip != nil {
+				return *skip
+			}
+			if !app.IsMutagenEnabled() {
+				return diagnostics.Skip("Mutagen is not enabled for project %s", app.Name)
+			}
+			if err := dockerutil.CheckDockerCLI(); err != nil {
+				return diagnostics.Fail("docker CLI is not available: %v", err)
+			}
+			issues, warnings := mutagenProblems(app)
+			if len(issues) > 0 {
+				return failWithOutput(append(issues, warnings...), "%d Mutagen issue(s) found", len(issues))
+			}
+			if len(warnings) > 0 {
+				return failWithOutput(warnings, "%d Mutagen warning(s) found", len(warnings))
+			}
+			return diagnostics.Pass("Mutagen %s is in sync", versionconstants.RequiredMutagenVersion)
+		},
+		FixFn: mutagenFixes,
+	})
+
+	diagnostics.Register(&diagnostics.FuncCheck{
+		CheckID:          "router.health",
+		CheckCategory:    "router",
+		CheckSeverity:    diagnostics.SeverityError,
+		CheckDescription: "The ddev-router container is healthy",
+		Remediation:      "Run 'ddev poweroff && ddev start' to recreate the router",
+		Fn: func(_ *diagnostics.Context) diagnostics.Result {
+			if nodeps.ArrayContainsString(globalconfig.DdevGlobalConfig.OmitContainersGlobal, nodeps.RouterContainer) {
+				return diagnostics.Skip("%s is omitted in the global config", nodeps.RouterContainer)
+			}
+			if router, err := ddevapp.FindDdevRouter(); err != nil || router == nil {
+				return diagnostics.Skip("%s is not running", nodeps.RouterContainer)
+			}
+			status, logOutput := ddevapp.GetRouterStatus()
+			if status != string(container.Healthy) {
+				r := diagnostics.Fail("%s is %s", nodeps.RouterContainer, status)
+				r.Output = logOutput
+				return r
+			}
+			if configErrors := ddevapp.GetRouterConfigErrors(); configErrors != "" {
+				r := diagnostics.Fail("%s has configuration errors", nodeps.RouterContainer)
+				r.Output = configErrors
+				return r
+			}
+			return diagnostics.Pass("%s is healthy", nodeps.RouterContainer)
+		},
+		FixFn: routerFixes,
+	})
+}
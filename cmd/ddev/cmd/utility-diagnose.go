@@ -1,14 +1,16 @@
 package cmd
 
 import (
+	"fmt"
 	"os"
-	"path"
+	"slices"
+	"strings"
 
-	"github.com/ddev/ddev/pkg/exec"
-	"github.com/ddev/ddev/pkg/fileutil"
-	"github.com/ddev/ddev/pkg/nodeps"
+	"github.com/ddev/ddev/pkg/ddevapp"
+	"github.com/ddev/ddev/pkg/diagnostics"
 	"github.com/ddev/ddev/pkg/output"
 	"github.com/ddev/ddev/pkg/util"
+	"github.com/ddev/ddev/pkg/version"
 	"github.com/spf13/cobra"
 )
 
@@ -18,62 +20,154 @@ var DiagnoseCmd = &cobra.Command{
 	Short: "Diagnose common DDEV issues with concise, actionable output",
 	Long: `Run quick diagnostics on your DDEV installation and current project.
 This command checks:
-- Docker environment and connectivity
-- Network configuration
-- HTTPS/mkcert setup
-- Current project health (if in a project directory)
+- Docker provider, buildx, containers and disk space
+- Network ports, DNS, internet access and proxies
+- HTTPS/mkcert setup and certificates
+- Current project health, Xdebug and Mutagen (if in a project directory)
+- Checks shipped by add-ons in .ddev/diagnostics
 
-For comprehensive output suitable for issue reports, use 'ddev utility test' instead.`,
+For comprehensive output suitable for issue reports, use 'ddev utility test' instead.
+
+Use --check or --category to run some of the checks, and --format to get
+a JSON or JUnit XML report.
+
+With --fix, each failing check that has a known remediation shows the exact
+action it will take, asks for confirmation, applies it and runs the check
+again to confirm that it worked. With --yes the fixes are applied without
+asking, which --format=json and --format=junit require.`,
 	Example: `ddev utility diagnose
 ddev ut diagnose
-DDEV_DIAGNOSE_FULL=true ddev utility diagnose  # Include test project creation`,
+DDEV_DIAGNOSE_FULL=true ddev utility diagnose  # Include test project creation
+ddev utility diagnose --list
+ddev utility diagnose --format=json
+ddev utility diagnose --category=docker,tls --format=junit > ddev-diagnose.xml
+ddev utility diagnose --check=network.ports
+ddev utility diagnose --fix
+ddev utility diagnose --category=network --fix
+ddev utility diagnose --fix --yes --format=json`,
 	Run: func(cmd *cobra.Command, args []string) {
 		if len(args) != 0 {
 			util.Failed("This command takes no additional arguments")
 		}
 
-		bashPath := util.FindBashPath()
-		tmpDir := os.TempDir()
+		format, _ := cmd.Flags().GetString("format")
+		ids, _ := cmd.Flags().GetStringSlice("check")
+		categories, _ := cmd.Flags().GetStringSlice("category")
+		list, _ := cmd.Flags().GetBool("list")
+		fix, _ := cmd.Flags().GetBool("fix")
+		yes, _ := cmd.Flags().GetBool("yes")
+		os.Exit(runDiagnosticChecks(format, ids, categories, list, fix, yes))
+	},
+}
 
-		// Copy embedded script to temp directory
-		err := fileutil.CopyEmbedAssets(bundledAssets, "scripts", tmpDir, nil)
+// runDiagnosticChecks runs the registered and project checks and writes the report
+// With fix, the fixes of failed checks are offered and the checks run again,
+// with yes they are applied without asking.
+// Returns exit code: 0 if no error-severity check failed, 1 otherwise
+func runDiagnosticChecks(format string, ids []string, categories []string, list bool, fix bool, yes bool) int {
+	if !slices.Contains(diagnostics.ValidFormats, format) {
+		util.Failed("Invalid --format=%s, valid formats are %s", format, strings.Join(diagnostics.ValidFormats, ", "))
+	}
+	// The json and junit reports go to stdout, where they can't be mixed with prompts
+	if fix && format != diagnostics.FormatText && !yes {
+		util.Failed("--fix with --format=%s needs --yes, the fixes can't be confirmed interactively", format)
+	}
+
+	// Load the project from its config rather than with GetActiveApp(),
+	// which needs a working Docker provider
+	ctx := &diagnostics.Context{}
+	if appRoot, err := ddevapp.GetActiveAppRoot(""); err == nil {
+		app, err := ddevapp.NewApp(appRoot, true)
 		if err != nil {
-			util.Failed("Failed to copy diagnose_ddev.sh to %s: %v", tmpDir, err)
+			util.Warning("Unable to load project in %s: %v", appRoot, err)
+		} else {
+			ctx.App = app
 		}
+	}
+
+	checks := diagnostics.Registered()
+	projectChecks, err := diagnostics.LoadProjectChecks(ctx.App, checks)
+	if err != nil {
+		util.Warning("Unable to load checks from .ddev/%s: %v", diagnostics.ProjectChecksDir, err)
+	}
+	checks = append(checks, projectChecks...)
 
-		scriptPath := tmpDir
-		if nodeps.IsWindows() {
-			if !fileutil.FileExists(bashPath) {
-				util.Failed("%s does not exist, please install git-bash to use 'ddev utility diagnose'", bashPath)
-			}
-			scriptPath = util.WindowsPathToCygwinPath(tmpDir)
+	if list {
+		var b strings.Builder
+		for _, c := range checks {
+			_, _ = fmt.Fprintf(&b, "%-22s %-10s %-8s %s\n", c.ID(), c.Category(), c.Severity(), c.Description())
 		}
+		output.UserOut.Print(b.String())
+		return 0
+	}
 
-		c := []string{"-c", path.Join(scriptPath, "diagnose_ddev.sh")}
-		util.Debug("Running %s %v", bashPath, c)
+	selected, err := diagnostics.Select(checks, ids, categories)
+	if err != nil {
+		util.Failed("%v", err)
+	}
+	if format == diagnostics.FormatText {
+		printDiagnoseHeader(ctx.App)
+	}
 
-		// Show introductory message
-		output.UserOut.Println("Running DDEV diagnostics...")
-		output.UserOut.Println()
-		util.Success("Quick troubleshooting tips:")
-		util.Success("* Use latest stable DDEV version")
-		util.Success("* ddev poweroff && ddev start")
-		util.Success("* Restart Docker Provider")
-		util.Success("* Temporarily disable VPN and firewall")
-		util.Success("* Troubleshooting guide: https://docs.ddev.com/en/stable/users/usage/troubleshooting/")
-		output.UserOut.Println()
-		output.UserOut.Println("For comprehensive output for issue reports, use 'ddev utility test' instead.")
+	// Keep the output of the checks and fixes, like the test project's
+	// start, out of a json or junit report on stdout
+	origStdout, origOut := os.Stdout, output.UserOut.Out
+	if format != diagnostics.FormatText {
+		os.Stdout, output.UserOut.Out = os.Stderr, os.Stderr
+	}
+	results := diagnostics.Run(ctx, selected)
+	if fix {
+		results = diagnostics.RunFixes(ctx, selected, results, func(action string) bool {
+			output.UserOut.Printf("Fix: %s\n", action)
+			return yes || util.ConfirmTo("Apply this fix?", false)
+		})
+	}
+	os.Stdout, output.UserOut.Out = origStdout, origOut
+
+	if err = diagnostics.WriteReport(os.Stdout, format, results); err != nil {
+		util.Failed("Failed to write report: %v", err)
+	}
+	failed := diagnostics.Failed(results, diagnostics.SeverityError)
+	if format == diagnostics.FormatText && diagnostics.Failed(results, diagnostics.SeverityWarning) {
 		output.UserOut.Println()
+		output.UserOut.Println("Next steps:")
+		output.UserOut.Println("  * Review the issues and fixes above, or try 'ddev utility diagnose --fix'")
+		output.UserOut.Println("  * Troubleshooting guide: https://docs.ddev.com/en/stable/users/usage/troubleshooting/")
+		output.UserOut.Println("  * For comprehensive output for issue reports, use 'ddev utility test'")
+		output.UserOut.Println("  * Get help on Discord: https://ddev.com/s/discord")
+	}
+	if failed {
+		return 1
+	}
+	return 0
+}
 
-		// Run the script with output to stdout
-		err = exec.RunInteractiveCommandWithOutput(bashPath, c, os.Stdout)
-		if err != nil {
-			// Script already printed diagnostic info, just exit with error code
-			os.Exit(1)
-		}
-	},
+// printDiagnoseHeader shows the environment and the project the checks run in
+func printDiagnoseHeader(app *ddevapp.DdevApp) {
+	output.UserOut.Println("DDEV Diagnostic Report")
+	output.UserOut.Println()
+	output.UserOut.Println("Environment")
+	versionInfo, _ := version.GetVersionInfo()
+	output.UserOut.Printf("  DDEV version: %s", versionInfo["DDEV version"])
+	output.UserOut.Printf("  OS: %s %s", versionInfo["os"], versionInfo["architecture"])
+	output.UserOut.Printf("  Docker provider: %s", versionInfo["docker-platform"])
+	output.UserOut.Printf("  Docker version: %s", versionInfo["docker"])
+	if app != nil {
+		status, _ := app.SiteStatus()
+		output.UserOut.Println()
+		output.UserOut.Println("Current Project")
+		output.UserOut.Printf("  Name: %s", app.Name)
+		output.UserOut.Printf("  Type: %s", app.Type)
+		output.UserOut.Printf("  Status: %s", status)
+	}
 }
 
 func init() {
+	DiagnoseCmd.Flags().String("format", diagnostics.FormatText, fmt.Sprintf("Write the report in this format: %s", strings.Join(diagnostics.ValidFormats, ", ")))
+	DiagnoseCmd.Flags().StringSlice("check", nil, "Run only these checks, by id")
+	DiagnoseCmd.Flags().StringSlice("category", nil, "Run only the checks in these categories")
+	DiagnoseCmd.Flags().Bool("list", false, "List the available checks")
+	DiagnoseCmd.Flags().Bool("fix", false, "Offer to fix failing checks, asking for confirmation before each fix")
+	DiagnoseCmd.Flags().BoolP("yes", "y", false, "Apply the fixes offered by --fix without asking, required with --format=json or junit")
 	DebugCmd.AddCommand(DiagnoseCmd)
 }
@@ -31,7 +31,8 @@ func TestUtilityDiagnoseCmd(t *testing.T) {
 		// May fail due to not being in project, but should produce output
 		require.Contains(t, out, "DDEV Diagnostic Report")
 		require.Contains(t, out, "Environment")
-		require.Contains(t, out, "Docker Environment")
+		require.Contains(t, out, "docker.provider")
+		require.Contains(t, out, "project.http: ")
 	})
 
 	// Test with basic project
@@ -53,6 +54,14 @@ func TestUtilityDiagnoseCmd(t *testing.T) {
 		require.Contains(t, out, "Type: php")
 	})
 
+	// Test the report formats
+	t.Run("Formats", func(t *testing.T) {
+		out, err := exec.RunHostCommand(DdevBin, "utility", "diagnose", "--check=network.proxy,project.location", "--format=json")
+		require.NoError(t, err, out)
+		require.Contains(t, out, `"id": "network.proxy"`)
+		require.NotContains(t, out, "DDEV Diagnostic Report")
+	})
+
 	// Test with customizations
 	t.Run("ProjectWithCustomizations", func(t *testing.T) {
 		origDir, _ := os.Getwd()
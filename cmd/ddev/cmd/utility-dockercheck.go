@@ -27,202 +27,230 @@ ddev ut dockercheck`,
 			util.Failed("This command takes no additional arguments")
 		}
 
-		hasWarnings := false
+		if exitCode := runDockercheck(); exitCode != 0 {
+			output.UserErr.Exit(exitCode)
+		}
+	},
+}
 
-		_, buildxErr := dockerutil.DownloadDockerBuildxIfNeeded()
+// runDockercheck checks the Docker provider and outputs results
+// Returns exit code: 0 if no issues, 1 if issues found
+func runDockercheck() int {
+	hasWarnings := false
 
-		versionInfo, _ := version.GetVersionInfo()
-		bashPath := util.FindBashPath()
-		util.Success("Docker platform: %v", versionInfo["docker-platform"])
-		switch versionInfo["docker-platform"] {
-		case "colima":
-			p, err := exec.LookPath("colima")
+	_, buildxErr := dockerutil.DownloadDockerBuildxIfNeeded()
+
+	versionInfo, _ := version.GetVersionInfo()
+	bashPath := util.FindBashPath()
+	util.Success("Docker platform: %v", versionInfo["docker-platform"])
+	switch versionInfo["docker-platform"] {
+	case "colima":
+		p, err := exec.LookPath("colima")
+		if err == nil {
+			out, err := exec2.RunHostCommand(bashPath, "-c", fmt.Sprintf("%s --version | awk '{print $3}'", p))
+			out = strings.Trim(out, "\r\n ")
 			if err == nil {
-				out, err := exec2.RunHostCommand(bashPath, "-c", fmt.Sprintf("%s --version | awk '{print $3}'", p))
-				out = strings.Trim(out, "\r\n ")
-				if err == nil {
-					util.Success("Colima version: %v", out)
-				}
+				util.Success("Colima version: %v", out)
 			}
-		case "lima":
-			p, err := exec.LookPath("limactl")
+		}
+	case "lima":
+		p, err := exec.LookPath("limactl")
+		if err == nil {
+			out, err := exec2.RunHostCommand(bashPath, "-c", fmt.Sprintf("%s --version | awk '{print $3}'", p))
+			out = strings.Trim(out, "\r\n ")
 			if err == nil {
-				out, err := exec2.RunHostCommand(bashPath, "-c", fmt.Sprintf("%s --version | awk '{print $3}'", p))
-				out = strings.Trim(out, "\r\n ")
-				if err == nil {
-					util.Success("Lima version: %v", out)
-				}
+				util.Success("Lima version: %v", out)
 			}
-		case "orbstack":
-			p, err := exec.LookPath("orb")
+		}
+	case "orbstack":
+		p, err := exec.LookPath("orb")
+		if err == nil {
+			out, err := exec2.RunHostCommand(bashPath, "-c", fmt.Sprintf("%s version | awk '/Version/ {print $2}'", p))
+			out = strings.Trim(out, "\r\n ")
 			if err == nil {
-				out, err := exec2.RunHostCommand(bashPath, "-c", fmt.Sprintf("%s version | awk '/Version/ {print $2}'", p))
-				out = strings.Trim(out, "\r\n ")
-				if err == nil {
-					util.Success("OrbStack version: %v", out)
-				}
+				util.Success("OrbStack version: %v", out)
 			}
-		case "rancher-desktop":
-			p, err := exec.LookPath("rdctl")
+		}
+	case "rancher-desktop":
+		p, err := exec.LookPath("rdctl")
+		if err == nil {
+			out, err := exec2.RunHostCommand(bashPath, "-c", fmt.Sprintf("%s version | awk '/version/ {print $4}'", p))
+			out = strings.Trim(out, "\r\n, ")
 			if err == nil {
-				out, err := exec2.RunHostCommand(bashPath, "-c", fmt.Sprintf("%s version | awk '/version/ {print $4}'", p))
-				out = strings.Trim(out, "\r\n, ")
-				if err == nil {
-					util.Success("Rancher Desktop version: %v", out)
-				}
+				util.Success("Rancher Desktop version: %v", out)
 			}
+		}
 
-		case "docker-desktop":
-			p, err := exec.LookPath("docker")
+	case "docker-desktop":
+		p, err := exec.LookPath("docker")
+		if err == nil {
+			out, err := exec2.RunHostCommand(bashPath, "-c", fmt.Sprintf("%s version | awk '/^Server/ {print $4}'", p))
+			out = strings.Trim(out, "\r\n, ")
 			if err == nil {
-				out, err := exec2.RunHostCommand(bashPath, "-c", fmt.Sprintf("%s version | awk '/^Server/ {print $4}'", p))
-				out = strings.Trim(out, "\r\n, ")
-				if err == nil {
-					util.Success("Docker Desktop version: %v", out)
-				}
+				util.Success("Docker Desktop version: %v", out)
 			}
 		}
+	}
 
-		buildxCheckErr := dockerutil.CheckDockerBuildxVersion()
-		if buildxCheckErr != nil {
-			util.Warning("Docker buildx version check: %v", buildxCheckErr)
-			hasWarnings = true
-		} else {
-			buildxVersion, _ := dockerutil.GetDockerBuildxVersion()
-			buildxLocation, _ := dockerutil.GetDockerBuildxLocation()
-			util.Success("docker buildx version %s (%s) meets requirements for minimum %s", buildxVersion, buildxLocation, versionconstants.DockerBuildxMinVersion)
-			if globalconfig.GetRequiredDockerBuildxVersion() != "" {
-				util.Warning("Using a specific docker-buildx plugin as specified in %q", globalconfig.GetGlobalConfigPath())
-			}
+	buildxCheckErr := dockerutil.CheckDockerBuildxVersion()
+	if buildxCheckErr != nil {
+		util.Warning("Docker buildx version check: %v", buildxCheckErr)
+		hasWarnings = true
+	} else {
+		buildxVersion, _ := dockerutil.GetDockerBuildxVersion()
+		buildxLocation, _ := dockerutil.GetDockerBuildxLocation()
+		util.Success("docker buildx version %s (%s) meets requirements for minimum %s", buildxVersion, buildxLocation, versionconstants.DockerBuildxMinVersion)
+		if globalconfig.GetRequiredDockerBuildxVersion() != "" {
+			util.Warning("Using a specific docker-buildx plugin as specified in %q", globalconfig.GetGlobalConfigPath())
 		}
+	}
 
-		dockerContextName, dockerHost, err := dockerutil.GetDockerContextNameAndHost()
-		if err != nil {
-			util.Warning("Could not get Docker context and host: %v", err)
-			hasWarnings = true
-		} else {
-			util.Success("Using Docker context: %s", dockerContextName)
-			dockerContextName = os.Getenv("DOCKER_CONTEXT")
-			if dockerContextName != "" {
-				util.Success("From DOCKER_CONTEXT=%s", dockerContextName)
-			}
-
-			util.Success("Using Docker host: %s", dockerHost)
-			dockerHost = os.Getenv("DOCKER_HOST")
-			if dockerHost != "" {
-				util.Success("From DOCKER_HOST=%s", dockerHost)
-			}
+	dockerContextName, dockerHost, err := dockerutil.GetDockerContextNameAndHost()
+	if err != nil {
+		util.Warning("Could not get Docker context and host: %v", err)
+		hasWarnings = true
+	} else {
+		util.Success("Using Docker context: %s", dockerContextName)
+		dockerContextName = os.Getenv("DOCKER_CONTEXT")
+		if dockerContextName != "" {
+			util.Success("From DOCKER_CONTEXT=%s", dockerContextName)
 		}
 
-		// Show TLS configuration
-		dockerTLSVerify := os.Getenv("DOCKER_TLS_VERIFY")
-		dockerTLS := os.Getenv("DOCKER_TLS")
-		dockerCertPath := os.Getenv("DOCKER_CERT_PATH")
-		if dockerTLSVerify != "" {
-			util.Success("DOCKER_TLS_VERIFY=%s (TLS enabled with verification)", dockerTLSVerify)
-		} else if dockerTLS != "" {
-			util.Success("DOCKER_TLS=%s (TLS enabled without verification)", dockerTLS)
-		} else {
-			util.Success("TLS not configured (no DOCKER_TLS_VERIFY or DOCKER_TLS)")
-		}
-		if dockerCertPath != "" {
-			util.Success("DOCKER_CERT_PATH=%s", dockerCertPath)
+		util.Success("Using Docker host: %s", dockerHost)
+		dockerHost = os.Getenv("DOCKER_HOST")
+		if dockerHost != "" {
+			util.Success("From DOCKER_HOST=%s", dockerHost)
 		}
+	}
 
-		dockerVersion, err := dockerutil.GetDockerVersion()
-		if err != nil {
-			util.Warning("Unable to get Docker version: %v", err)
-			hasWarnings = true
-		} else {
-			util.Success("Docker version: %s", dockerVersion)
-		}
-		err = dockerutil.CheckDockerVersion(dockerutil.DockerRequirements)
-		if err != nil {
-			if err.Error() == "no docker" {
-				util.Warning("Docker is not installed or the Docker client is not available in the $PATH")
-			} else {
-				util.WarningOnce("Problem with your Docker provider: %v.", err)
-			}
-			hasWarnings = true
-		}
-		dockerAPIVersion, err := dockerutil.GetDockerAPIVersion()
-		if err != nil {
-			util.Warning("Unable to get Docker API version: %v", err)
-			hasWarnings = true
-		} else {
-			util.Success("Docker API version: %s", dockerAPIVersion)
-		}
+	// Show TLS configuration
+	dockerTLSVerify := os.Getenv("DOCKER_TLS_VERIFY")
+	dockerTLS := os.Getenv("DOCKER_TLS")
+	dockerCertPath := os.Getenv("DOCKER_CERT_PATH")
+	if dockerTLSVerify != "" {
+		util.Success("DOCKER_TLS_VERIFY=%s (TLS enabled with verification)", dockerTLSVerify)
+	} else if dockerTLS != "" {
+		util.Success("DOCKER_TLS=%s (TLS enabled without verification)", dockerTLS)
+	} else {
+		util.Success("TLS not configured (no DOCKER_TLS_VERIFY or DOCKER_TLS)")
+	}
+	if dockerCertPath != "" {
+		util.Success("DOCKER_CERT_PATH=%s", dockerCertPath)
+	}
 
-		uid, _, _ := dockerutil.GetContainerUser()
-		_, out, err := dockerutil.RunSimpleContainer(versionconstants.UtilitiesImage, "dockercheck-runcontainer--"+util.RandString(6), []string{"ls", "/mnt/ddev-global-cache"}, []string{}, []string{}, []string{"ddev-global-cache" + ":/mnt/ddev-global-cache"}, uid, true, false, nil, nil, nil)
-		if err != nil {
-			util.Warning("Unable to run simple container: %v; output=%s", err, out)
-			hasWarnings = true
+	dockerVersion, err := dockerutil.GetDockerVersion()
+	if err != nil {
+		util.Warning("Unable to get Docker version: %v", err)
+		hasWarnings = true
+	} else {
+		util.Success("Docker version: %s", dockerVersion)
+	}
+	err = dockerutil.CheckDockerVersion(dockerutil.DockerRequirements)
+	if err != nil {
+		if err.Error() == "no docker" {
+			util.Warning("Docker is not installed or the Docker client is not available in the $PATH")
 		} else {
-			util.Success("Able to run simple container that mounts a volume.")
+			util.WarningOnce("Problem with your Docker provider: %v.", err)
 		}
+		hasWarnings = true
+	}
+	dockerAPIVersion, err := dockerutil.GetDockerAPIVersion()
+	if err != nil {
+		util.Warning("Unable to get Docker API version: %v", err)
+		hasWarnings = true
+	} else {
+		util.Success("Docker API version: %s", dockerAPIVersion)
+	}
 
-		_, _, err = dockerutil.RunSimpleContainer(versionconstants.UtilitiesImage, "dockercheck-curl--"+util.RandString(6), []string{"curl", "-sfLI", "https://google.com"}, []string{}, []string{}, []string{"ddev-global-cache" + ":/mnt/ddev-global-cache/bashhistory"}, uid, true, false, nil, nil, nil)
-		if err != nil {
-			util.Warning("Unable to run use internet inside container, many things will fail: %v", err)
-			hasWarnings = true
-		} else {
-			util.Success("Able to use internet inside container.")
-		}
+	if out, err := checkSimpleContainer(); err != nil {
+		util.Warning("Unable to run simple container: %v; output=%s", err, out)
+		hasWarnings = true
+	} else {
+		util.Success("Able to run simple container that mounts a volume.")
+	}
 
-		if err := dockerutil.CheckAvailableSpace(); err != nil {
-			util.Warning("Warning: %v", err)
-			hasWarnings = true
-		}
+	if err := checkContainerInternet(); err != nil {
+		util.Warning("Unable to run use internet inside container, many things will fail: %v", err)
+		hasWarnings = true
+	} else {
+		util.Success("Able to use internet inside container.")
+	}
 
-		// Test buildx with a trivial build on the host, and --load the result.
-		//
-		// --load is essential, not incidental. Under the docker-container
-		// driver the build result otherwise stays in the build cache and is
-		// never handed to the engine ("WARNING: No output specified with
-		// docker-container driver"), so without it this check passes on a
-		// machine where every real project build fails at its final step. That
-		// happens, for example, when an image signature policy in policy.json
-		// rejects the docker-archive transport: the build succeeds in full and
-		// only the load is rejected. Loading is also what makes the cleanup
-		// below meaningful; before --load there was never an image to remove.
-		if buildxErr == nil {
-			// Use RunCLIPluginCommand to execute buildx build via Docker CLI plugin infrastructure
-			stdin := strings.NewReader(fmt.Sprintf("FROM %s", versionconstants.UtilitiesImage))
-			out, err = dockerutil.RunCLIPluginCommand("buildx", stdin, "build", "--no-cache", "--load", "-f-", "-t", "ddev-buildx-test:latest", ".")
-			if err != nil {
-				util.Warning("Unable to perform trivial build and load with buildx: %v; output=%s", err, out)
-				hasWarnings = true
-			} else {
-				// Clean up the test image using Docker API
-				cleanupErr := dockerutil.RemoveImage("ddev-buildx-test:latest")
-				if cleanupErr != nil {
-					util.Debug("Failed to clean up test image: %v", cleanupErr)
-				}
-				util.Success("docker buildx is working correctly (trivial build and load succeeded)")
-			}
-		} else {
-			util.Warning("Skipping buildx test due to earlier buildx version check error.")
-			hasWarnings = true
-		}
+	if err := dockerutil.CheckAvailableSpace(); err != nil {
+		util.Warning("Warning: %v", err)
+		hasWarnings = true
+	}
 
-		// Check docker auth configuration
-		err = dockerutil.CheckDockerAuth()
-		if err != nil {
-			util.Warning("Docker authentication may have issues: %v", err)
+	if buildxErr == nil {
+		if out, err := checkBuildxBuild(); err != nil {
+			util.Warning("Unable to perform trivial build and load with buildx: %v; output=%s", err, out)
 			hasWarnings = true
 		} else {
-			util.Success("Docker authentication is configured correctly")
+			util.Success("docker buildx is working correctly (trivial build and load succeeded)")
 		}
+	} else {
+		util.Warning("Skipping buildx test due to earlier buildx version check error.")
+		hasWarnings = true
+	}
 
-		if hasWarnings {
-			util.Error("Docker provider checks completed with warnings. Please address the issues above.")
-			if buildxErr != nil {
-				util.Error("Docker buildx error: %v", buildxErr)
-			}
-			output.UserErr.Exit(1)
+	// Check docker auth configuration
+	err = dockerutil.CheckDockerAuth()
+	if err != nil {
+		util.Warning("Docker authentication may have issues: %v", err)
+		hasWarnings = true
+	} else {
+		util.Success("Docker authentication is configured correctly")
+	}
+
+	if hasWarnings {
+		util.Error("Docker provider checks completed with warnings. Please address the issues above.")
+		if buildxErr != nil {
+			util.Error("Docker buildx error: %v", buildxErr)
 		}
-	},
+		return 1
+	}
+	return 0
+}
+
+// checkSimpleContainer runs a container that mounts a volume, returning
+// its output on failure
+func checkSimpleContainer() (string, error) {
+	uid, _, _ := dockerutil.GetContainerUser()
+	_, out, err := dockerutil.RunSimpleContainer(versionconstants.UtilitiesImage, "dockercheck-runcontainer--"+util.RandString(6), []string{"ls", "/mnt/ddev-global-cache"}, []string{}, []string{}, []string{"ddev-global-cache" + ":/mnt/ddev-global-cache"}, uid, true, false, nil, nil, nil)
+	return out, err
+}
+
+// checkContainerInternet checks that a container can reach the internet
+func checkContainerInternet() error {
+	uid, _, _ := dockerutil.GetContainerUser()
+	_, _, err := dockerutil.RunSimpleContainer(versionconstants.UtilitiesImage, "dockercheck-curl--"+util.RandString(6), []string{"curl", "-sfLI", "https://google.com"}, []string{}, []string{}, []string{"ddev-global-cache" + ":/mnt/ddev-global-cache/bashhistory"}, uid, true, false, nil, nil, nil)
+	return err
+}
+
+// checkBuildxBuild does a trivial buildx build on the host and --loads the
+// result, returning the build output on failure.
+//
+// --load is essential, not incidental. Under the docker-container
+// driver the build result otherwise stays in the build cache and is
+// never handed to the engine ("WARNING: No output specified with
+// docker-container driver"), so without it this check passes on a
+// machine where every real project build fails at its final step. That
+// happens, for example, when an image signature policy in policy.json
+// rejects the docker-archive transport: the build succeeds in full and
+// only the load is rejected. Loading is also what makes the cleanup
+// below meaningful; before --load there was never an image to remove.
+func checkBuildxBuild() (string, error) {
+	// Use RunCLIPluginCommand to execute buildx build via Docker CLI plugin infrastructure
+	stdin := strings.NewReader(fmt.Sprintf("FROM %s", versionconstants.UtilitiesImage))
+	out, err := dockerutil.RunCLIPluginCommand("buildx", stdin, "build", "--no-cache", "--load", "-f-", "-t", "ddev-buildx-test:latest", ".")
+	if err != nil {
+		return out, err
+	}
+	// Clean up the test image using Docker API
+	if cleanupErr := dockerutil.RemoveImage("ddev-buildx-test:latest"); cleanupErr != nil {
+		util.Debug("Failed to clean up test image: %v", cleanupErr)
+	}
+	return "", nil
 }
 
 func init() {
@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"slices"
+	"syscall"
+	"time"
+
+	"github.com/ddev/ddev/pkg/ddevapp"
+	"github.com/ddev/ddev/pkg/globalconfig"
+	"github.com/ddev/ddev/pkg/idlemanager"
+	"github.com/ddev/ddev/pkg/output"
+	"github.com/ddev/ddev/pkg/styles"
+	"github.com/ddev/ddev/pkg/util"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+)
+
+// DebugIdleManagerCmd implements the ddev utility idle-manager command
+var DebugIdleManagerCmd = &cobra.Command{
+	Use:   "idle-manager [command]",
+	Short: "Manage the idle manager that stops unused projects",
+	Long: `Manage the idle manager that stops the projects without HTTP requests,
+'ddev exec' or 'ddev ssh' for idle_timeout, and starts them again when they're
+requested. It runs on the host when idle_timeout is set with
+'ddev config global --idle-timeout=2h'.`,
+	Run: func(cmd *cobra.Command, _ []string) {
+		err := cmd.Usage()
+		util.CheckErr(err)
+	},
+}
+
+// idleProjectStatus is a row of ddev utility idle-manager status
+type idleProjectStatus struct {
+	Name         string    `json:"name"`
+	Status       string    `json:"status"`
+	LastActivity time.Time `json:"last_activity,omitzero"`
+	StopsAt      time.Time `json:"stops_at,omitzero"`
+	KeepRunning  bool      `json:"keep_running"`
+}
+
+// DebugIdleManagerStatusCmd implements the ddev utility idle-manager status command
+var DebugIdleManagerStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the last activity of projects and when they stop",
+	Example: `ddev utility idle-manager status
+ddev utility idle-manager status -j`,
+	Args: cobra.NoArgs,
+	Run: func(_ *cobra.Command, _ []string) {
+		if !idlemanager.IsEnabled() {
+			util.Failed("The idle manager is disabled, enable it with 'ddev config global --idle-timeout=2h'")
+		}
+		if !idlemanager.IsRunning() {
+			util.Warning("The idle manager isn't running, it starts with the next 'ddev start'")
+		}
+
+		apps, err := ddevapp.GetProjects(false)
+		if err != nil {
+			util.Failed("Failed to list projects: %v", err)
+		}
+		stopped := idlemanager.StoppedProjects()
+		var rows []idleProjectStatus
+		for _, app := range apps {
+			status, _ := app.SiteStatus()
+			row := idleProjectStatus{Name: app.Name, Status: status, KeepRunning: idlemanager.KeepRunning(app.Name)}
+			if status != ddevapp.SiteRunning {
+				// Only show the stopped projects the idle manager starts on request
+				if !slices.Contains(stopped, app.Name) {
+					continue
+				}
+				row.Status = "stopped by idle manager"
+			}
+			if last, ok := idlemanager.LastActivity(app.Name); ok {
+				row.LastActivity = last
+				if status == ddevapp.SiteRunning && !row.KeepRunning {
+					row.StopsAt = last.Add(idlemanager.Timeout())
+				}
+			}
+			rows = append(rows, row)
+		}
+
+		t := table.NewWriter()
+		styles.SetGlobalTableStyle(t, false)
+		t.AppendHeader(table.Row{"Project", "Status", "Last activity", "Stops"})
+		for _, row := range rows {
+			last, stops := "", ""
+			if !row.LastActivity.IsZero() {
+				last = row.LastActivity.Local().Format("2006-01-02 15:04")
+			}
+			switch {
+			case row.KeepRunning:
+				stops = "never (keep_running)"
+			case !row.StopsAt.IsZero():
+				stops = "in " + time.Until(row.StopsAt).Round(time.Minute).String()
+				if time.Now().After(row.StopsAt) {
+					stops = "at the next check"
+				}
+			}
+			t.AppendRow(table.Row{row.Name, row.Status, last, stops})
+		}
+		output.UserOut.WithField("raw", rows).Print(fmt.Sprintf("idle_timeout is %s, log is in %s\n", idlemanager.Timeout(), idlemanager.LogFilePath()) + t.Render())
+	},
+}
+
+// DebugIdleManagerStartCmd implements the ddev utility idle-manager start command
+var DebugIdleManagerStartCmd = &cobra.Command{
+	Use:   "start",
+	Short: "Start the idle manager in the background",
+	Args:  cobra.NoArgs,
+	Run: func(_ *cobra.Command, _ []string) {
+		if !idlemanager.IsEnabled() {
+			util.Failed("The idle manager is disabled, enable it with 'ddev config global --idle-timeout=2h'")
+		}
+		if err := idlemanager.Start(); err != nil {
+			util.Failed("%v", err)
+		}
+		util.Success("The idle manager is running, log is in %s", idlemanager.LogFilePath())
+	},
+}
+
+// DebugIdleManagerStopCmd implements the ddev utility idle-manager stop command
+var DebugIdleManagerStopCmd = &cobra.Command{
+	Use:   "stop",
+	Short: "Stop the idle manager",
+	Args:  cobra.NoArgs,
+	Run: func(_ *cobra.Command, _ []string) {
+		if err := idlemanager.Stop(); err != nil {
+			util.Failed("Failed to stop the idle manager: %v", err)
+		}
+		util.Success("The idle manager has been stopped.")
+	},
+}
+
+// DebugIdleManagerServeCmd implements the ddev utility idle-manager serve
+// command, which is the background process started by the start command
+var DebugIdleManagerServeCmd = &cobra.Command{
+	Use:    "serve",
+	Short:  "Run the idle manager in the foreground",
+	Hidden: true,
+	Args:   cobra.NoArgs,
+	Run: func(_ *cobra.Command, _ []string) {
+		listener, err := idlemanager.Listen()
+		if err != nil {
+			util.Failed("Unable to listen on port %d: %v", idlemanager.DefaultWakePort, err)
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		logf := func(format string, a ...any) {
+			fmt.Printf("%s %s\n", time.Now().Format(time.RFC3339), fmt.Sprintf(format, a...))
+		}
+		logf("Listening on %s, idle_timeout is %s", listener.Addr(), globalconfig.DdevGlobalConfig.IdleTimeout)
+		if err = ddevapp.RunIdleManager(ctx, listener, logf); err != nil {
+			util.Failed("The idle manager failed: %v", err)
+		}
+		logf("Stopped")
+	},
+}
+
+func init() {
+	DebugIdleManagerCmd.AddCommand(DebugIdleManagerStatusCmd)
+	DebugIdleManagerCmd.AddCommand(DebugIdleManagerStartCmd)
+	DebugIdleManagerCmd.AddCommand(DebugIdleManagerStopCmd)
+	DebugIdleManagerCmd.AddCommand(DebugIdleManagerServeCmd)
+	DebugCmd.AddCommand(DebugIdleManagerCmd)
+}
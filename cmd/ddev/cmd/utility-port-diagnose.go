@@ -76,20 +76,7 @@ type namedPort struct {
 // Returns 0 if all ports are available, 1 if any conflicts are found.
 func runPortDiagnose() int {
 	// Check for running DDEV projects or router first — they legitimately use ports.
-	activeProjects := ddevapp.GetActiveProjects()
-	router, _ := ddevapp.FindDdevRouter()
-	if len(activeProjects) > 0 || router != nil {
-		var reasons []string
-		if len(activeProjects) > 0 {
-			names := make([]string, 0, len(activeProjects))
-			for _, app := range activeProjects {
-				names = append(names, app.Name)
-			}
-			reasons = append(reasons, fmt.Sprintf("running projects: %s", strings.Join(names, ", ")))
-		}
-		if router != nil {
-			reasons = append(reasons, "ddev-router is running")
-		}
+	if reasons := activeDdevReasons(); len(reasons) > 0 {
 		output.UserErr.Printf("DDEV is currently active (%s).\n", strings.Join(reasons, "; "))
 		output.UserErr.Println("Running DDEV services use ports that will show as false conflicts.")
 		output.UserErr.Println("Please run 'ddev poweroff' first, then re-run this command.")
@@ -97,42 +84,13 @@ func runPortDiagnose() int {
 	}
 
 	app, err := ddevapp.GetActiveApp("")
-	var ports []namedPort
-	inProject := err == nil && app.AppRoot != ""
-
-	if inProject {
-		// Clear the cached rendered compose YAML so GetPrimaryRouterHTTP*Port reads
-		// the current project config rather than stale values from a previous start.
-		app.ComposeYaml = nil
+	if err == nil && app.AppRoot != "" {
 		output.UserOut.Printf("Port diagnostics for project: %s\n", app.Name)
-		httpPort := app.GetPrimaryRouterHTTPPort()
-		httpsPort := app.GetPrimaryRouterHTTPSPort()
-		mailpitHTTP := app.GetMailpitHTTPPort()
-		mailpitHTTPS := app.GetMailpitHTTPSPort()
-		xhguiHTTP := app.GetXHGuiHTTPPort()
-		xhguiHTTPS := app.GetXHGuiHTTPSPort()
-
-		for _, np := range []namedPort{
-			{httpPort, "router HTTP"},
-			{httpsPort, "router HTTPS"},
-			{mailpitHTTP, "Mailpit HTTP"},
-			{mailpitHTTPS, "Mailpit HTTPS"},
-			{xhguiHTTP, "XHGui HTTP"},
-			{xhguiHTTPS, "XHGui HTTPS"},
-		} {
-			if np.port != "" {
-				ports = append(ports, np)
-			}
-		}
 	} else {
-		httpPort := globalconfig.DdevGlobalConfig.RouterHTTPPort
-		httpsPort := globalconfig.DdevGlobalConfig.RouterHTTPSPort
-		output.UserOut.Printf("Not in a DDEV project directory — checking default ports %s and %s.\n", httpPort, httpsPort)
-		ports = []namedPort{
-			{httpPort, "HTTP"},
-			{httpsPort, "HTTPS"},
-		}
+		app = nil
+		output.UserOut.Printf("Not in a DDEV project directory — checking default ports %s and %s.\n", globalconfig.DdevGlobalConfig.RouterHTTPPort, globalconfig.DdevGlobalConfig.RouterHTTPSPort)
 	}
+	ports := portsToDiagnose(app)
 
 	// On non-Windows systems, some listeners (e.g. docker-proxy under rootful
 	// Docker CE) are owned by root and invisible without elevated privileges.
@@ -235,6 +193,51 @@ func runPortDiagnose() int {
 	return 0
 }
 
+// activeDdevReasons describes the running projects and router, whose ports
+// would show up as false conflicts
+func activeDdevReasons() []string {
+	var reasons []string
+	if activeProjects := ddevapp.GetActiveProjects(); len(activeProjects) > 0 {
+		names := make([]string, 0, len(activeProjects))
+		for _, app := range activeProjects {
+			names = append(names, app.Name)
+		}
+		reasons = append(reasons, fmt.Sprintf("running projects: %s", strings.Join(names, ", ")))
+	}
+	if router, _ := ddevapp.FindDdevRouter(); router != nil {
+		reasons = append(reasons, "ddev-router is running")
+	}
+	return reasons
+}
+
+// portsToDiagnose returns the host ports the project needs, or the default
+// router ports when app is nil
+func portsToDiagnose(app *ddevapp.DdevApp) []namedPort {
+	if app == nil {
+		return []namedPort{
+			{globalconfig.DdevGlobalConfig.RouterHTTPPort, "HTTP"},
+			{globalconfig.DdevGlobalConfig.RouterHTTPSPort, "HTTPS"},
+		}
+	}
+	// Clear the cached rendered compose YAML so GetPrimaryRouterHTTP*Port reads
+	// the current project config rather than stale values from a previous start.
+	app.ComposeYaml = nil
+	var ports []namedPort
+	for _, np := range []namedPort{
+		{app.GetPrimaryRouterHTTPPort(), "router HTTP"},
+		{app.GetPrimaryRouterHTTPSPort(), "router HTTPS"},
+		{app.GetMailpitHTTPPort(), "Mailpit HTTP"},
+		{app.GetMailpitHTTPSPort(), "Mailpit HTTPS"},
+		{app.GetXHGuiHTTPPort(), "XHGui HTTP"},
+		{app.GetXHGuiHTTPSPort(), "XHGui HTTPS"},
+	} {
+		if np.port != "" {
+			ports = append(ports, np)
+		}
+	}
+	return ports
+}
+
 // askSudoPermission explains the exact sudo commands that may be run and asks
 // the user whether to proceed. Returns true immediately if --allow-sudo was
 // passed. Returns false if sudo is unavailable, no elevation tool exists, or
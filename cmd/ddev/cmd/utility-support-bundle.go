@@ -0,0 +1,220 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ddev/ddev/pkg/ddevapp"
+	"github.com/ddev/ddev/pkg/dockerutil"
+	"github.com/ddev/ddev/pkg/exec"
+	"github.com/ddev/ddev/pkg/fileutil"
+	"github.com/ddev/ddev/pkg/globalconfig"
+	"github.com/ddev/ddev/pkg/nodeps"
+	"github.com/ddev/ddev/pkg/supportbundle"
+	"github.com/ddev/ddev/pkg/util"
+	"github.com/ddev/ddev/pkg/version"
+	"github.com/ddev/ddev/pkg/versionconstants"
+	"github.com/spf13/cobra"
+)
+
+// supportBundleLogLines is how many lines of each service log are collected
+const supportBundleLogLines = "1000"
+
+// UtilitySupportBundleCmd implements the ddev utility support-bundle command
+var UtilitySupportBundleCmd = &cobra.Command{
+	ValidArgsFunction: ddevapp.GetProjectNamesFunc("all", 1),
+	Use:               "support-bundle [project]",
+	Short:             "Collect a redacted tar.gz of troubleshooting information for issue reports",
+	Long: `Collect the information usually requested in issue reports into one tar.gz:
+the processed project configuration, the rendered docker-compose configuration,
+logs of each service and the router, router configuration errors, the rendered
+Traefik dynamic configuration, custom configuration, installed add-on manifests,
+Mutagen status, 'docker info', DDEV version information and the global configuration.
+
+Secrets in environment variables, .env files and provider configurations are
+masked by pattern, but please review the bundle before sharing it. The bundle
+contains a manifest.json listing everything that was included, and anything
+that could not be collected.`,
+	Example: `ddev utility support-bundle
+ddev utility support-bundle myproject
+ddev utility support-bundle --output=/tmp/ddev-support.tar.gz`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		app := supportBundleApp(args)
+
+		name := "ddev-support-" + time.Now().Format("20060102-150405")
+		if app != nil {
+			name = fmt.Sprintf("ddev-support-%s-%s", app.Name, time.Now().Format("20060102-150405"))
+		}
+		dest, _ := cmd.Flags().GetString("output")
+		if dest == "" {
+			dest = name + ".tar.gz"
+		}
+
+		bundle := collectSupportBundle(app)
+		if err := bundle.WriteFile(dest, name); err != nil {
+			util.Failed("Failed to write support bundle %s: %v", dest, err)
+		}
+
+		var failed []string
+		for _, e := range bundle.Manifest.Entries {
+			if e.Error != "" {
+				failed = append(failed, e.Path)
+			}
+		}
+		if len(failed) > 0 {
+			util.Warning("Unable to collect %s, see %s in the bundle for details", strings.Join(failed, ", "), supportbundle.ManifestName)
+		}
+		util.Success("Wrote support bundle %s with %d items. Secrets are masked by pattern, please review it before sharing.", dest, len(bundle.Manifest.Entries)-len(failed))
+	},
+}
+
+// supportBundleApp loads the named project, or the project in the current
+// directory, from its configuration so it works without a Docker provider.
+// It returns nil outside a project.
+func supportBundleApp(args []string) *ddevapp.DdevApp {
+	appRoot := ""
+	if len(args) == 1 {
+		p := globalconfig.GetProject(args[0])
+		if p == nil {
+			util.Failed("Project %s was not found, see 'ddev list'", args[0])
+		}
+		appRoot = p.AppRoot
+	} else if root, err := ddevapp.GetActiveAppRoot(""); err == nil {
+		appRoot = root
+	}
+	if appRoot == "" {
+		return nil
+	}
+	app, err := ddevapp.NewApp(appRoot, true)
+	if err != nil {
+		util.Warning("Unable to load project in %s: %v", appRoot, err)
+		return nil
+	}
+	return app
+}
+
+// collectSupportBundle gathers everything for the bundle. Failures are
+// recorded in the manifest so a broken Docker provider still gets a bundle.
+func collectSupportBundle(app *ddevapp.DdevApp) *supportbundle.Bundle {
+	projectName := ""
+	if app != nil {
+		projectName = app.Name
+	}
+	b := supportbundle.New(versionconstants.DdevVersion, projectName)
+
+	b.Collect("ddev/version.txt", "ddev version", func() (string, error) {
+		info, err := version.GetVersionInfo()
+		keys := make([]string, 0, len(info))
+		for k := range info {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		var s strings.Builder
+		for _, k := range keys {
+			_, _ = fmt.Fprintf(&s, "%s: %s\n", k, info[k])
+		}
+		return s.String(), err
+	})
+	b.AddFile("ddev/global_config.yaml", "Global configuration", globalconfig.GetGlobalConfigPath())
+
+	dockerInfo, dockerErr := dockerutil.GetDockerClientInfo()
+	b.Collect("docker/info.json", "docker info", func() (string, error) {
+		if dockerErr != nil {
+			return "", dockerErr
+		}
+		out, err := json.MarshalIndent(dockerInfo, "", "  ")
+		return string(out), err
+	})
+
+	b.Collect("router/status.txt", "ddev-router status", func() (string, error) {
+		status, logOutput := ddevapp.GetRouterStatus()
+		return fmt.Sprintf("%s\n%s", status, logOutput), nil
+	})
+	b.Collect("router/config-errors.txt", "ddev-router configuration errors", func() (string, error) {
+		return ddevapp.GetRouterConfigErrors(), nil
+	})
+	b.Collect("logs/ddev-router.log", "ddev-router logs", func() (string, error) {
+		// The router logs don't depend on the project
+		return (&ddevapp.DdevApp{}).CaptureLogs(nodeps.RouterContainer, true, supportBundleLogLines)
+	})
+	addSupportBundleDir(b, "router/traefik", "Rendered Traefik dynamic configuration", filepath.Join(globalconfig.GetGlobalDdevDir(), "traefik", "config"), "*.yaml")
+
+	if app == nil {
+		return b
+	}
+
+	b.Collect("project/config.yaml", "ddev utility configyaml --full-yaml", func() (string, error) {
+		out, err := app.GetProcessedProjectConfigYAML()
+		return string(out), err
+	})
+	b.Collect("project/docker-compose.yaml", "ddev utility compose-config", func() (string, error) {
+		_ = app.DockerEnv()
+		if err := app.WriteDockerComposeYAML(); err != nil {
+			return "", err
+		}
+		return fileutil.ReadFileIntoString(app.DockerComposeFullRenderedYAMLPath())
+	})
+	b.Collect("project/custom-config.txt", "ddev utility check-custom-config --all", func() (string, error) {
+		message, _ := app.CheckCustomConfig(true)
+		return message, nil
+	})
+	addSupportBundleDir(b, "project/traefik", "Project Traefik configuration", app.GetConfigPath("traefik/config"), "*.yaml")
+	addSupportBundleDir(b, "project/addon-metadata", "Installed add-on manifest", app.GetConfigPath(ddevapp.AddonMetadataDir), "*/manifest.yaml")
+	addSupportBundleDir(b, "project/env", ".env file", app.GetConfigPath(""), ".env*")
+	addSupportBundleDir(b, "project/providers", "Provider configuration", app.GetConfigPath("providers"), "*.yaml")
+
+	if app.IsMutagenEnabled() {
+		b.Collect("mutagen/status.txt", "ddev mutagen status --verbose", func() (string, error) {
+			return exec.RunHostCommand(globalconfig.GetMutagenPath(), "sync", "list", "-l", ddevapp.MutagenSyncName(app.Name))
+		})
+	}
+
+	if dockerErr != nil {
+		b.AddError("logs", "Service logs", dockerErr)
+		return b
+	}
+	// Read the rendered compose file here rather than rely on the
+	// docker-compose.yaml collector having run
+	if err := app.ReadDockerComposeYAML(); err != nil {
+		b.AddError("logs", "Service logs", err)
+		return b
+	}
+	var services []string
+	for service := range app.ComposeYaml.Services {
+		services = append(services, service)
+	}
+	sort.Strings(services)
+	for _, service := range services {
+		b.Collect("logs/"+service+".log", "ddev logs -s "+service, func() (string, error) {
+			return app.CaptureLogs(service, true, supportBundleLogLines)
+		})
+	}
+	return b
+}
+
+// addSupportBundleDir adds the files matching pattern in dir under prefix
+func addSupportBundleDir(b *supportbundle.Bundle, prefix string, description string, dir string, pattern string) {
+	files, err := filepath.Glob(filepath.Join(dir, pattern))
+	if err != nil {
+		b.AddError(prefix, description, err)
+		return
+	}
+	sort.Strings(files)
+	for _, f := range files {
+		rel, err := filepath.Rel(dir, f)
+		if err != nil || fileutil.IsDirectory(f) {
+			continue
+		}
+		b.AddFile(prefix+"/"+filepath.ToSlash(rel), description, f)
+	}
+}
+
+func init() {
+	UtilitySupportBundleCmd.Flags().StringP("output", "o", "", "Path of the tar.gz to write, defaults to ddev-support-<project>-<timestamp>.tar.gz in the current directory")
+	DebugCmd.AddCommand(UtilitySupportBundleCmd)
+}
@@ -328,6 +328,30 @@ func verifyCATrustedByOS(caCert *x509.Certificate, caKey crypto.Signer) (bool, e
 	return verifyErr == nil, nil
 }
 
+// caTrustedByOS checks whether the OS actually trusts the mkcert CA in
+// caRoot: it signs an ephemeral leaf cert with the CA and verifies it
+// against the live system cert pool. This is the definitive diagnostic — it
+// matches the chain walk a browser performs and does not modify the system.
+func caTrustedByOS(caRoot string) (bool, error) {
+	caCertBytes, err := os.ReadFile(filepath.Join(caRoot, "rootCA.pem"))
+	if err != nil {
+		return false, fmt.Errorf("cannot read CA certificate: %w", err)
+	}
+	caKeyBytes, err := os.ReadFile(filepath.Join(caRoot, "rootCA-key.pem"))
+	if err != nil {
+		return false, fmt.Errorf("cannot read CA key: %w", err)
+	}
+	caCert, err := parseCertFromPEM(caCertBytes)
+	if err != nil {
+		return false, fmt.Errorf("cannot parse CA certificate: %w", err)
+	}
+	caKey, err := parsePKCS8Key(caKeyBytes)
+	if err != nil {
+		return false, err
+	}
+	return verifyCATrustedByOS(caCert, caKey)
+}
+
 // checkOSTrustStore checks whether the mkcert CA is trusted by the OS cert pool.
 // If the CA is not trusted, it warns about potential elevation and asks permission
 // before running mkcert -install.
@@ -356,31 +380,11 @@ func checkOSTrustStore(caRoot string) bool {
 		}
 	}
 
-	// Check whether the OS actually trusts the CA: generate an ephemeral leaf
-	// cert signed by our CA and verify it against the live system cert pool.
-	// This is the definitive diagnostic — it matches the chain walk a browser
-	// performs and does not modify the system.
-	caCertBytes, certReadErr := os.ReadFile(filepath.Join(caRoot, "rootCA.pem"))
-	caKeyBytes, keyReadErr := os.ReadFile(filepath.Join(caRoot, "rootCA-key.pem"))
-	if certReadErr != nil || keyReadErr != nil {
-		output.UserOut.Println("  ⚠ Cannot read CA files for trust verification — skipping")
-		output.UserOut.Println()
-		return true
-	}
-
-	caCert, certParseErr := parseCertFromPEM(caCertBytes)
-	caKey, keyParseErr := parsePKCS8Key(caKeyBytes)
-	if certParseErr != nil || keyParseErr != nil {
-		output.UserOut.Println("  ⚠ Cannot parse CA files for trust verification — skipping")
-		output.UserOut.Println()
-		return true
-	}
-
-	trusted, checkErr := verifyCATrustedByOS(caCert, caKey)
+	trusted, checkErr := caTrustedByOS(caRoot)
 	if checkErr != nil {
 		output.UserOut.Printf("  ⚠ OS trust check could not run: %v\n", checkErr)
 		output.UserOut.Println()
-		return false
+		return true
 	}
 
 	if trusted {
@@ -857,59 +861,52 @@ func checkCertificateFiles(caRoot string, app *ddevapp.DdevApp) bool {
 // checkCertFile validates a single certificate file against the CA pool.
 // Returns true if issues found.
 func checkCertFile(certPath string, caPool *x509.CertPool, expectedHostnames []string, label string) bool {
-	hasIssues := false
-
-	certPEM, err := os.ReadFile(certPath)
-	if err != nil {
-		tlsFail("%s not found: %s", label, certPath)
+	cert, problems := certFileProblems(certPath, caPool, expectedHostnames)
+	if cert == nil {
+		tlsFail("%s: %s", label, problems[0])
 		return true
 	}
-
-	block, _ := pem.Decode(certPEM)
-	if block == nil {
-		tlsFail("%s: failed to decode PEM", label)
-		return true
+	if len(problems) == 0 {
+		output.UserOut.Printf("  ✓ %s exists and is valid (expires %s)\n", label, cert.NotAfter.Format("2006-01-02"))
+		output.UserOut.Printf("  ✓ %s verified against current CAROOT\n", label)
+		for _, hostname := range expectedHostnames {
+			output.UserOut.Printf("  ✓ %s covers hostname %s\n", label, hostname)
+		}
+		return false
+	}
+	for _, problem := range problems {
+		tlsFail("%s: %s", label, problem)
 	}
+	return true
+}
 
-	cert, err := x509.ParseCertificate(block.Bytes)
+// certFileProblems validates a certificate file against the CA pool and the
+// hostnames it must cover. It returns the parsed certificate, which is nil
+// if the file can't be read, along with the problems found.
+func certFileProblems(certPath string, caPool *x509.CertPool, expectedHostnames []string) (*x509.Certificate, []string) {
+	certPEM, err := os.ReadFile(certPath)
 	if err != nil {
-		tlsFail("%s: failed to parse certificate: %v", label, err)
-		return true
+		return nil, []string{fmt.Sprintf("not found: %s", certPath)}
+	}
+	cert, err := parseCertFromPEM(certPEM)
+	if err != nil {
+		return nil, []string{fmt.Sprintf("failed to parse certificate: %v", err)}
 	}
 
-	// Check expiration
+	var problems []string
 	now := time.Now()
 	if now.After(cert.NotAfter) {
-		tlsFail("%s: EXPIRED (expired %s)", label, cert.NotAfter.Format("2006-01-02"))
-		hasIssues = true
-	} else {
-		output.UserOut.Printf("  ✓ %s exists and is valid (expires %s)\n", label, cert.NotAfter.Format("2006-01-02"))
+		problems = append(problems, fmt.Sprintf("EXPIRED (expired %s)", cert.NotAfter.Format("2006-01-02")))
 	}
-
-	// Verify against CA
-	_, err = cert.Verify(x509.VerifyOptions{
-		Roots:       caPool,
-		CurrentTime: now,
-	})
-	if err != nil {
-		tlsFail("%s: NOT verified against current CAROOT: %v", label, err)
-		output.UserOut.Println("    → CA may have been rotated; regenerate certificates")
-		hasIssues = true
-	} else {
-		output.UserOut.Printf("  ✓ %s verified against current CAROOT\n", label)
+	if _, err = cert.Verify(x509.VerifyOptions{Roots: caPool, CurrentTime: now}); err != nil {
+		problems = append(problems, fmt.Sprintf("NOT verified against current CAROOT, the CA may have been rotated: %v", err))
 	}
-
-	// Check hostname coverage
 	for _, hostname := range expectedHostnames {
 		if err := cert.VerifyHostname(hostname); err != nil {
-			tlsFail("%s does not cover hostname %s", label, hostname)
-			hasIssues = true
-		} else {
-			output.UserOut.Printf("  ✓ %s covers hostname %s\n", label, hostname)
+			problems = append(problems, fmt.Sprintf("does not cover hostname %s", hostname))
 		}
 	}
-
-	return hasIssues
+	return cert, problems
 }
 
 // checkLiveConnectivity checks HTTPS connectivity to a running project.
@@ -10,6 +10,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/ddev/ddev/pkg/dbgpproxy"
 	"github.com/ddev/ddev/pkg/ddevapp"
 	"github.com/ddev/ddev/pkg/dockerutil"
 	"github.com/ddev/ddev/pkg/environment"
@@ -1009,6 +1010,19 @@ func checkVSCodeLaunchJSON(path string) bool {
 	return hasPort9003 && hasHostname && hasPathMapping
 }
 
+// dbgpTestInitXML is the init packet sent by the protocol test, with the
+// IDE key as argument
+const dbgpTestInitXML = `<?xml version="1.0" encoding="UTF-8"?>
+<init xmlns="urn:debugger_protocol_v1"
+      xmlns:xdebug="https://xdebug.org/dbgp/xdebug"
+      fileuri="file:///var/www/html/index.php"
+      language="PHP"
+      protocol_version="1.0"
+      appid="ddev-test"
+      idekey="%s">
+  <engine version="3.3.0"><![CDATA[Xdebug]]></engine>
+</init>`
+
 // testDBGpProtocol tests the DBGp protocol by connecting to the IDE from inside the web container
 func testDBGpProtocol(app *ddevapp.DdevApp, ideLocation string, envType string) bool {
 	targetHost := "host.docker.internal"
@@ -1017,6 +1031,32 @@ func testDBGpProtocol(app *ddevapp.DdevApp, ideLocation string, envType string)
 		return true
 	}
 
+	// With the DBGp proxy, the IDE registered for the project's IDE key
+	// receives the connection instead of whatever listens on port 9003
+	ideKey := "PHPSTORM"
+	if globalconfig.DdevGlobalConfig.XdebugProxy {
+		ides, err := dbgpproxy.ListLocal()
+		if err != nil {
+			output.UserOut.Printf("✗ xdebug_proxy is enabled but the Xdebug proxy isn't running: %v\n", err)
+			output.UserOut.Println("  Try: ddev utility xdebug-proxy start")
+			return false
+		}
+		ideKey = app.GetName()
+		registered := false
+		for _, ide := range ides {
+			if ide.Key == ideKey {
+				registered = true
+			}
+			output.UserOut.Printf("  Registered with the Xdebug proxy: IDE key '%s' at %s\n", ide.Key, ide)
+		}
+		if !registered {
+			output.UserOut.Printf("✗ No IDE is registered with the Xdebug proxy for IDE key '%s'\n", ideKey)
+			output.UserOut.Printf("  Configure your IDE to register with the DBGp proxy at 127.0.0.1:%d with IDE key '%s'\n", dbgpproxy.DefaultIDEPort, ideKey)
+			return false
+		}
+		output.UserOut.Printf("✓ An IDE is registered with the Xdebug proxy for IDE key '%s'\n", ideKey)
+	}
+
 	connected, errMsg := testContainerToHostConnectivity(app, targetHost, 9003)
 	if !connected {
 		output.UserOut.Printf("✗ Cannot connect to port 9003: %s\n", errMsg)
@@ -1034,7 +1074,7 @@ if (!$sock) {
     exit(1);
 }
 
-$initXML = base64_decode('PD94bWwgdmVyc2lvbj0iMS4wIiBlbmNvZGluZz0iVVRGLTgiPz4KPGluaXQgeG1sbnM9InVybjpkZWJ1Z2dlcl9wcm90b2NvbF92MSIKICAgICAgeG1sbnM6eGRlYnVnPSJodHRwczovL3hkZWJ1Zy5vcmcvZGJncC94ZGVidWciCiAgICAgIGZpbGV1cmk9ImZpbGU6Ly8vdmFyL3d3dy9odG1sL2luZGV4LnBocCIKICAgICAgbGFuZ3VhZ2U9IlBIUCIKICAgICAgcHJvdG9jb2xfdmVyc2lvbj0iMS4wIgogICAgICBhcHBpZD0iZGRldi10ZXN0IgogICAgICBpZGVrZXk9IlBIUFNUT1JNIj4KICA8ZW5naW5lIHZlcnNpb249IjMuMy4wIj48IVtDREFUQVtYZGVidWddXT48L2VuZ2luZT4KPC9pbml0Pg==');
+$initXML = base64_decode('INIT_PLACEHOLDER');
 
 $packet = strlen($initXML) . "\0" . $initXML . "\0";
 fwrite($sock, $packet);
@@ -1058,6 +1098,7 @@ if ($response) {
 fclose($sock);
 ?>`
 	phpScript = strings.Replace(phpScript, "HOST_PLACEHOLDER", targetHost, 1)
+	phpScript = strings.Replace(phpScript, "INIT_PLACEHOLDER", base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf(dbgpTestInitXML, ideKey))), 1)
 
 	// Use base64 to avoid all shell interpretation issues
 	phpScriptB64 := base64.StdEncoding.EncodeToString([]byte(phpScript))
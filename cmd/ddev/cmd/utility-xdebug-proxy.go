@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/ddev/ddev/pkg/dbgpproxy"
+	"github.com/ddev/ddev/pkg/globalconfig"
+	"github.com/ddev/ddev/pkg/output"
+	"github.com/ddev/ddev/pkg/styles"
+	"github.com/ddev/ddev/pkg/util"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+)
+
+// DebugXdebugProxyCmd implements the ddev utility xdebug-proxy command
+var DebugXdebugProxyCmd = &cobra.Command{
+	Use:   "xdebug-proxy [command]",
+	Short: "Manage the DBGp proxy that routes Xdebug connections to IDEs",
+	Long: fmt.Sprintf(`Manage the DBGp proxy that routes Xdebug connections to IDEs by IDE key.
+It runs on the host when xdebug_proxy is enabled with 'ddev config global --xdebug-proxy'.
+IDEs register on port %d with the project name as IDE key, and Xdebug connects to port %d.`, dbgpproxy.DefaultIDEPort, dbgpproxy.DefaultXdebugPort),
+	Run: func(cmd *cobra.Command, _ []string) {
+		err := cmd.Usage()
+		util.CheckErr(err)
+	},
+}
+
+// DebugXdebugProxyStatusCmd implements the ddev utility xdebug-proxy status command
+var DebugXdebugProxyStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the IDEs registered with the Xdebug proxy",
+	Example: `ddev utility xdebug-proxy status
+ddev utility xdebug-proxy status -j`,
+	Args: cobra.NoArgs,
+	Run: func(_ *cobra.Command, _ []string) {
+		ides, err := dbgpproxy.ListLocal()
+		if err != nil {
+			util.Failed("The Xdebug proxy isn't running on port %d: %v", dbgpproxy.DefaultXdebugPort, err)
+		}
+		if len(ides) == 0 {
+			output.UserOut.WithField("raw", ides).Print("The Xdebug proxy is running, no IDE is registered.")
+			return
+		}
+
+		t := table.NewWriter()
+		styles.SetGlobalTableStyle(t, false)
+		t.AppendHeader(table.Row{"IDE key", "IDE address", "Multiple sessions"})
+		for _, ide := range ides {
+			t.AppendRow(table.Row{ide.Key, ide.String(), ide.Multiple})
+		}
+		output.UserOut.WithField("raw", ides).Print(t.Render())
+	},
+}
+
+// DebugXdebugProxyStartCmd implements the ddev utility xdebug-proxy start command
+var DebugXdebugProxyStartCmd = &cobra.Command{
+	Use:   "start",
+	Short: "Start the Xdebug proxy in the background",
+	Args:  cobra.NoArgs,
+	Run: func(_ *cobra.Command, _ []string) {
+		if err := dbgpproxy.Start(); err != nil {
+			util.Failed("%v", err)
+		}
+		if !globalconfig.DdevGlobalConfig.XdebugProxy {
+			util.Warning("xdebug_proxy isn't enabled, so projects don't set their IDE key, use 'ddev config global --xdebug-proxy' and restart them")
+		}
+		util.Success("The Xdebug proxy is running, IDEs can register on port %d, log is in %s", dbgpproxy.DefaultIDEPort, dbgpproxy.LogFilePath())
+	},
+}
+
+// DebugXdebugProxyStopCmd implements the ddev utility xdebug-proxy stop command
+var DebugXdebugProxyStopCmd = &cobra.Command{
+	Use:   "stop",
+	Short: "Stop the Xdebug proxy",
+	Args:  cobra.NoArgs,
+	Run: func(_ *cobra.Command, _ []string) {
+		if err := dbgpproxy.Stop(); err != nil {
+			util.Failed("Failed to stop the Xdebug proxy: %v", err)
+		}
+		util.Success("The Xdebug proxy has been stopped.")
+	},
+}
+
+// DebugXdebugProxyServeCmd implements the ddev utility xdebug-proxy serve
+// command, which is the background process started by the start command
+var DebugXdebugProxyServeCmd = &cobra.Command{
+	Use:    "serve",
+	Short:  "Run the Xdebug proxy in the foreground",
+	Hidden: true,
+	Args:   cobra.NoArgs,
+	Run: func(_ *cobra.Command, _ []string) {
+		ideListener, xdebugListener, err := dbgpproxy.Listen()
+		if err != nil {
+			util.Failed("%v", err)
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		proxy := dbgpproxy.New()
+		proxy.Logf = func(format string, a ...any) {
+			fmt.Printf("%s %s\n", time.Now().Format(time.RFC3339), fmt.Sprintf(format, a...))
+		}
+		proxy.Logf("Listening for IDEs on %s and for Xdebug on %s", ideListener.Addr(), xdebugListener.Addr())
+		if err = proxy.Serve(ctx, ideListener, xdebugListener); err != nil {
+			util.Failed("The Xdebug proxy failed: %v", err)
+		}
+		proxy.Logf("Stopped")
+	},
+}
+
+func init() {
+	DebugXdebugProxyCmd.AddCommand(DebugXdebugProxyStatusCmd)
+	DebugXdebugProxyCmd.AddCommand(DebugXdebugProxyStartCmd)
+	DebugXdebugProxyCmd.AddCommand(DebugXdebugProxyStopCmd)
+	DebugXdebugProxyCmd.AddCommand(DebugXdebugProxyServeCmd)
+	DebugCmd.AddCommand(DebugXdebugProxyCmd)
+}
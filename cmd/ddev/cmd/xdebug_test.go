@@ -2,6 +2,8 @@ package cmd
 
 import (
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/ddev/ddev/pkg/exec"
@@ -88,6 +90,38 @@ func TestCmdXdebug(t *testing.T) {
 		require.NoError(t, err)
 		require.Contains(t, string(out), "xdebug disabled")
 
+		// Profile requests, then collect the profiles
+		out, err = exec.RunHostCommand(DdevBin, "xdebug", "--mode", "profile")
+		require.NoError(t, err)
+		require.Contains(t, string(out), "Enabled xdebug (mode: profile, start_with_request: yes)")
+
+		out, err = exec.RunHostCommand(DdevBin, "xdebug", "status")
+		require.NoError(t, err)
+		require.Contains(t, string(out), "xdebug enabled (mode: profile)")
+
+		_, err = exec.RunHostCommand(DdevBin, "exec", "php", "-r", "echo 1;")
+		require.NoError(t, err)
+
+		out, err = exec.RunHostCommand(DdevBin, "xdebug", "profile", "collect")
+		require.NoError(t, err, "out=%s", out)
+		require.Contains(t, string(out), "into .ddev/xdebug-profiles")
+		profiles, err := filepath.Glob(filepath.Join(v.Dir, ".ddev/xdebug-profiles/cachegrind.out.*"))
+		require.NoError(t, err)
+		require.NotEmpty(t, profiles)
+		_ = os.RemoveAll(filepath.Join(v.Dir, ".ddev/xdebug-profiles"))
+
+		_, err = exec.RunHostCommand(DdevBin, "xdebug", "off")
+		require.NoError(t, err)
+
+		// Xdebug is loaded only for a command run with --xdebug
+		out, err = exec.RunHostCommand(DdevBin, "exec", "--xdebug=coverage", "php", "-r", `echo ini_get("xdebug.mode");`)
+		require.NoError(t, err)
+		require.Equal(t, "coverage", strings.TrimSpace(out))
+
+		out, err = exec.RunHostCommand(DdevBin, "exec", "php", "-m")
+		require.NoError(t, err)
+		require.NotContains(t, out, "xdebug")
+
 		_, err = exec.RunHostCommand(DdevBin, "stop")
 		require.NoError(t, err, "Failed ddev stop with php=%v: %v", phpVersion, err)
 	}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"strings"
@@ -86,6 +87,23 @@ func TestCmdXHGui(t *testing.T) {
 		testcommon.WithBackoff(500*time.Millisecond),
 	)
 
+	// The run is also reported on the command line, and compares with itself
+	out, err = exec.RunHostCommand(DdevBin, "xhprof", "report", "--format", "json")
+	require.NoError(t, err, "out=%s", out)
+	var reports []struct {
+		ID           string `json:"id"`
+		TopInclusive []struct {
+			Name string `json:"name"`
+		} `json:"top_inclusive"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(out), &reports), "out=%s", out)
+	require.Len(t, reports, 1)
+	require.NotEmpty(t, reports[0].TopInclusive)
+	require.Equal(t, "main()", reports[0].TopInclusive[0].Name)
+	out, err = exec.RunHostCommand(DdevBin, "xhprof", "diff", reports[0].ID, reports[0].ID)
+	require.NoError(t, err, "out=%s", out)
+	require.Contains(t, out, "Run "+reports[0].ID)
+
 	_, err = exec.RunHostCommand(DdevBin, "xhgui", "off")
 	require.NoError(t, err)
 	out, err = exec.RunHostCommand(DdevBin, "xhgui", "status")
@@ -0,0 +1,285 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ddev/ddev/pkg/config/types"
+	"github.com/ddev/ddev/pkg/ddevapp"
+	"github.com/ddev/ddev/pkg/heredoc"
+	"github.com/ddev/ddev/pkg/output"
+	"github.com/ddev/ddev/pkg/styles"
+	"github.com/ddev/ddev/pkg/util"
+	"github.com/ddev/ddev/pkg/xhprof"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+)
+
+const (
+	xhprofFormatText = "text"
+	xhprofFormatJSON = "json"
+	// xhprofTopFunctions is the number of functions shown per table
+	xhprofTopFunctions = 20
+)
+
+// DdevXHProfCmd represents the xhprof command
+var DdevXHProfCmd = &cobra.Command{
+	Use:   "xhprof [on|off|enable|disable|true|false|toggle|status]",
+	Short: "Enable, disable or check the status of XHProf, and report on XHGui runs",
+	Long: heredoc.DocI2S(`
+			Enable, disable or check the status of XHProf profiling. With no argument, XHProf is enabled.
+			With xhprof_mode 'xhgui', 'ddev xhprof report' and 'ddev xhprof diff' show the runs collected in the xhgui database.`),
+	Example: heredoc.DocI2S(`
+		ddev xhprof
+		ddev xhprof on
+		ddev xhprof off
+		ddev xhprof toggle
+		ddev xhprof status
+		ddev xhprof report
+		ddev xhprof diff <run1> <run2>
+	`),
+	ValidArgs: []string{"on", "off", "enable", "disable", "true", "false", "toggle", "status"},
+	Args:      cobra.MatchAll(cobra.MaximumNArgs(1), cobra.OnlyValidArgs),
+	Run: func(_ *cobra.Command, args []string) {
+		app, err := ddevapp.GetActiveApp("")
+		if err != nil {
+			util.Failed("Unable to get project: %v", err)
+		}
+		if err = app.StartAppIfNotRunning(); err != nil {
+			util.Failed("Failed to start %s: %v", app.Name, err)
+		}
+
+		action := "on"
+		if len(args) == 1 {
+			action = args[0]
+		}
+		status, err := ddevapp.XHProfStatus(app)
+		if err != nil {
+			util.Failed("Unable to get the XHProf status: %v", err)
+		}
+
+		switch action {
+		case "toggle":
+			if status {
+				xhprofRun(app, "disable_xhprof")
+			} else {
+				xhprofRun(app, "enable_xhprof")
+			}
+		case "on", "enable", "true":
+			xhprofRun(app, "enable_xhprof")
+		case "off", "disable", "false":
+			xhprofRun(app, "disable_xhprof")
+		case "status":
+			if status {
+				output.UserOut.WithField("raw", status).Print("xhprof is enabled")
+			} else {
+				output.UserOut.WithField("raw", status).Print("xhprof is disabled")
+			}
+		}
+	},
+}
+
+// xhprofRun runs enable_xhprof or disable_xhprof in the web container
+// and shows what it says
+func xhprofRun(app *ddevapp.DdevApp, script string) {
+	out, stderr, err := app.Exec(&ddevapp.ExecOpts{Cmd: script})
+	if err != nil {
+		util.Failed("Failed to run %s: %v %s", script, err, stderr)
+	}
+	output.UserOut.Print(strings.TrimSpace(out))
+}
+
+// DdevXHProfReportCmd implements the xhprof report command
+var DdevXHProfReportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Show the most expensive functions of recent XHGui runs",
+	Long: heredoc.DocI2S(`
+			Show the top functions by inclusive and exclusive wall time of the most recent runs in the xhgui database.
+			Times are in milliseconds. Needs xhprof_mode 'xhgui' and runs collected with 'ddev xhgui on'.`),
+	Example: heredoc.DocI2S(`
+		ddev xhprof report
+		ddev xhprof report --url /checkout --last 3
+		ddev xhprof report --format json
+	`),
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, _ []string) {
+		urlPattern, _ := cmd.Flags().GetString("url")
+		last, _ := cmd.Flags().GetInt("last")
+		format := xhprofFormat(cmd)
+		if last < 1 {
+			util.Failed("--last must be at least 1")
+		}
+
+		app := xhprofXHGuiApp()
+		runs, err := ddevapp.GetXHGuiRuns(app, urlPattern, last)
+		if err != nil {
+			util.Failed("%v", err)
+		}
+		if len(runs) == 0 {
+			util.Failed("No XHGui runs found, use 'ddev xhgui on' and make some requests first")
+		}
+
+		type runReport struct {
+			ddevapp.XHGuiRun
+			Inclusive []xhprof.Function `json:"top_inclusive"`
+			Exclusive []xhprof.Function `json:"top_exclusive"`
+		}
+		var reports []runReport
+		for _, run := range runs {
+			functions := run.Profile.Functions()
+			reports = append(reports, runReport{
+				XHGuiRun:  run,
+				Inclusive: xhprof.Top(functions, xhprofTopFunctions, func(f xhprof.Function) int64 { return f.WallTime }),
+				Exclusive: xhprof.Top(functions, xhprofTopFunctions, func(f xhprof.Function) int64 { return f.ExclusiveWallTime }),
+			})
+		}
+		if format == xhprofFormatJSON {
+			xhprofWriteJSON(reports)
+			return
+		}
+
+		for i, report := range reports {
+			if i > 0 {
+				output.UserOut.Print("")
+			}
+			output.UserOut.Printf("Run %s %s at %s: wall time %s, CPU %s, memory %s", report.ID, report.URL, report.RequestTime.Format("2006-01-02 15:04:05"), xhprofMilliseconds(report.WallTime), xhprofMilliseconds(report.CPU), util.FormatBytes(report.Memory))
+			output.UserOut.Print(xhprofFunctionTable("Inclusive", report.Inclusive, func(f xhprof.Function) (int64, int64, int64) { return f.WallTime, f.CPU, f.Memory }))
+			output.UserOut.Print(xhprofFunctionTable("Exclusive", report.Exclusive, func(f xhprof.Function) (int64, int64, int64) {
+				return f.ExclusiveWallTime, f.ExclusiveCPU, f.ExclusiveMemory
+			}))
+		}
+	},
+}
+
+// DdevXHProfDiffCmd implements the xhprof diff command
+var DdevXHProfDiffCmd = &cobra.Command{
+	Use:   "diff <run1> <run2>",
+	Short: "Compare two XHGui runs function by function",
+	Long: heredoc.DocI2S(`
+			Show the wall time, CPU and memory deltas per function between two runs in the xhgui database,
+			largest wall time change first. Times are in milliseconds. Run IDs are shown by 'ddev xhprof report'.`),
+	Example: heredoc.DocI2S(`
+		ddev xhprof diff 6650a1b2c3d4e5f601234567 6650a1f9c3d4e5f601234568
+		ddev xhprof diff <run1> <run2> --format json
+	`),
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		format := xhprofFormat(cmd)
+		app := xhprofXHGuiApp()
+		before, err := ddevapp.GetXHGuiRun(app, args[0])
+		if err != nil {
+			util.Failed("%v", err)
+		}
+		after, err := ddevapp.GetXHGuiRun(app, args[1])
+		if err != nil {
+			util.Failed("%v", err)
+		}
+
+		deltas := xhprof.Diff(before.Profile, after.Profile)
+		if format == xhprofFormatJSON {
+			xhprofWriteJSON(map[string]any{"before": before, "after": after, "functions": deltas})
+			return
+		}
+
+		output.UserOut.Printf("Run %s %s: wall time %s, CPU %s, memory %s", before.ID, before.URL, xhprofMilliseconds(before.WallTime), xhprofMilliseconds(before.CPU), util.FormatBytes(before.Memory))
+		output.UserOut.Printf("Run %s %s: wall time %s, CPU %s, memory %s", after.ID, after.URL, xhprofMilliseconds(after.WallTime), xhprofMilliseconds(after.CPU), util.FormatBytes(after.Memory))
+
+		t := table.NewWriter()
+		styles.SetGlobalTableStyle(t, false)
+		t.AppendHeader(table.Row{"Function", "Wall time", "Δ Wall time", "CPU", "Δ CPU", "Memory", "Δ Memory"})
+		t.SetColumnConfigs([]table.ColumnConfig{{Name: "Function", WidthMax: 60}})
+		for _, d := range deltas {
+			if d.WallTime == 0 && d.CPU == 0 && d.Memory == 0 {
+				continue
+			}
+			name := d.Name
+			if d.OnlyIn != "" {
+				name += fmt.Sprintf(" (only %s)", d.OnlyIn)
+			}
+			t.AppendRow(table.Row{
+				name,
+				xhprofMilliseconds(d.After.WallTime), xhprofSigned(d.WallTime, xhprofMilliseconds),
+				xhprofMilliseconds(d.After.CPU), xhprofSigned(d.CPU, xhprofMilliseconds),
+				util.FormatBytes(d.After.Memory), xhprofSigned(d.Memory, util.FormatBytes),
+			})
+		}
+		output.UserOut.Print(t.Render())
+	},
+}
+
+// xhprofXHGuiApp returns the running project, which must use xhprof_mode xhgui
+func xhprofXHGuiApp() *ddevapp.DdevApp {
+	app, err := ddevapp.GetActiveApp("")
+	if err != nil {
+		util.Failed("Unable to get project: %v", err)
+	}
+	if app.GetXHProfMode() != types.XHProfModeXHGui {
+		util.Failed("XHProf Mode is set to '%s', runs are only stored with 'xhgui'.\nUse the command below to enable it:\nddev config global --xhprof-mode=xhgui && ddev restart", app.GetXHProfMode())
+	}
+	if err = app.StartAppIfNotRunning(); err != nil {
+		util.Failed("Failed to start %s: %v", app.Name, err)
+	}
+	return app
+}
+
+// xhprofFormat returns the validated --format flag
+func xhprofFormat(cmd *cobra.Command) string {
+	format, _ := cmd.Flags().GetString("format")
+	if format != xhprofFormatText && format != xhprofFormatJSON {
+		util.Failed("Unknown format '%s', valid formats are %s, %s", format, xhprofFormatText, xhprofFormatJSON)
+	}
+	return format
+}
+
+// xhprofWriteJSON writes a report as indented JSON to stdout
+func xhprofWriteJSON(report any) {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		util.Failed("Failed to write JSON: %v", err)
+	}
+}
+
+// xhprofFunctionTable renders the functions with the metrics returned by get
+func xhprofFunctionTable(kind string, functions []xhprof.Function, get func(xhprof.Function) (int64, int64, int64)) string {
+	t := table.NewWriter()
+	styles.SetGlobalTableStyle(t, false)
+	t.AppendHeader(table.Row{"Function", "Calls", kind + " wall time", kind + " CPU", kind + " memory"})
+	t.SetColumnConfigs([]table.ColumnConfig{{Name: "Function", WidthMax: 60}})
+	for _, f := range functions {
+		wallTime, cpu, memory := get(f)
+		t.AppendRow(table.Row{f.Name, f.Calls, xhprofMilliseconds(wallTime), xhprofMilliseconds(cpu), util.FormatBytes(memory)})
+	}
+	return t.Render()
+}
+
+// xhprofMilliseconds formats microseconds as milliseconds
+func xhprofMilliseconds(microseconds int64) string {
+	return fmt.Sprintf("%.1fms", float64(microseconds)/1000)
+}
+
+// xhprofSigned formats a delta with its sign
+func xhprofSigned(delta int64, format func(int64) string) string {
+	switch {
+	case delta > 0:
+		return "+" + format(delta)
+	case delta < 0:
+		return "-" + format(-delta)
+	}
+	return format(0)
+}
+
+func init() {
+	for _, c := range []*cobra.Command{DdevXHProfReportCmd, DdevXHProfDiffCmd} {
+		c.Flags().String("format", xhprofFormatText, fmt.Sprintf("Output format, %s or %s", xhprofFormatText, xhprofFormatJSON))
+		_ = c.RegisterFlagCompletionFunc("format", configCompletionFunc([]string{xhprofFormatText, xhprofFormatJSON}))
+	}
+	DdevXHProfReportCmd.Flags().String("url", "", "Only report runs with a URL containing this pattern")
+	DdevXHProfReportCmd.Flags().Int("last", 1, "Number of most recent runs to report")
+
+	DdevXHProfCmd.AddCommand(DdevXHProfReportCmd)
+	DdevXHProfCmd.AddCommand(DdevXHProfDiffCmd)
+	RootCmd.AddCommand(DdevXHProfCmd)
+}
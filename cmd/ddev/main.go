@@ -5,6 +5,7 @@ import (
 
 	"github.com/ddev/ddev/cmd/ddev/cmd"
 	"github.com/ddev/ddev/pkg/amplitude"
+	"github.com/ddev/ddev/pkg/instrumentation"
 	"github.com/ddev/ddev/pkg/util"
 )
 
@@ -19,6 +20,10 @@ func main() {
 		amplitude.Flush()
 	}()
 
+	// Local instrumentation sinks configured in the global config
+	instrumentation.Init()
+	defer instrumentation.Exit(nil)
+
 	// Prevent running as root
 	// We really don't want ~/.ddev to have root ownership, breaks things.
 	if os.Geteuid() == 0 {
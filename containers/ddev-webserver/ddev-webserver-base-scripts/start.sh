@@ -45,6 +45,7 @@ if [ -d /mnt/ddev_config/php ] ; then
   if [ -n "$(ls -A /mnt/ddev_config/php/*.ini 2>/dev/null)" ]; then
     cp /mnt/ddev_config/php/*.ini /etc/php/${DDEV_PHP_VERSION}/cli/conf.d/
     cp /mnt/ddev_config/php/*.ini /etc/php/${DDEV_PHP_VERSION}/fpm/conf.d/
+    cp /mnt/ddev_config/php/*.ini /etc/frankenphp/php.d/
   fi
 fi
 
@@ -56,6 +57,14 @@ if [ -d /mnt/ddev_config/apache ]; then
   rm -rf /etc/apache2/sites-enabled
   cp -r /mnt/ddev_config/apache /etc/apache2/sites-enabled
 fi
+if [ -d /mnt/ddev_config/caddy ]; then
+  rm -rf /etc/caddy/sites-enabled
+  cp -r /mnt/ddev_config/caddy /etc/caddy/sites-enabled
+fi
+if [ -d /mnt/ddev_config/frankenphp ]; then
+  rm -rf /etc/frankenphp/sites-enabled
+  cp -r /mnt/ddev_config/frankenphp /etc/frankenphp/sites-enabled
+fi
 
 if [ "$DDEV_PROJECT_TYPE" = "backdrop" ] ; then
   # Start can be executed when the container is already running.
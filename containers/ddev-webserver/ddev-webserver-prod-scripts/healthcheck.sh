@@ -22,7 +22,7 @@ if [ -f /tmp/healthy ]; then
 fi
 
 # Shutdown the supervisor if one of the critical processes is in the FATAL state
-for service in php-fpm nginx apache2; do
+for service in php-fpm nginx apache2 caddy frankenphp; do
   if supervisorctl status "${service}" 2>/dev/null | grep -q FATAL; then
     printf "%s:FATAL " "${service}"
     supervisorctl shutdown
@@ -60,6 +60,16 @@ if [ "${DDEV_WEBSERVER_TYPE#*-}" = "fpm" ]; then
   fi
 fi
 
+# FrankenPHP has no php-fpm, so its health endpoint stands in for phpstatus
+if [ "${DDEV_WEBSERVER_TYPE}" = "frankenphp" ]; then
+  if curl --fail -s 127.0.0.1/healthcheck >/dev/null; then
+    phpstatus="true"
+    printf "frankenphp:OK "
+  else
+    printf "frankenphp:FAILED "
+  fi
+fi
+
 if [ "${phpstatus}" = "true" ] && [ "${htmlaccess}" = "true" ]; then
     touch /tmp/healthy
     exit 0
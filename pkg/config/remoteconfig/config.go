@@ -16,6 +16,9 @@ type Config struct {
 
 	UpdateInterval int
 	TickerInterval int
+
+	// Sources are extra files or URLs with messages in the remote config format
+	Sources []string
 }
 
 // getLocalSourceFileName returns the filename of the local storage.
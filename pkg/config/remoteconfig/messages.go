@@ -26,6 +26,9 @@ type conditionDefinition struct {
 	name          string
 	description   string
 	conditionFunc func() bool
+	// argConditionFunc is used instead of conditionFunc for conditions
+	// with an argument, like ProjectType:drupal11
+	argConditionFunc func(arg string) bool
 }
 
 var conditionDefinitions = map[string]conditionDefinition{}
@@ -46,11 +49,27 @@ func AddCondition(name, description string, conditionFunc func() bool) {
 	}
 }
 
+// AddArgCondition adds a condition that is used with an argument after a
+// colon, like ProjectType:drupal11
+func AddArgCondition(name, description string, conditionFunc func(arg string) bool) {
+	conditionDefinitions[strings.ToLower(name)] = conditionDefinition{
+		name:             name,
+		description:      description,
+		argConditionFunc: conditionFunc,
+	}
+}
+
+// ListConditions returns the description of each condition. Conditions
+// with an argument are listed like ProjectType:<arg>.
 func ListConditions() (conditions map[string]string) {
 	conditions = make(map[string]string)
 
 	for _, condition := range conditionDefinitions {
-		conditions[condition.name] = condition.description
+		name := condition.name
+		if condition.argConditionFunc != nil {
+			name += ":<arg>"
+		}
+		conditions[name] = condition.description
 	}
 
 	return
@@ -64,9 +83,10 @@ func (c *remoteConfig) ShowNotifications() {
 		return
 	}
 
+	all := c.allMessages()
 	for _, messages := range []messageTypes{
-		{messageType: types.Info, messages: c.remoteConfig.Messages.Notifications.Infos},
-		{messageType: types.Warning, messages: c.remoteConfig.Messages.Notifications.Warnings},
+		{messageType: types.Info, messages: all.Notifications.Infos},
+		{messageType: types.Warning, messages: all.Notifications.Warnings},
 	} {
 		t := table.NewWriter()
 
@@ -83,7 +103,7 @@ func (c *remoteConfig) ShowNotifications() {
 		}
 
 		for _, message := range messages.messages {
-			if !c.checkConditions(message.Conditions) || !c.checkVersions(message.Versions) {
+			if !MessageApplies(message) {
 				continue
 			}
 
@@ -138,7 +158,7 @@ func (c *remoteConfig) ShowTicker() {
 
 		message := &tickerData.Messages[messageOffset-1]
 
-		if c.checkConditions(message.Conditions) && c.checkVersions(message.Versions) {
+		if MessageApplies(*message) {
 			t := table.NewWriter()
 			applyTableStyle(ticker, t)
 
@@ -237,26 +257,44 @@ func (c *remoteConfig) showSponsorshipMessage() bool {
 		c.state.LastSponsorshipAt.Add(sponsorshipInterval).Before(time.Now())
 }
 
-func (c *remoteConfig) checkConditions(conditions []string) bool {
+// MessageApplies returns true if the conditions and versions of a message
+// match this DDEV and machine, so it may be shown
+func MessageApplies(message types.Message) bool {
+	return checkConditions(message.Conditions) && checkVersions(message.Versions)
+}
+
+func checkConditions(conditions []string) bool {
 	for _, rawCondition := range conditions {
-		condition, negated := strings.CutPrefix(strings.TrimSpace(rawCondition), "!")
-		condition = strings.ToLower(strings.TrimSpace(condition))
+		if result, _ := CheckCondition(rawCondition); !result {
+			return false
+		}
+	}
 
-		conditionDef, found := conditionDefinitions[condition]
+	return true
+}
 
-		if found {
-			conditionResult := conditionDef.conditionFunc()
+// CheckCondition evaluates a message condition like "WSL2", "!Colima" or
+// "ProjectType:drupal11". Unknown conditions are true, so messages using
+// conditions of newer DDEV versions are still shown; known is false for them.
+func CheckCondition(rawCondition string) (result bool, known bool) {
+	condition, negated := strings.CutPrefix(strings.TrimSpace(rawCondition), "!")
+	name, arg, hasArg := strings.Cut(strings.TrimSpace(condition), ":")
 
-			if (!negated && !conditionResult) || (negated && conditionResult) {
-				return false
-			}
-		}
+	conditionDef, found := conditionDefinitions[strings.ToLower(strings.TrimSpace(name))]
+	if !found || hasArg != (conditionDef.argConditionFunc != nil) {
+		return true, false
 	}
 
-	return true
+	if hasArg {
+		result = conditionDef.argConditionFunc(strings.TrimSpace(arg))
+	} else {
+		result = conditionDef.conditionFunc()
+	}
+
+	return result != negated, true
 }
 
-func (c *remoteConfig) checkVersions(versions string) bool {
+func checkVersions(versions string) bool {
 	versions = strings.TrimSpace(versions)
 	if versions != "" {
 		match, err := util.SemverValidate(versions, versionconstants.DdevVersion)
@@ -317,9 +355,10 @@ func (c *remoteConfig) ShowSponsorshipAppreciation() {
 	}
 }
 
-// getTicker returns ticker data from the messages structure
+// getTicker returns ticker data from the messages structure, including the
+// ticker messages of extra sources
 func (c *remoteConfig) getTicker() types.Ticker {
-	return c.remoteConfig.Messages.Ticker
+	return c.allMessages().Ticker
 }
 
 type preset int
@@ -32,6 +32,9 @@ func New(config *Config, stateManager statetypes.State, isInternetActive func()
 	cfg.urlDownloader = downloader.NewURLJSONCDownloader(config.URL)
 	cfg.updateFromRemote()
 
+	// Load extra message sources.
+	cfg.loadMessageSources(config.Local.Path, config.Sources)
+
 	return cfg
 }
 
@@ -55,6 +58,8 @@ type remoteConfig struct {
 	tickerInterval   int
 	isInternetActive func() bool
 
+	sources []messageSource
+
 	mu sync.Mutex
 }
 
@@ -2,6 +2,8 @@ package remoteconfig_test
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"strings"
@@ -276,3 +278,63 @@ func containsAny(s string, substrings []string) bool {
 	}
 	return false
 }
+
+// TestMessageSources tests that messages of extra files and URLs are loaded
+// and that their conditions are evaluated
+func TestMessageSources(t *testing.T) {
+	tmpDir := t.TempDir()
+	stateManager := yaml.NewState(filepath.Join(tmpDir, "state.yaml"))
+
+	fileSource := `{
+  // Messages of the team
+  "messages": {
+    "notifications": {
+      "infos": [
+        {"message": "Shown", "conditions": ["TestCondition:yes"]},
+        {"message": "Hidden", "conditions": ["!TestCondition:yes"]}
+      ]
+    }
+  }
+}`
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "team-messages.jsonc"), []byte(fileSource), 0644))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"messages": {"ticker": {"messages": [{"message": "From a URL", "versions": ">=0.0.0-0"}]}}}`))
+	}))
+	defer server.Close()
+
+	remoteconfig.AddArgCondition("TestCondition", "A condition for tests", func(arg string) bool {
+		return arg == "yes"
+	})
+
+	config := remoteconfig.Config{
+		Local: remoteconfig.Local{
+			Path: tmpDir,
+		},
+		Sources: []string{"team-messages.jsonc", server.URL, filepath.Join(tmpDir, "missing.jsonc")},
+	}
+	rc := remoteconfig.New(&config, stateManager, func() bool { return true })
+
+	sources := rc.GetMessageSources()
+	require.Len(t, sources, 3)
+	require.Equal(t, "team-messages.jsonc", sources[1].Location)
+	require.Len(t, sources[1].Messages.Notifications.Infos, 2)
+	require.True(t, remoteconfig.MessageApplies(sources[1].Messages.Notifications.Infos[0]))
+	require.False(t, remoteconfig.MessageApplies(sources[1].Messages.Notifications.Infos[1]))
+	require.Equal(t, server.URL, sources[2].Location)
+	require.Equal(t, "From a URL", sources[2].Messages.Ticker.Messages[0].Message)
+
+	// The URL source is cached
+	server.Close()
+	rc = remoteconfig.New(&config, stateManager, func() bool { return false })
+	require.Equal(t, "From a URL", rc.GetMessageSources()[2].Messages.Ticker.Messages[0].Message)
+
+	result, known := remoteconfig.CheckCondition("!TestCondition:no")
+	require.True(t, result)
+	require.True(t, known)
+	_, known = remoteconfig.CheckCondition("TestCondition")
+	require.False(t, known)
+	result, known = remoteconfig.CheckCondition("NotACondition:x")
+	require.True(t, result)
+	require.False(t, known)
+}
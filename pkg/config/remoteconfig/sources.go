@@ -0,0 +1,136 @@
+package remoteconfig
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ddev/ddev/pkg/config/remoteconfig/downloader"
+	"github.com/ddev/ddev/pkg/config/remoteconfig/storage"
+	"github.com/ddev/ddev/pkg/config/remoteconfig/types"
+	"github.com/ddev/ddev/pkg/util"
+	"muzzammil.xyz/jsonc"
+)
+
+// messageSource is an extra source of messages, like an organization's
+// internal notifications, in the same format as the remote config
+type messageSource struct {
+	location string
+	messages types.Messages
+}
+
+// isURLSource returns true if the source location is a URL rather than a file
+func isURLSource(location string) bool {
+	return strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://")
+}
+
+// loadMessageSources loads the extra message sources. Files are read on
+// every run, URLs are downloaded like the remote config and cached in the
+// local storage directory.
+func (c *remoteConfig) loadMessageSources(localPath string, locations []string) {
+	for _, location := range locations {
+		location = strings.TrimSpace(location)
+		if location == "" {
+			continue
+		}
+		var data types.RemoteConfigData
+		var err error
+		if isURLSource(location) {
+			data, err = c.loadURLMessageSource(localPath, location)
+		} else {
+			data, err = loadFileMessageSource(localPath, location)
+		}
+		if err != nil {
+			util.Warning("Unable to load messages from %s: %v", location, err)
+			continue
+		}
+		c.sources = append(c.sources, messageSource{location: location, messages: data.Messages})
+	}
+}
+
+// loadFileMessageSource reads messages from a JSONC file. Relative paths
+// are relative to the global DDEV directory.
+func loadFileMessageSource(localPath string, location string) (types.RemoteConfigData, error) {
+	var data types.RemoteConfigData
+	if strings.HasPrefix(location, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			location = filepath.Join(home, location[2:])
+		}
+	}
+	if !filepath.IsAbs(location) {
+		location = filepath.Join(localPath, location)
+	}
+	content, err := os.ReadFile(location)
+	if err != nil {
+		return data, err
+	}
+	err = jsonc.Unmarshal(content, &data)
+	return data, err
+}
+
+// loadURLMessageSource returns the messages at a URL, downloading them if
+// the local copy is older than the update interval
+func (c *remoteConfig) loadURLMessageSource(localPath string, url string) (types.RemoteConfigData, error) {
+	hash := sha256.Sum256([]byte(url))
+	fileName := filepath.Join(localPath, localFileName+"-"+hex.EncodeToString(hash[:])[:12])
+	fileStorage := storage.NewFileStorage(fileName)
+
+	data, err := fileStorage.Read()
+	if err != nil {
+		util.Debug("Error while loading messages of %s from local storage: %v", url, err)
+	}
+	stat, statErr := os.Stat(fileName)
+	if statErr == nil && stat.ModTime().Add(c.getUpdateInterval()).After(time.Now()) {
+		return data, nil
+	}
+	if !c.isInternetActive() {
+		util.Debug("No internet connection, using the local copy of %s", url)
+		return data, nil
+	}
+
+	util.Debug("Downloading messages from %s", url)
+	var downloaded types.RemoteConfigData
+	if err = downloader.NewURLJSONCDownloader(url).Download(context.Background(), &downloaded); err != nil {
+		if statErr != nil {
+			return data, err
+		}
+		util.Debug("Error while downloading messages from %s, using the local copy: %v", url, err)
+		return data, nil
+	}
+	if err = fileStorage.Write(downloaded); err != nil {
+		util.Debug("Error while writing messages of %s to local storage: %v", url, err)
+	}
+	return downloaded, nil
+}
+
+// GetMessageSources returns the messages of the remote config and of each
+// extra source
+func (c *remoteConfig) GetMessageSources() []types.MessageSource {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	sources := []types.MessageSource{{Location: c.urlDownloader.GetURL(), Messages: c.remoteConfig.Messages}}
+	for _, s := range c.sources {
+		sources = append(sources, types.MessageSource{Location: s.location, Messages: s.messages})
+	}
+	return sources
+}
+
+// allMessages returns the messages of the remote config followed by the
+// messages of the extra sources
+func (c *remoteConfig) allMessages() types.Messages {
+	all := c.remoteConfig.Messages
+	all.Notifications.Infos = append([]types.Message{}, all.Notifications.Infos...)
+	all.Notifications.Warnings = append([]types.Message{}, all.Notifications.Warnings...)
+	all.Ticker.Messages = append([]types.Message{}, all.Ticker.Messages...)
+	for _, s := range c.sources {
+		all.Notifications.Infos = append(all.Notifications.Infos, s.messages.Notifications.Infos...)
+		all.Notifications.Warnings = append(all.Notifications.Warnings, s.messages.Notifications.Warnings...)
+		all.Ticker.Messages = append(all.Ticker.Messages, s.messages.Ticker.Messages...)
+	}
+	return all
+}
@@ -5,6 +5,13 @@ type RemoteConfig interface {
 	ShowNotifications()
 	ShowTicker()
 	ShowSponsorshipAppreciation()
+	GetMessageSources() []MessageSource
+}
+
+// MessageSource is the remote config or an extra source of messages
+type MessageSource struct {
+	Location string   `json:"location"`
+	Messages Messages `json:"messages"`
 }
 
 // Remote config data structures (moved from internal package)
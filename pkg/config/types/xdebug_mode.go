@@ -0,0 +1,116 @@
+package types
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+type XdebugMode = string
+
+const (
+	XdebugModeEmpty    XdebugMode = ""
+	XdebugModeDebug    XdebugMode = "debug"
+	XdebugModeDevelop  XdebugMode = "develop"
+	XdebugModeCoverage XdebugMode = "coverage"
+	XdebugModeProfile  XdebugMode = "profile"
+	XdebugModeTrace    XdebugMode = "trace"
+)
+
+// XdebugModeDefault is the xdebug.mode used when xdebug_mode is empty
+const XdebugModeDefault = XdebugModeDebug + "," + XdebugModeDevelop
+
+// ValidXdebugModeOptions returns a slice of valid Xdebug modes, which
+// may be combined like "debug,develop".
+func ValidXdebugModeOptions() []XdebugMode {
+	return []XdebugMode{
+		XdebugModeDebug,
+		XdebugModeDevelop,
+		XdebugModeCoverage,
+		XdebugModeProfile,
+		XdebugModeTrace,
+	}
+}
+
+// IsValidXdebugMode checks to see if the given Xdebug mode, or each of a
+// comma-separated list of modes, is valid.
+func IsValidXdebugMode(xdebugMode string) bool {
+	if xdebugMode == XdebugModeEmpty {
+		return true
+	}
+
+	for mode := range strings.SplitSeq(xdebugMode, ",") {
+		if !slices.Contains(ValidXdebugModeOptions(), strings.TrimSpace(mode)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// CheckValidXdebugMode checks to see if the given Xdebug mode is valid
+// and returns an error in case the value is not valid.
+func CheckValidXdebugMode(xdebugMode string) error {
+	if !IsValidXdebugMode(xdebugMode) {
+		return fmt.Errorf(
+			"\"%s\" is not a valid xdebug_mode option. Valid options include \"%s\" or a comma-separated list of them",
+			xdebugMode,
+			strings.Join(ValidXdebugModeOptions(), "\", \""),
+		)
+	}
+
+	return nil
+}
+
+type XdebugStartWithRequest = string
+
+const (
+	XdebugStartWithRequestEmpty   XdebugStartWithRequest = ""
+	XdebugStartWithRequestYes     XdebugStartWithRequest = "yes"
+	XdebugStartWithRequestTrigger XdebugStartWithRequest = "trigger"
+)
+
+// ValidXdebugStartWithRequestOptions returns a slice of valid
+// xdebug_start_with_request options.
+func ValidXdebugStartWithRequestOptions() []XdebugStartWithRequest {
+	return []XdebugStartWithRequest{
+		XdebugStartWithRequestTrigger,
+		XdebugStartWithRequestYes,
+	}
+}
+
+// CheckValidXdebugStartWithRequest checks to see if the given
+// xdebug_start_with_request option is valid and returns an error in case
+// the value is not valid.
+func CheckValidXdebugStartWithRequest(startWithRequest string) error {
+	if startWithRequest != XdebugStartWithRequestEmpty && !slices.Contains(ValidXdebugStartWithRequestOptions(), startWithRequest) {
+		return fmt.Errorf(
+			"\"%s\" is not a valid xdebug_start_with_request option. Valid options include \"%s\"",
+			startWithRequest,
+			strings.Join(ValidXdebugStartWithRequestOptions(), "\", \""),
+		)
+	}
+
+	return nil
+}
+
+// Flag definitions
+const FlagXdebugModeName = "xdebug-mode"
+
+func FlagXdebugModeDescription() string {
+	return fmt.Sprintf(
+		"Xdebug mode when Xdebug is enabled, possible values are \"%s\" or a comma-separated list of them, defaults to \"%s\"",
+		strings.Join(ValidXdebugModeOptions(), "\", \""),
+		XdebugModeDefault,
+	)
+}
+
+const FlagXdebugStartWithRequestName = "xdebug-start-with-request"
+
+func FlagXdebugStartWithRequestDescription() string {
+	return fmt.Sprintf(
+		"Whether Xdebug starts with every request or only when triggered, possible values are \"%s\", defaults to \"%s\"",
+		strings.Join(ValidXdebugStartWithRequestOptions(), "\", \""),
+		XdebugStartWithRequestYes,
+	)
+}
@@ -0,0 +1,69 @@
+package dbgpproxy
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+
+	"github.com/ddev/ddev/pkg/hostdaemon"
+)
+
+// daemon runs the proxy in the background
+var daemon = &hostdaemon.Daemon{
+	Name:      "Xdebug proxy",
+	Args:      []string{"utility", "xdebug-proxy", "serve"},
+	PidFile:   ".dbgp-proxy.pid",
+	LogFile:   "dbgp-proxy.log",
+	IsRunning: IsRunning,
+	StartHint: fmt.Sprintf("ports %d and %d may be in use, for example by an IDE", DefaultIDEPort, DefaultXdebugPort),
+}
+
+// Listen listens on the IDE and Xdebug ports where the web containers can
+// reach them, but not on the network unless router_bind_all_interfaces is
+// set, since a registered IDE receives the debugging sessions of the
+// project and the Xdebug port lists the IDEs.
+func Listen() (ideListener net.Listener, xdebugListener net.Listener, err error) {
+	ideListener, err = hostdaemon.Listen(DefaultIDEPort)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to listen for IDEs on port %d: %v", DefaultIDEPort, err)
+	}
+	xdebugListener, err = hostdaemon.Listen(DefaultXdebugPort)
+	if err != nil {
+		_ = ideListener.Close()
+		return nil, nil, fmt.Errorf("unable to listen for Xdebug on port %d: %v", DefaultXdebugPort, err)
+	}
+	return ideListener, xdebugListener, nil
+}
+
+// localXdebugAddress is where the proxy answers proxylist on this machine
+func localXdebugAddress() string {
+	return net.JoinHostPort("127.0.0.1", strconv.Itoa(DefaultXdebugPort))
+}
+
+// LogFilePath returns the file the proxy logs registrations and connections to
+func LogFilePath() string {
+	return daemon.LogFilePath()
+}
+
+// ListLocal returns the IDEs registered with the proxy on this machine, or
+// an error if it isn't running
+func ListLocal() ([]IDE, error) {
+	return List(localXdebugAddress())
+}
+
+// IsRunning returns true if the proxy answers on this machine
+func IsRunning() bool {
+	_, err := ListLocal()
+	return err == nil
+}
+
+// Start starts the proxy as a background process of this ddev binary,
+// unless it's already running
+func Start() error {
+	return daemon.Start()
+}
+
+// Stop stops the background proxy started by Start, if any
+func Stop() error {
+	return daemon.Stop()
+}
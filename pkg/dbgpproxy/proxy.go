@@ -0,0 +1,316 @@
+// Package dbgpproxy implements a DBGp proxy, which routes each Xdebug
+// connection to the IDE registered with the IDE key of the connection, so
+// several IDEs, projects or developers can debug at the same time.
+// See https://xdebug.org/docs/dbgp#just-in-time-debugging-and-debugger-proxies
+package dbgpproxy
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultIDEPort is the port IDEs register on, as with Xdebug's dbgpProxy
+	DefaultIDEPort = 9001
+	// DefaultXdebugPort is the port Xdebug connects to, which is Xdebug's
+	// default client port, so the web container needs no other setting
+	DefaultXdebugPort = 9003
+	// maxPacketSize limits the init packet of Xdebug
+	maxPacketSize = 1 << 20
+	// timeout limits commands and the init packet, not debugging sessions
+	timeout = 5 * time.Second
+)
+
+// IDE is an IDE registered with the proxy
+type IDE struct {
+	Key      string `xml:"idekey,attr" json:"idekey"`
+	Address  string `xml:"address,attr" json:"address"`
+	Port     int    `xml:"port,attr" json:"port"`
+	Multiple bool   `xml:"multiple,attr" json:"multiple"`
+}
+
+// String returns the address the IDE listens on
+func (ide IDE) String() string {
+	return net.JoinHostPort(ide.Address, strconv.Itoa(ide.Port))
+}
+
+// Proxy routes Xdebug connections to registered IDEs
+type Proxy struct {
+	mu   sync.Mutex
+	ides map[string]IDE
+	// Logf, if set, receives a line for every registration and connection
+	Logf func(format string, a ...any)
+}
+
+// New returns a proxy without registered IDEs
+func New() *Proxy {
+	return &Proxy{ides: map[string]IDE{}}
+}
+
+// IDEs returns the registered IDEs sorted by IDE key
+func (p *Proxy) IDEs() []IDE {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	ides := make([]IDE, 0, len(p.ides))
+	for _, ide := range p.ides {
+		ides = append(ides, ide)
+	}
+	slices.SortFunc(ides, func(a, b IDE) int { return strings.Compare(a.Key, b.Key) })
+	return ides
+}
+
+// Serve accepts IDE commands on ideListener and Xdebug connections on
+// xdebugListener until the context is done
+func (p *Proxy) Serve(ctx context.Context, ideListener net.Listener, xdebugListener net.Listener) error {
+	go func() {
+		<-ctx.Done()
+		_ = ideListener.Close()
+		_ = xdebugListener.Close()
+	}()
+
+	errs := make(chan error, 2)
+	accept := func(l net.Listener, handle func(net.Conn)) {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				errs <- err
+				return
+			}
+			go handle(conn)
+		}
+	}
+	go accept(ideListener, p.handleIDE)
+	go accept(xdebugListener, p.handleXdebug)
+
+	err := <-errs
+	if ctx.Err() != nil {
+		return nil
+	}
+	return err
+}
+
+func (p *Proxy) logf(format string, a ...any) {
+	if p.Logf != nil {
+		p.Logf(format, a...)
+	}
+}
+
+// handleIDE runs a proxyinit, proxystop or proxylist command of an IDE
+func (p *Proxy) handleIDE(conn net.Conn) {
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(timeout))
+	command, err := readCommand(bufio.NewReader(conn))
+	if err != nil {
+		return
+	}
+	_, _ = io.WriteString(conn, p.runCommand(command, conn.RemoteAddr(), true))
+}
+
+// handleXdebug routes a connection of Xdebug to the IDE registered with
+// the IDE key of its init packet. Only proxylist is allowed as a command on
+// this port, so the web container can show the registered IDEs.
+func (p *Proxy) handleXdebug(conn net.Conn) {
+	_ = conn.SetDeadline(time.Now().Add(timeout))
+	reader := bufio.NewReader(conn)
+	first, err := reader.Peek(1)
+	if err != nil {
+		_ = conn.Close()
+		return
+	}
+	if first[0] < '0' || first[0] > '9' {
+		defer conn.Close()
+		command, err := readCommand(reader)
+		if err == nil {
+			_, _ = io.WriteString(conn, p.runCommand(command, conn.RemoteAddr(), false))
+		}
+		return
+	}
+
+	packet, key, err := readInitPacket(reader)
+	if err != nil {
+		p.logf("Invalid connection from %s: %v", conn.RemoteAddr(), err)
+		_ = conn.Close()
+		return
+	}
+	p.mu.Lock()
+	ide, ok := p.ides[key]
+	p.mu.Unlock()
+	if !ok {
+		p.logf("No IDE registered for idekey '%s', closing the connection from %s", key, conn.RemoteAddr())
+		_ = conn.Close()
+		return
+	}
+
+	ideConn, err := net.DialTimeout("tcp", ide.String(), timeout)
+	if err != nil {
+		p.logf("Unable to connect to the IDE for idekey '%s' at %s: %v", key, ide, err)
+		_ = conn.Close()
+		return
+	}
+	p.logf("Routing the connection from %s with idekey '%s' to %s", conn.RemoteAddr(), key, ide)
+	_ = conn.SetDeadline(time.Time{})
+	if _, err = ideConn.Write(packet); err != nil {
+		_ = ideConn.Close()
+		_ = conn.Close()
+		return
+	}
+	go func() {
+		_, _ = io.Copy(ideConn, reader)
+		_ = ideConn.Close()
+	}()
+	_, _ = io.Copy(conn, ideConn)
+	_ = conn.Close()
+}
+
+// runCommand runs a proxy command and returns its XML response. Commands
+// look like "proxyinit -p 9000 -k PHPSTORM -m 1".
+func (p *Proxy) runCommand(command string, remote net.Addr, allowChanges bool) string {
+	fields := strings.Fields(command)
+	if len(fields) == 0 {
+		return errorResponse("proxyerror", "", "empty command")
+	}
+	name := fields[0]
+	args := map[string]string{}
+	for i := 1; i+1 < len(fields); i += 2 {
+		args[fields[i]] = fields[i+1]
+	}
+	key := args["-k"]
+
+	switch {
+	case name == "proxylist":
+		var response strings.Builder
+		response.WriteString(xml.Header + "<proxylist>")
+		for _, ide := range p.IDEs() {
+			fmt.Fprintf(&response, `<ide idekey="%s" address="%s" port="%d" multiple="%t"/>`, xmlEscape(ide.Key), xmlEscape(ide.Address), ide.Port, ide.Multiple)
+		}
+		response.WriteString("</proxylist>")
+		return response.String()
+	case !allowChanges && (name == "proxyinit" || name == "proxystop"):
+		return errorResponse(name, key, "use the IDE port of the proxy")
+	case name == "proxyinit":
+		port, err := strconv.Atoi(args["-p"])
+		if key == "" || err != nil || port <= 0 || port > 65535 {
+			return errorResponse(name, key, "proxyinit needs -p <port> and -k <idekey>")
+		}
+		address, _, _ := net.SplitHostPort(remote.String())
+		ide := IDE{Key: key, Address: address, Port: port, Multiple: args["-m"] == "1"}
+		p.mu.Lock()
+		p.ides[key] = ide
+		p.mu.Unlock()
+		p.logf("Registered the IDE at %s for idekey '%s'", ide, key)
+		return fmt.Sprintf(`%s<proxyinit success="1" idekey="%s" address="%s" port="%d"/>`, xml.Header, xmlEscape(key), xmlEscape(address), port)
+	case name == "proxystop":
+		p.mu.Lock()
+		_, ok := p.ides[key]
+		delete(p.ides, key)
+		p.mu.Unlock()
+		if !ok {
+			return errorResponse(name, key, "no IDE is registered for this idekey")
+		}
+		p.logf("Unregistered the IDE for idekey '%s'", key)
+		return fmt.Sprintf(`%s<proxystop success="1" idekey="%s"/>`, xml.Header, xmlEscape(key))
+	default:
+		return errorResponse(name, key, "unknown command "+name)
+	}
+}
+
+// errorResponse returns a failed response with a message for the IDE
+func errorResponse(name string, key string, message string) string {
+	return fmt.Sprintf(`%s<%s success="0" idekey="%s"><error id="1"><message>%s</message></error></%s>`, xml.Header, name, xmlEscape(key), xmlEscape(message), name)
+}
+
+func xmlEscape(s string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+// readCommand reads a command terminated by a NUL byte, a newline or the
+// end of the connection
+func readCommand(reader *bufio.Reader) (string, error) {
+	var command strings.Builder
+	for command.Len() < 1024 {
+		b, err := reader.ReadByte()
+		if err == io.EOF && command.Len() > 0 {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+		if b == 0 || b == '\n' {
+			break
+		}
+		command.WriteByte(b)
+	}
+	return strings.TrimSpace(command.String()), nil
+}
+
+// readInitPacket reads the init packet of Xdebug, "<length>\0<xml>\0", and
+// returns it and its IDE key
+func readInitPacket(reader *bufio.Reader) ([]byte, string, error) {
+	lengthField, err := reader.ReadString(0)
+	if err != nil {
+		return nil, "", err
+	}
+	length, err := strconv.Atoi(strings.TrimSuffix(lengthField, "\x00"))
+	if err != nil || length <= 0 || length > maxPacketSize {
+		return nil, "", fmt.Errorf("invalid packet length '%s'", strings.TrimSuffix(lengthField, "\x00"))
+	}
+	body := make([]byte, length+1)
+	if _, err = io.ReadFull(reader, body); err != nil {
+		return nil, "", err
+	}
+
+	var init struct {
+		XMLName xml.Name `xml:"init"`
+		IDEKey  string   `xml:"idekey,attr"`
+	}
+	// Xdebug declares encoding="iso-8859-1", only the ASCII idekey is needed
+	decoder := xml.NewDecoder(bytes.NewReader(body[:length]))
+	decoder.CharsetReader = func(_ string, input io.Reader) (io.Reader, error) {
+		return input, nil
+	}
+	if err = decoder.Decode(&init); err != nil {
+		return nil, "", fmt.Errorf("invalid init packet: %v", err)
+	}
+	if init.IDEKey == "" {
+		return nil, "", errors.New("the init packet has no idekey")
+	}
+	return append([]byte(lengthField), body...), init.IDEKey, nil
+}
+
+// List returns the IDEs registered with the proxy at address
+func List(address string) ([]IDE, error) {
+	conn, err := net.DialTimeout("tcp", address, timeout)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(timeout))
+	if _, err = io.WriteString(conn, "proxylist\x00"); err != nil {
+		return nil, err
+	}
+	response, err := io.ReadAll(conn)
+	if err != nil {
+		return nil, err
+	}
+	var list struct {
+		XMLName xml.Name `xml:"proxylist"`
+		IDEs    []IDE    `xml:"ide"`
+	}
+	if err = xml.Unmarshal(response, &list); err != nil {
+		return nil, fmt.Errorf("unexpected response from %s: %v", address, err)
+	}
+	return list.IDEs, nil
+}
@@ -0,0 +1,101 @@
+package dbgpproxy_test
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"testing"
+
+	"github.com/ddev/ddev/pkg/dbgpproxy"
+	"github.com/stretchr/testify/require"
+)
+
+// TestProxy checks that IDEs register with the proxy and receive the
+// Xdebug connections with their IDE key
+func TestProxy(t *testing.T) {
+	ideListener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	xdebugListener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	proxy := dbgpproxy.New()
+	proxy.Logf = t.Logf
+	done := make(chan error)
+	go func() { done <- proxy.Serve(ctx, ideListener, xdebugListener) }()
+	t.Cleanup(func() {
+		cancel()
+		require.NoError(t, <-done)
+	})
+
+	// The IDE listens for debugging sessions and registers with the proxy
+	ide, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ide.Close()
+	idePort := ide.Addr().(*net.TCPAddr).Port
+
+	response := command(t, ideListener.Addr().String(), fmt.Sprintf("proxyinit -p %d -k my-project -m 0\x00", idePort))
+	require.Contains(t, response, `<proxyinit success="1" idekey="my-project" address="127.0.0.1" port="`+strconv.Itoa(idePort)+`"/>`)
+
+	ides, err := dbgpproxy.List(xdebugListener.Addr().String())
+	require.NoError(t, err)
+	require.Equal(t, []dbgpproxy.IDE{{Key: "my-project", Address: "127.0.0.1", Port: idePort}}, ides)
+
+	// Registering isn't allowed on the Xdebug port
+	response = command(t, xdebugListener.Addr().String(), "proxyinit -p 1234 -k other\x00")
+	require.Contains(t, response, `success="0"`)
+	require.Len(t, proxy.IDEs(), 1)
+
+	// A connection of Xdebug with the IDE key is routed to the IDE, with
+	// the init packet, in both directions
+	initXML := `<?xml version="1.0" encoding="iso-8859-1"?><init xmlns="urn:debugger_protocol_v1" fileuri="file:///var/www/html/index.php" language="PHP" protocol_version="1.0" appid="42" idekey="my-project"/>`
+	packet := strconv.Itoa(len(initXML)) + "\x00" + initXML + "\x00"
+	xdebug, err := net.Dial("tcp", xdebugListener.Addr().String())
+	require.NoError(t, err)
+	defer xdebug.Close()
+	_, err = io.WriteString(xdebug, packet)
+	require.NoError(t, err)
+
+	session, err := ide.Accept()
+	require.NoError(t, err)
+	defer session.Close()
+	received := make([]byte, len(packet))
+	_, err = io.ReadFull(session, received)
+	require.NoError(t, err)
+	require.Equal(t, packet, string(received))
+
+	_, err = io.WriteString(session, "run -i 1\x00")
+	require.NoError(t, err)
+	line, err := bufio.NewReader(xdebug).ReadString(0)
+	require.NoError(t, err)
+	require.Equal(t, "run -i 1\x00", line)
+
+	// A connection with another IDE key is closed
+	otherXML := `<init idekey="someone-else"/>`
+	other, err := net.Dial("tcp", xdebugListener.Addr().String())
+	require.NoError(t, err)
+	defer other.Close()
+	_, err = io.WriteString(other, strconv.Itoa(len(otherXML))+"\x00"+otherXML+"\x00")
+	require.NoError(t, err)
+	_, err = other.Read(make([]byte, 1))
+	require.ErrorIs(t, err, io.EOF)
+
+	response = command(t, ideListener.Addr().String(), "proxystop -k my-project\x00")
+	require.Contains(t, response, `<proxystop success="1" idekey="my-project"/>`)
+	require.Empty(t, proxy.IDEs())
+}
+
+// command sends a proxy command and returns the response
+func command(t *testing.T, address string, cmd string) string {
+	conn, err := net.Dial("tcp", address)
+	require.NoError(t, err)
+	defer conn.Close()
+	_, err = io.WriteString(conn, cmd)
+	require.NoError(t, err)
+	response, err := io.ReadAll(conn)
+	require.NoError(t, err)
+	return string(response)
+}
@@ -499,7 +499,7 @@ func (app *DdevApp) ConfigFileOverrideAction(overrideExistingConfig bool) error
 		}
 		// If the override function has changed the database type
 		// check to make sure that there's not one already existing
-		if origDB != app.Database {
+		if origDB.Type != app.Database.Type || origDB.Version != app.Database.Version {
 			// We can't upgrade database if it already exists
 			dbType, err := app.GetExistingDBType()
 			if err != nil {
@@ -25,6 +25,7 @@ import (
 //go:embed traefik_config_template.yaml
 //go:embed traefik_static_config_template.yaml
 //go:embed traefik_global_config_template.yaml
+//go:embed traefik_idle_wake_template.yaml
 //go:embed drupal/*
 //go:embed magento/*
 //go:embed maho/*
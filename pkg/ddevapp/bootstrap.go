@@ -0,0 +1,210 @@
+package ddevapp
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ddev/ddev/pkg/nodeps"
+	"go.yaml.in/yaml/v4"
+)
+
+// BootstrapStateFileName is the file in .ddev where 'ddev up' records the
+// bootstrap steps it completed
+const BootstrapStateFileName = ".ddev-state"
+
+// BootstrapStep is a step of the bootstrap section of config.yaml, which
+// 'ddev up' runs once, in order. Exactly one of the fields is set.
+type BootstrapStep struct {
+	// AddOn is installed with 'ddev add-on get'
+	AddOn string `yaml:"add_on,omitempty"`
+	// Composer holds the arguments of 'ddev composer', like "install"
+	Composer string `yaml:"composer,omitempty"`
+	// Npm holds the arguments of 'ddev npm', like "ci"
+	Npm string `yaml:"npm,omitempty"`
+	// Database is where the database comes from
+	Database *BootstrapSource `yaml:"database,omitempty"`
+	// Files is where the user-generated files come from
+	Files *BootstrapSource `yaml:"files,omitempty"`
+	// Exec is a command run in the web container
+	Exec string `yaml:"exec,omitempty"`
+	// Ddev holds the arguments of a ddev command run on the host, including custom commands
+	Ddev string `yaml:"ddev,omitempty"`
+}
+
+// BootstrapSource is where a bootstrap step gets the database or the files
+// from. Exactly one of Provider, File and Snapshot is set.
+type BootstrapSource struct {
+	// Provider is pulled from with 'ddev pull'
+	Provider string `yaml:"provider,omitempty"`
+	// File is a dump or archive relative to the project root, imported with 'ddev import-db' or 'ddev import-files'
+	File string `yaml:"file,omitempty"`
+	// Snapshot is restored with 'ddev snapshot restore', only for the database
+	Snapshot string `yaml:"snapshot,omitempty"`
+	// Target is the upload directory the files are imported into, the first one by default
+	Target string `yaml:"target,omitempty"`
+}
+
+// BootstrapState is the content of the .ddev/.ddev-state file
+type BootstrapState struct {
+	// Completed maps the completed bootstrap steps, by their key as
+	// returned by BootstrapStepKeys, to the time they completed
+	Completed map[string]time.Time `yaml:"bootstrap_completed"`
+}
+
+// String describes the step
+func (s BootstrapStep) String() string {
+	switch {
+	case s.AddOn != "":
+		return "add-on get " + s.AddOn
+	case s.Composer != "":
+		return "composer " + s.Composer
+	case s.Npm != "":
+		return "npm " + s.Npm
+	case s.Database != nil:
+		return "database from " + s.Database.String()
+	case s.Files != nil:
+		return "files from " + s.Files.String()
+	case s.Exec != "":
+		return "exec " + s.Exec
+	case s.Ddev != "":
+		return "ddev " + s.Ddev
+	}
+	return ""
+}
+
+// String describes the source
+func (s BootstrapSource) String() string {
+	desc := ""
+	switch {
+	case s.Provider != "":
+		desc = "provider " + s.Provider
+	case s.File != "":
+		desc = "file " + s.File
+	case s.Snapshot != "":
+		desc = "snapshot " + s.Snapshot
+	}
+	if s.Target != "" {
+		desc += " into " + s.Target
+	}
+	return desc
+}
+
+// Validate checks that exactly one thing is done by the step
+func (s BootstrapStep) Validate() error {
+	set := 0
+	for _, isSet := range []bool{s.AddOn != "", s.Composer != "", s.Npm != "", s.Database != nil, s.Files != nil, s.Exec != "", s.Ddev != ""} {
+		if isSet {
+			set++
+		}
+	}
+	if set != 1 {
+		return errors.New("a bootstrap step must have exactly one of add_on, composer, npm, database, files, exec and ddev")
+	}
+	if s.Database != nil {
+		if s.Database.Target != "" {
+			return errors.New("target is only used by files")
+		}
+		return s.Database.validate(true)
+	}
+	if s.Files != nil {
+		return s.Files.validate(false)
+	}
+	return nil
+}
+
+// validate checks that the source has exactly one origin
+func (s BootstrapSource) validate(allowSnapshot bool) error {
+	if !allowSnapshot && s.Snapshot != "" {
+		return errors.New("files can't come from a snapshot")
+	}
+	set := 0
+	for _, origin := range []string{s.Provider, s.File, s.Snapshot} {
+		if origin != "" {
+			set++
+		}
+	}
+	if set != 1 {
+		return errors.New("a bootstrap source must have exactly one of provider, file and snapshot")
+	}
+	return nil
+}
+
+// NeedsRunningProject reports whether the project must be running for the
+// step, only add-ons are installed before it starts
+func (s BootstrapStep) NeedsRunningProject() bool {
+	return s.AddOn == ""
+}
+
+// GetBootstrapStatePath returns the path of the .ddev/.ddev-state file
+func (app *DdevApp) GetBootstrapStatePath() string {
+	return app.GetConfigPath(BootstrapStateFileName)
+}
+
+// ReadBootstrapState reads the bootstrap steps completed by 'ddev up', no
+// step is completed if the state file doesn't exist
+func (app *DdevApp) ReadBootstrapState() (*BootstrapState, error) {
+	state := &BootstrapState{Completed: map[string]time.Time{}}
+	content, err := os.ReadFile(app.GetBootstrapStatePath())
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err = yaml.Unmarshal(content, state); err != nil {
+		return nil, fmt.Errorf("unable to parse %s: %v", app.GetBootstrapStatePath(), err)
+	}
+	if state.Completed == nil {
+		state.Completed = map[string]time.Time{}
+	}
+	return state, nil
+}
+
+// WriteBootstrapState writes the bootstrap steps completed by 'ddev up'
+func (app *DdevApp) WriteBootstrapState(state *BootstrapState) error {
+	content, err := yaml.Marshal(state)
+	if err != nil {
+		return err
+	}
+	content = append([]byte(nodeps.DdevFileSignature+"\n# The bootstrap steps completed by 'ddev up', remove this file to run them again.\n"), content...)
+	return os.WriteFile(app.GetBootstrapStatePath(), content, 0644)
+}
+
+// ResetBootstrapState forgets the bootstrap steps completed by 'ddev up'
+func (app *DdevApp) ResetBootstrapState() error {
+	if err := os.Remove(app.GetBootstrapStatePath()); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// BootstrapStepKeys returns the keys of the steps in the state file. The
+// key is the description of the step, so a changed step runs again, and
+// a step that occurs again is numbered, like "exec drush cr (2)", so each
+// occurrence runs.
+func BootstrapStepKeys(steps []BootstrapStep) []string {
+	keys := make([]string, len(steps))
+	seen := map[string]int{}
+	for i, step := range steps {
+		desc := step.String()
+		seen[desc]++
+		keys[i] = desc
+		if seen[desc] > 1 {
+			keys[i] = fmt.Sprintf("%s (%d)", desc, seen[desc])
+		}
+	}
+	return keys
+}
+
+// IsCompleted reports whether 'ddev up' completed the step with the key
+func (state *BootstrapState) IsCompleted(key string) bool {
+	_, ok := state.Completed[key]
+	return ok
+}
+
+// Complete records that 'ddev up' completed the step with the key
+func (state *BootstrapState) Complete(key string) {
+	state.Completed[key] = time.Now()
+}
@@ -0,0 +1,87 @@
+package ddevapp
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestBootstrapConfig checks reading and validating the bootstrap steps
+func TestBootstrapConfig(t *testing.T) {
+	app := &DdevApp{AppRoot: t.TempDir()}
+	require.NoError(t, os.MkdirAll(app.GetConfigPath(""), 0755))
+	require.NoError(t, os.WriteFile(app.GetConfigPath("config.yaml"), []byte(`name: shop
+bootstrap:
+  - add_on: ddev/ddev-redis
+  - composer: install
+  - database:
+      file: .ddev/seed/db.sql.gz
+  - files:
+      provider: upsun
+      target: web/files
+  - exec: drush cr
+`), 0644))
+	_, err := app.ReadConfig(true)
+	require.NoError(t, err)
+	require.Len(t, app.Bootstrap, 5)
+	var steps []string
+	for _, step := range app.Bootstrap {
+		require.NoError(t, step.Validate())
+		steps = append(steps, step.String())
+	}
+	require.Equal(t, []string{
+		"add-on get ddev/ddev-redis",
+		"composer install",
+		"database from file .ddev/seed/db.sql.gz",
+		"files from provider upsun into web/files",
+		"exec drush cr",
+	}, steps)
+	require.False(t, app.Bootstrap[0].NeedsRunningProject())
+	require.True(t, app.Bootstrap[1].NeedsRunningProject())
+
+	for _, step := range []BootstrapStep{
+		{},
+		{Composer: "install", Npm: "ci"},
+		{Database: &BootstrapSource{}},
+		{Database: &BootstrapSource{File: "db.sql", Snapshot: "base"}},
+		{Database: &BootstrapSource{File: "db.sql", Target: "files"}},
+		{Files: &BootstrapSource{Snapshot: "base"}},
+	} {
+		require.Error(t, step.Validate(), step.String())
+	}
+}
+
+// TestBootstrapState checks recording the completed bootstrap steps
+func TestBootstrapState(t *testing.T) {
+	app := &DdevApp{AppRoot: t.TempDir()}
+	require.NoError(t, os.MkdirAll(app.GetConfigPath(""), 0755))
+	install := BootstrapStep{Composer: "install"}
+	clearCache := BootstrapStep{Exec: "drush cr"}
+	keys := BootstrapStepKeys([]BootstrapStep{clearCache, install, clearCache})
+	require.Equal(t, []string{"exec drush cr", "composer install", "exec drush cr (2)"}, keys)
+
+	state, err := app.ReadBootstrapState()
+	require.NoError(t, err)
+	require.False(t, state.IsCompleted(keys[1]))
+	state.Complete(keys[0])
+	state.Complete(keys[1])
+	require.NoError(t, app.WriteBootstrapState(state))
+	require.FileExists(t, filepath.Join(app.AppRoot, ".ddev", BootstrapStateFileName))
+
+	state, err = app.ReadBootstrapState()
+	require.NoError(t, err)
+	require.True(t, state.IsCompleted(keys[1]))
+	// A step that occurs again runs again
+	require.True(t, state.IsCompleted(keys[0]))
+	require.False(t, state.IsCompleted(keys[2]))
+	// A changed step runs again
+	require.False(t, state.IsCompleted(BootstrapStepKeys([]BootstrapStep{{Composer: "install --no-dev"}})[0]))
+
+	require.NoError(t, app.ResetBootstrapState())
+	require.NoError(t, app.ResetBootstrapState())
+	state, err = app.ReadBootstrapState()
+	require.NoError(t, err)
+	require.Empty(t, state.Completed)
+}
@@ -0,0 +1,265 @@
+package ddevapp
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	ddevexec "github.com/ddev/ddev/pkg/exec"
+	"github.com/ddev/ddev/pkg/fileutil"
+	"github.com/ddev/ddev/pkg/globalconfig"
+	"github.com/ddev/ddev/pkg/nodeps"
+	"github.com/ddev/ddev/pkg/util"
+	"github.com/otiai10/copy"
+)
+
+// CloneConfigFileName is the config override that gives a clone its name.
+// It matches config.*.local.yaml, so it isn't committed from a git worktree.
+const CloneConfigFileName = "config.zz-clone.local.yaml"
+
+// CloneOptions are the options of DdevApp.Clone
+type CloneOptions struct {
+	// Name is the name of the new project
+	Name string
+	// Dir is the directory of the new project, next to the original one by default
+	Dir string
+	// GitWorktree is a branch checked out in a git worktree instead of copying the code
+	GitWorktree string
+}
+
+// Clone creates a copy of the project named opts.Name, with its code, database
+// and upload directories, and starts it. The original project isn't changed,
+// except for a temporary snapshot of its database. If it fails, the copy is
+// removed.
+func (app *DdevApp) Clone(opts CloneOptions) (clone *DdevApp, err error) {
+	if err := ValidateProjectName(opts.Name); err != nil {
+		return nil, err
+	}
+	if p := globalconfig.GetProject(opts.Name); p != nil {
+		return nil, fmt.Errorf("a project named '%s' already exists in %s", opts.Name, p.AppRoot)
+	}
+	dir := opts.Dir
+	if dir == "" {
+		dir = filepath.Join(filepath.Dir(app.AppRoot), opts.Name)
+	}
+	dir, err = filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	if fileutil.FileExists(dir) {
+		return nil, fmt.Errorf("%s already exists, choose another directory with --dir", dir)
+	}
+
+	copyDB := !app.IsDBOmitted()
+	if copyDB {
+		if status, _ := app.SiteStatus(); status != SiteRunning {
+			return nil, fmt.Errorf("project %s must be running to copy its database, start it with 'ddev start %s'", app.Name, app.Name)
+		}
+	}
+	if app.IsMutagenEnabled() {
+		if err = app.MutagenSyncFlush(); err != nil {
+			return nil, fmt.Errorf("unable to sync the code of %s from the container: %v", app.Name, err)
+		}
+	}
+
+	// From here on, remove the copy if anything fails
+	var startedClone *DdevApp
+	createdBranch := false
+	defer func() {
+		if err == nil {
+			return
+		}
+		clone = nil
+		util.Warning("Removing the copy %s in %s after the failure", opts.Name, dir)
+		if startedClone != nil {
+			if stopErr := startedClone.Stop(true, false); stopErr != nil {
+				util.Warning("Unable to remove the containers and volumes of %s: %v", opts.Name, stopErr)
+			}
+		}
+		_ = globalconfig.RemoveProjectInfo(opts.Name)
+		if opts.GitWorktree != "" && fileutil.IsDirectory(dir) {
+			if out, removeErr := ddevexec.RunHostCommand("git", "-C", app.AppRoot, "worktree", "remove", "--force", dir); removeErr != nil {
+				util.Warning("Unable to remove the git worktree %s: %v, output=%s", dir, removeErr, strings.TrimSpace(out))
+			}
+		}
+		if createdBranch {
+			_, _ = ddevexec.RunHostCommand("git", "-C", app.AppRoot, "branch", "-D", opts.GitWorktree)
+		}
+		_ = os.RemoveAll(dir)
+	}()
+
+	if opts.GitWorktree != "" {
+		util.Success("Creating a git worktree of branch %s in %s", opts.GitWorktree, dir)
+		if createdBranch, err = app.addGitWorktree(dir, opts.GitWorktree); err != nil {
+			return nil, err
+		}
+		// The worktree has the committed .ddev files of its branch, only
+		// add the ones that aren't committed
+		if err = app.copyUntrackedConfigFiles(dir); err != nil {
+			return nil, err
+		}
+		for _, uploadDir := range app.GetUploadDirs() {
+			src := app.calculateHostUploadDirFullPath(uploadDir)
+			rel, err := filepath.Rel(app.AppRoot, src)
+			if err != nil || strings.HasPrefix(rel, "..") || !fileutil.IsDirectory(src) {
+				continue
+			}
+			util.Success("Copying upload directory %s", rel)
+			if err = copy.Copy(src, filepath.Join(dir, rel), copy.Options{OnSymlink: shallowSymlinks}); err != nil {
+				return nil, fmt.Errorf("unable to copy upload directory %s: %v", rel, err)
+			}
+		}
+	} else {
+		util.Success("Copying %s to %s", app.AppRoot, dir)
+		if err = app.copyCloneFiles(app.AppRoot, dir); err != nil {
+			return nil, err
+		}
+	}
+
+	override := fmt.Sprintf(`%s
+# The name of this copy of the %s project made by 'ddev clone'.
+# The hostnames and host ports of the original project are dropped, so
+# both projects can run at the same time.
+override_config: true
+name: %s
+name_from: ""
+additional_hostnames: []
+additional_fqdns: []
+host_db_port: ""
+host_webserver_port: ""
+host_https_port: ""
+host_mailpit_port: ""
+host_xhgui_port: ""
+`, nodeps.DdevFileSignature, app.Name, opts.Name)
+	if err = os.WriteFile(filepath.Join(dir, ".ddev", CloneConfigFileName), []byte(override), 0644); err != nil {
+		return nil, err
+	}
+
+	snapshotName := ""
+	if copyDB {
+		snapshotName = fmt.Sprintf("%s_clone_%s", app.Name, time.Now().Format("20060102150405"))
+		if snapshotName, err = app.Snapshot(snapshotName, false); err != nil {
+			return nil, fmt.Errorf("unable to snapshot the database of %s: %v", app.Name, err)
+		}
+		if err = moveCloneSnapshot(app.GetConfigPath("db_snapshots"), filepath.Join(dir, ".ddev", "db_snapshots"), snapshotName); err != nil {
+			return nil, err
+		}
+	}
+
+	clone, err = NewApp(dir, true)
+	if err != nil {
+		return nil, err
+	}
+	if clone.Name != opts.Name {
+		return nil, fmt.Errorf("the configuration in %s gives the copy the name %s instead of %s", clone.GetConfigPath(""), clone.Name, opts.Name)
+	}
+	startedClone = clone
+	if err = clone.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start %s: %v", clone.Name, err)
+	}
+	if snapshotName != "" {
+		if err = clone.RestoreSnapshot(snapshotName); err != nil {
+			return nil, fmt.Errorf("failed to restore the database of %s into %s: %v", app.Name, clone.Name, err)
+		}
+	}
+	return clone, nil
+}
+
+// addGitWorktree checks out branch in a new worktree in dir, creating the
+// branch from the current commit if it doesn't exist, and returns whether it
+// created the branch
+func (app *DdevApp) addGitWorktree(dir string, branch string) (bool, error) {
+	createBranch := false
+	args := []string{"-C", app.AppRoot, "worktree", "add", dir, branch}
+	if _, err := ddevexec.RunHostCommand("git", "-C", app.AppRoot, "rev-parse", "--verify", "--quiet", "refs/heads/"+branch); err != nil {
+		createBranch = true
+		args = []string{"-C", app.AppRoot, "worktree", "add", "-b", branch, dir}
+	}
+	if out, err := ddevexec.RunHostCommand("git", args...); err != nil {
+		return false, fmt.Errorf("unable to create a git worktree of %s: %v, output=%s", app.AppRoot, err, strings.TrimSpace(out))
+	}
+	return createBranch, nil
+}
+
+// copyUntrackedConfigFiles copies the .ddev files that aren't committed,
+// like config.local.yaml and ignored add-on files, to the same place in dir
+func (app *DdevApp) copyUntrackedConfigFiles(dir string) error {
+	out, err := ddevexec.HostCommand("git", "-C", app.AppRoot, "ls-files", "--others", "-z", "--", ".ddev").Output()
+	if err != nil {
+		return fmt.Errorf("unable to list the .ddev files of %s that aren't committed: %v", app.AppRoot, err)
+	}
+	for _, rel := range strings.Split(string(out), "\x00") {
+		if rel == "" {
+			continue
+		}
+		src := filepath.Join(app.AppRoot, filepath.FromSlash(rel))
+		if app.skipCloneFile(src) {
+			continue
+		}
+		if err = copy.Copy(src, filepath.Join(dir, filepath.FromSlash(rel)), copy.Options{OnSymlink: shallowSymlinks}); err != nil {
+			return fmt.Errorf("unable to copy %s: %v", rel, err)
+		}
+	}
+	return nil
+}
+
+// copyCloneFiles copies src to dest without the files skipped by
+// skipCloneFile
+func (app *DdevApp) copyCloneFiles(src string, dest string) error {
+	return copy.Copy(src, dest, copy.Options{
+		OnSymlink: shallowSymlinks,
+		Skip: func(_ os.FileInfo, path string, _ string) (bool, error) {
+			return app.skipCloneFile(path), nil
+		},
+	})
+}
+
+// skipCloneFile returns true for the snapshots and the files named after the
+// original project, or in their directories, which the copy generates for
+// its own name
+func (app *DdevApp) skipCloneFile(path string) bool {
+	configDir := app.GetConfigPath("")
+	skip := []string{
+		filepath.Join(configDir, "db_snapshots"),
+		filepath.Join(configDir, ".downloads"),
+		filepath.Join(configDir, CloneConfigFileName),
+		filepath.Join(configDir, "traefik", "config", app.Name+".yaml"),
+		filepath.Join(configDir, "traefik", "certs", app.Name+".crt"),
+		filepath.Join(configDir, "traefik", "certs", app.Name+".key"),
+	}
+	for p := path; p != configDir && strings.HasPrefix(p, configDir); p = filepath.Dir(p) {
+		if !slices.Contains(skip, p) {
+			continue
+		}
+		if strings.Contains(p, "traefik") && fileutil.FileExists(p) {
+			if err := fileutil.CheckSignatureOrNoFile(p, nodeps.DdevFileSignature); err != nil {
+				util.Warning("Not copying the customized %s, it's only used by project %s", p, app.Name)
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// shallowSymlinks copies symlinks as they are
+func shallowSymlinks(string) copy.SymlinkAction {
+	return copy.Shallow
+}
+
+// moveCloneSnapshot moves the files of a snapshot, the database and the
+// builtin services, from one snapshots directory to another
+func moveCloneSnapshot(srcDir string, destDir string, snapshotName string) error {
+	files, err := copySnapshotFiles(srcDir, destDir, snapshotName)
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		if err = os.Remove(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package ddevapp
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/ddev/ddev/pkg/nodeps"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCopyCloneFiles checks that the files generated for the name of the
+// original project and its snapshots aren't copied to a clone
+func TestCopyCloneFiles(t *testing.T) {
+	appRoot := t.TempDir()
+	app := &DdevApp{Name: "orig", AppRoot: appRoot}
+	writeTemplateFiles(t, appRoot, map[string]string{
+		"web/index.php":                       "<?php",
+		".ddev/config.yaml":                   "name: orig\n",
+		".ddev/db_snapshots/s-mariadb_10.zst": "",
+		".ddev/traefik/config/orig.yaml":      nodeps.DdevFileSignature + "\n",
+		".ddev/traefik/config/extra.yaml":     "",
+		".ddev/traefik/certs/orig.crt":        nodeps.DdevFileSignature + "\n",
+	})
+
+	dest := filepath.Join(t.TempDir(), "copy")
+	require.NoError(t, app.copyCloneFiles(appRoot, dest))
+	require.FileExists(t, filepath.Join(dest, "web/index.php"))
+	require.FileExists(t, filepath.Join(dest, ".ddev/config.yaml"))
+	require.FileExists(t, filepath.Join(dest, ".ddev/traefik/config/extra.yaml"))
+	require.NoDirExists(t, filepath.Join(dest, ".ddev/db_snapshots"))
+	require.NoFileExists(t, filepath.Join(dest, ".ddev/traefik/config/orig.yaml"))
+	require.NoFileExists(t, filepath.Join(dest, ".ddev/traefik/certs/orig.crt"))
+}
+
+// TestCopyUntrackedConfigFiles checks that only the .ddev files that aren't
+// committed are copied to a git worktree
+func TestCopyUntrackedConfigFiles(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is needed to test copying untracked files")
+	}
+	appRoot := t.TempDir()
+	app := &DdevApp{Name: "orig", AppRoot: appRoot}
+	writeTemplateFiles(t, appRoot, map[string]string{
+		".ddev/config.yaml":                   "name: orig\n",
+		".ddev/.gitignore":                    "/ignored.yaml\n",
+		".ddev/config.local.yaml":             "php_version: \"8.4\"\n",
+		".ddev/ignored.yaml":                  "",
+		".ddev/db_snapshots/s-mariadb_10.zst": "",
+		"web/untracked.php":                   "<?php",
+	})
+	for _, args := range [][]string{
+		{"init", "--quiet"},
+		{"add", ".ddev/config.yaml", ".ddev/.gitignore"},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "--quiet", "-m", "config"},
+	} {
+		out, err := exec.Command("git", append([]string{"-C", appRoot}, args...)...).CombinedOutput()
+		require.NoError(t, err, "git %v: %s", args, out)
+	}
+
+	dest := t.TempDir()
+	require.NoError(t, app.copyUntrackedConfigFiles(dest))
+	require.FileExists(t, filepath.Join(dest, ".ddev/config.local.yaml"))
+	require.FileExists(t, filepath.Join(dest, ".ddev/ignored.yaml"))
+	require.NoFileExists(t, filepath.Join(dest, ".ddev/config.yaml"))
+	require.NoFileExists(t, filepath.Join(dest, ".ddev/.gitignore"))
+	require.NoDirExists(t, filepath.Join(dest, ".ddev/db_snapshots"))
+	require.NoDirExists(t, filepath.Join(dest, "web"))
+}
+
+// TestMoveCloneSnapshot checks moving the files of a snapshot
+func TestMoveCloneSnapshot(t *testing.T) {
+	srcDir, destDir := t.TempDir(), filepath.Join(t.TempDir(), "db_snapshots")
+	for _, f := range []string{"orig_clone_1-mariadb_10.11.zst", "orig_clone_1-redis.rdb", "other-mariadb_10.11.zst"} {
+		require.NoError(t, os.WriteFile(filepath.Join(srcDir, f), []byte(f), 0644))
+	}
+
+	require.NoError(t, moveCloneSnapshot(srcDir, destDir, "orig_clone_1"))
+	require.FileExists(t, filepath.Join(destDir, "orig_clone_1-mariadb_10.11.zst"))
+	require.FileExists(t, filepath.Join(destDir, "orig_clone_1-redis.rdb"))
+	require.NoFileExists(t, filepath.Join(srcDir, "orig_clone_1-mariadb_10.11.zst"))
+	require.FileExists(t, filepath.Join(srcDir, "other-mariadb_10.11.zst"))
+
+	require.Error(t, moveCloneSnapshot(srcDir, destDir, "missing"))
+}
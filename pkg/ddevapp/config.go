@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
 	"regexp"
 	"runtime"
@@ -119,6 +120,11 @@ func NewApp(appRoot string, includeOverrides bool) (*DdevApp, error) {
 		}
 	}
 
+	// Give each git worktree or branch its own project name
+	if err := app.ApplyNameFrom(); err != nil {
+		util.WarningOnce("Unable to derive the name of project %s with name_from: %v", app.Name, err)
+	}
+
 	// Make "drupal" an alias to latest modern drupal version
 	if app.Type == nodeps.AppTypeDrupal {
 		app.Type = nodeps.AppTypeDrupalLatestStable
@@ -229,6 +235,9 @@ func (app *DdevApp) WriteConfig() error {
 	// Work against a copy of the DdevApp, since we don't want to actually change it.
 	appcopy := *app
 
+	// Keep the configured name when it was derived with name_from
+	appcopy.Name = strings.TrimSuffix(appcopy.Name, app.nameFromSuffix)
+
 	// If the app name has been changed by `config.*.yaml`,
 	// remove it from the main config.yaml file.
 	if hasConfigNameOverride, _ := app.HasConfigNameOverride(); hasConfigNameOverride {
@@ -514,6 +523,22 @@ func (app *DdevApp) ValidateConfig() error {
 		return err
 	}
 
+	if app.NameFrom != "" && !slices.Contains(ValidNameFromValues, app.NameFrom) {
+		return fmt.Errorf("invalid name_from '%s', must be one of %v", app.NameFrom, ValidNameFromValues)
+	}
+
+	for i, step := range app.Bootstrap {
+		if err := step.Validate(); err != nil {
+			return fmt.Errorf("invalid bootstrap step %d: %v", i+1, err)
+		}
+	}
+
+	for i, check := range app.HealthChecks {
+		if err := check.Validate(); err != nil {
+			return fmt.Errorf("invalid health check %d: %v", i+1, err)
+		}
+	}
+
 	// Validate docroot
 	if err := ValidateDocroot(app.Docroot); err != nil {
 		return err
@@ -554,11 +579,44 @@ func (app *DdevApp) ValidateConfig() error {
 		return fmt.Errorf("the %s project has an invalid nodejs_version: %q, Node.js versions cannot contain whitespace; leave it empty to use the DDEV default", app.Name, app.NodeJSVersion)
 	}
 
+	// Validate Xdebug settings
+	if err := types.CheckValidXdebugMode(app.XdebugMode); err != nil {
+		return fmt.Errorf("the %s project has an invalid xdebug_mode: %v", app.Name, err)
+	}
+	if err := types.CheckValidXdebugStartWithRequest(app.XdebugStartWithRequest); err != nil {
+		return fmt.Errorf("the %s project has an invalid xdebug_start_with_request: %v", app.Name, err)
+	}
+
 	// Validate webserver type
 	if !nodeps.IsValidWebserverType(app.WebserverType) {
 		return fmt.Errorf("the %s project has an unsupported webserver type: %s, DDEV (%s) only supports the following webserver types: %s", app.Name, app.WebserverType, runtime.GOARCH, nodeps.GetValidWebserverTypes()).(invalidWebserverType)
 	}
 
+	if app.WebserverType == nodeps.WebserverFrankenPHP && app.PHPVersion != nodeps.FrankenPHPPHPVersion {
+		return fmt.Errorf("the %s project has php_version: %s, but webserver_type: %s always runs its embedded PHP %s; use php_version: %s", app.Name, app.PHPVersion, nodeps.WebserverFrankenPHP, nodeps.FrankenPHPPHPVersion, nodeps.FrankenPHPPHPVersion)
+	}
+
+	if app.FrankenPHPWorker != "" {
+		if app.WebserverType != nodeps.WebserverFrankenPHP {
+			return fmt.Errorf("the %s project has frankenphp_worker: %s, but it can only be used with webserver_type: %s", app.Name, app.FrankenPHPWorker, nodeps.WebserverFrankenPHP)
+		}
+		if path.IsAbs(app.FrankenPHPWorker) || strings.HasPrefix(path.Clean(app.FrankenPHPWorker), "..") {
+			return fmt.Errorf("the %s project has an invalid frankenphp_worker: %s, it must be a path relative to the project root, for example 'public/index.php'", app.Name, app.FrankenPHPWorker)
+		}
+	}
+
+	if err := app.validatePHPPools(); err != nil {
+		return err
+	}
+
+	if err := app.validateBuiltinServices(); err != nil {
+		return err
+	}
+
+	if err := app.validateDatabaseExtensions(); err != nil {
+		return err
+	}
+
 	if !nodeps.IsValidOmitContainers(app.OmitContainers) {
 		return fmt.Errorf("the %s project has an unsupported omit_containers: %s, DDEV (%s) only supports the following for omit_containers: %s", app.Name, app.OmitContainers, runtime.GOARCH, nodeps.GetValidOmitContainers()).(InvalidOmitContainers)
 	}
@@ -741,7 +799,7 @@ func (app *DdevApp) FixObsolete() {
 	}
 
 	// Remove old global commands
-	for _, command := range []string{"host/sequelpro", "host/yarn", "host/xhgui", "web/nvm", "web/autocomplete/nvm", "web/python", "web/typo3cms"} {
+	for _, command := range []string{"host/sequelpro", "host/yarn", "host/xhgui", "web/nvm", "web/autocomplete/nvm", "web/python", "web/typo3cms", "web/xhprof"} {
 		cmdPath := filepath.Join(globalconfig.GetGlobalDdevDir(), "commands/", command)
 		signatureFound, err := fileutil.FgrepStringInFile(cmdPath, nodeps.DdevFileSignature)
 		if err == nil && signatureFound {
@@ -875,6 +933,8 @@ type composeYAMLVars struct {
 	HostXHGuiPort             string
 	XhguiImage                string
 	XHProfMode                types.XHProfMode
+	XdebugProxy               bool
+	BuiltinServices           []BuiltinServiceTemplate
 }
 
 // RenderComposeYAML renders the contents of .ddev/.ddev-docker-compose*.
@@ -962,7 +1022,9 @@ func (app *DdevApp) RenderComposeYAML() (string, error) {
 		HostXHGuiPort:           app.HostXHGuiPort,
 		XhguiImage:              docker.GetXhguiImage(),
 		XHProfMode:              app.GetXHProfMode(),
+		BuiltinServices:         app.getBuiltinServiceTemplates(),
 		UseHardenedImages:       globalconfig.DdevGlobalConfig.UseHardenedImages,
+		XdebugProxy:             globalconfig.DdevGlobalConfig.XdebugProxy,
 	}
 	// We don't want to bind-mount Git directory if it doesn't exist
 	if fileutil.IsDirectory(filepath.Join(app.AppRoot, ".git")) {
@@ -1076,6 +1138,12 @@ EOF
 	if app.CorepackEnable {
 		extraWebContent = extraWebContent + "\nRUN (command -v corepack >/dev/null 2>&1 || log-stderr.sh npm install -g corepack -f || true) && log-stderr.sh corepack enable || true"
 	}
+	// Add additional php-fpm pools for php_pools
+	phpPoolsContent, err := app.phpPoolsBuildContent()
+	if err != nil {
+		return "", err
+	}
+	extraWebContent = extraWebContent + phpPoolsContent
 	// Add supervisord config for WebExtraDaemons
 	var supervisorGroup []string
 	for _, appStart := range app.WebExtraDaemons {
@@ -1094,6 +1162,14 @@ stdout_logfile_maxbytes=0
 redirect_stderr=true
 stopasgroup=true
 `, appStart.Name, appStart.Command, appStart.Directory)
+		// Run the daemon with its own php_version from php_pools
+		binDir, err := app.GetPHPBinDir(appStart.PHPVersion)
+		if err != nil {
+			return "", fmt.Errorf("invalid php_version for web_extra_daemons '%s': %v", appStart.Name, err)
+		}
+		if binDir != "" {
+			supervisorConf = supervisorConf + fmt.Sprintf("environment=PATH=\"%s:%%(ENV_PATH)s\"\n", binDir)
+		}
 		err = os.WriteFile(app.GetConfigPath(fmt.Sprintf(".webimageBuild/%s.conf", appStart.Name)), []byte(supervisorConf), 0755)
 		if err != nil {
 			return "", fmt.Errorf("failed to write .webimageBuild/%s.conf: %v", appStart.Name, err)
@@ -1202,6 +1278,12 @@ find / -type d \( -user postgres -o -group postgres \) -exec chown -Rh %[2]s:%[3
 find / -type f \( -user postgres -o -group postgres \) -exec chown -h %[2]s:%[3]s {} + 2>/dev/null || true
 EOF
 `, app.GetMaxContainerWaitTime(), uid, gid)
+		// database.extensions and database.settings, after the apt sources are patched above
+		postgresExtensionsContent, err := app.postgresExtensionsBuildContent()
+		if err != nil {
+			return "", err
+		}
+		extraDBContent = extraDBContent + postgresExtensionsContent
 	}
 
 	err = WriteBuildDockerfile(app, app.GetConfigPath(".dbimageBuild/Dockerfile"), filepath.Join(globalconfig.GetGlobalDdevDir(), "db-build"), app.GetConfigPath("db-build"), app.DBImageExtraPackages, "", extraDBContent)
@@ -1811,17 +1893,21 @@ func PrepDdevDirectory(app *DdevApp) error {
 		"**/*.example",
 		".dbimageBuild",
 		".ddev-docker-*.yaml",
+		".ddev-state",
 		".*downloads",
 		".homeadditions",
 		".importdb*",
 		".webimageBuild",
 		"apache/apache-site.conf",
+		"caddy/Caddyfile",
 		"commands/.gitattributes",
 		"config.local.y*ml",
 		"config.*.local.y*ml",
 		"db_snapshots",
+		"frankenphp/Caddyfile",
 		"mutagen/mutagen.yml",
 		"mutagen/.start-synced",
+		"nginx/php-pools.conf",
 		"nginx_full/nginx-site.conf",
 		"postgres/postgresql.conf",
 		"providers/acquia.yaml",
@@ -1834,6 +1920,7 @@ func PrepDdevDirectory(app *DdevApp) error {
 		fmt.Sprintf("traefik/config/%s.yaml", app.Name),
 		fmt.Sprintf("traefik/certs/%s.crt", app.Name),
 		fmt.Sprintf("traefik/certs/%s.key", app.Name),
+		"xdebug-profiles",
 		"xhprof/xhprof_prepend.php",
 		"**/README.*",
 	)
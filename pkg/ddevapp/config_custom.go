@@ -151,6 +151,16 @@ func (app *DdevApp) CheckCustomConfig(showAll bool) (message string, hasWarnings
 			checkOnlyWhen: func() bool { return app.WebserverType == nodeps.WebserverApacheFPM },
 			displayName:   "Web server",
 		},
+		{
+			collectFiles: func() ([]string, error) {
+				return filepath.Glob(filepath.Join(ddevDir, "caddy", "*"))
+			},
+			expectedDdevFiles: func() ([]string, error) {
+				return []string{app.GetConfigPath("caddy/Caddyfile"), app.GetConfigPath("caddy/README.caddy.txt")}, nil
+			},
+			checkOnlyWhen: func() bool { return app.WebserverType == nodeps.WebserverCaddyFPM },
+			displayName:   "Web server",
+		},
 		{
 			collectFiles: func() ([]string, error) {
 				return getBuildDockerfilesInDir(filepath.Join(globalconfig.GetGlobalDdevDir(), "db-build"))
@@ -165,6 +175,16 @@ func (app *DdevApp) CheckCustomConfig(showAll bool) (message string, hasWarnings
 			checkOnlyWhen: func() bool { return !slices.Contains(app.OmitContainers, "db") },
 			displayName:   "Database",
 		},
+		{
+			collectFiles: func() ([]string, error) {
+				return filepath.Glob(filepath.Join(ddevDir, "frankenphp", "*"))
+			},
+			expectedDdevFiles: func() ([]string, error) {
+				return []string{app.GetConfigPath("frankenphp/Caddyfile"), app.GetConfigPath("frankenphp/README.frankenphp.txt")}, nil
+			},
+			checkOnlyWhen: func() bool { return app.WebserverType == nodeps.WebserverFrankenPHP },
+			displayName:   "Web server",
+		},
 		{
 			collectFiles: func() ([]string, error) {
 				return []string{app.GetConfigPath("mutagen/mutagen.yml")}, nil
@@ -189,6 +209,9 @@ func (app *DdevApp) CheckCustomConfig(showAll bool) (message string, hasWarnings
 			collectFiles: func() ([]string, error) {
 				return filepath.Glob(filepath.Join(ddevDir, "nginx", "*.conf"))
 			},
+			expectedDdevFiles: func() ([]string, error) {
+				return []string{app.GetConfigPath("nginx/php-pools.conf")}, nil
+			},
 			checkOnlyWhen: func() bool { return app.WebserverType == nodeps.WebserverNginxFPM },
 			displayName:   "Web server",
 		},
@@ -459,6 +482,21 @@ func (app *DdevApp) CheckCustomConfig(showAll bool) (message string, hasWarnings
 		})
 	}
 
+	// A postgresql.conf taken over by the user can set the same server settings
+	// as database.extensions and database.settings, which silently win because
+	// they're loaded later from /etc/postgresql/conf.d.
+	if app.Database.Type == nodeps.Postgres && !slices.Contains(app.OmitContainers, "db") {
+		confPath := app.GetConfigPath("postgres/postgresql.conf")
+		if sigExists, err := fileutil.FgrepStringInFile(confPath, nodeps.DdevFileSignature); err == nil && !sigExists {
+			if conflicts, err := findPostgresSettingConflicts(confPath, app.getPostgresManagedSettings()); err == nil && len(conflicts) > 0 {
+				findings = append(findings, finding{
+					category: "Database",
+					files:    []fileInfo{{path: fmt.Sprintf("%s (conflicts with database.extensions/database.settings: %s)", confPath, strings.Join(conflicts, ", "))}},
+				})
+			}
+		}
+	}
+
 	// Build message for all findings
 	if len(findings) > 0 {
 		// Group findings by category
@@ -767,6 +767,88 @@ func TestConfigValidate(t *testing.T) {
 	require.Contains(t, err.Error(), "unsupported webserver type")
 	app.WebserverType = nodeps.WebserverDefault
 
+	// xdebug_mode may combine modes, xdebug_start_with_request is trigger or yes
+	app.XdebugMode = "profile,debug"
+	app.XdebugStartWithRequest = "trigger"
+	err = app.ValidateConfig()
+	require.NoError(t, err)
+	app.XdebugMode = "profiler"
+	err = app.ValidateConfig()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid xdebug_mode")
+	app.XdebugMode = ""
+	app.XdebugStartWithRequest = "no"
+	err = app.ValidateConfig()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid xdebug_start_with_request")
+	app.XdebugStartWithRequest = ""
+
+	// frankenphp_worker only makes sense with webserver_type: frankenphp
+	app.FrankenPHPWorker = "public/index.php"
+	err = app.ValidateConfig()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "can only be used with webserver_type: frankenphp")
+	app.WebserverType = nodeps.WebserverFrankenPHP
+	app.PHPVersion = nodeps.PHP84
+	err = app.ValidateConfig()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "always runs its embedded PHP")
+	app.PHPVersion = nodeps.FrankenPHPPHPVersion
+	err = app.ValidateConfig()
+	require.NoError(t, err)
+	app.FrankenPHPWorker = "../outside.php"
+	err = app.ValidateConfig()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid frankenphp_worker")
+	app.FrankenPHPWorker = ""
+	app.WebserverType = nodeps.WebserverDefault
+	app.PHPVersion = nodeps.PHPDefault
+
+	// php_pools need a valid, unique path and a version other than php_version
+	app.PHPPools = []ddevapp.PHPPool{{Version: nodeps.PHP74, Path: "/legacy"}}
+	err = app.ValidateConfig()
+	require.NoError(t, err)
+	app.PHPPools = append(app.PHPPools, ddevapp.PHPPool{Version: nodeps.PHP81, Path: "/legacy"})
+	err = app.ValidateConfig()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "duplicate 'path: /legacy'")
+	app.PHPPools = []ddevapp.PHPPool{{Version: nodeps.PHP74, Path: "legacy/"}}
+	err = app.ValidateConfig()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid 'path: legacy/'")
+	app.PHPPools = []ddevapp.PHPPool{{Version: "4.4", Path: "/legacy"}}
+	err = app.ValidateConfig()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "unsupported 'version: 4.4'")
+	app.PHPPools = []ddevapp.PHPPool{{Version: app.PHPVersion, Path: "/legacy"}}
+	err = app.ValidateConfig()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "same as php_version")
+	app.PHPPools = []ddevapp.PHPPool{{Version: nodeps.PHP74, Path: "/legacy"}}
+	app.WebExtraDaemons = []ddevapp.WebExtraDaemon{{Name: "worker", Command: "php worker.php", Directory: "/var/www/html", PHPVersion: nodeps.PHP81}}
+	err = app.ValidateConfig()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "'name: worker' in web_extra_daemons")
+	app.WebExtraDaemons[0].PHPVersion = nodeps.PHP74
+	err = app.ValidateConfig()
+	require.NoError(t, err)
+	app.WebserverType = nodeps.WebserverFrankenPHP
+	app.PHPVersion = nodeps.FrankenPHPPHPVersion
+	err = app.ValidateConfig()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "php_pools, but they can only be used")
+	app.WebserverType = nodeps.WebserverDefault
+	app.PHPVersion = nodeps.PHPDefault
+	app.PHPPools = nil
+	// Without php_pools, a daemon can only use php_version
+	err = app.ValidateConfig()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "'name: worker' in web_extra_daemons")
+	app.WebExtraDaemons[0].PHPVersion = app.PHPVersion
+	err = app.ValidateConfig()
+	require.NoError(t, err)
+	app.WebExtraDaemons = nil
+
 	// PHP <= 7.3 can't authenticate against MySQL 9+, which removed the
 	// mysql_native_password plugin that those old PHP mysqlnd builds require.
 	appDatabase := app.Database
@@ -115,7 +115,7 @@ func updateCraftCMSDotEnv(app *DdevApp) (string, error) {
 }
 
 func craftCmsConfigOverrideAction(app *DdevApp) error {
-	app.Database = DatabaseDesc{nodeps.MySQL, nodeps.MySQL80}
+	app.Database = DatabaseDesc{Type: nodeps.MySQL, Version: nodeps.MySQL80}
 	return nil
 }
 
@@ -23,11 +23,13 @@ import (
 	"github.com/ddev/ddev/pkg/appimport"
 	"github.com/ddev/ddev/pkg/archive"
 	"github.com/ddev/ddev/pkg/config/types"
+	"github.com/ddev/ddev/pkg/dbgpproxy"
 	ddevImages "github.com/ddev/ddev/pkg/docker"
 	"github.com/ddev/ddev/pkg/dockerutil"
 	"github.com/ddev/ddev/pkg/exec"
 	"github.com/ddev/ddev/pkg/fileutil"
 	"github.com/ddev/ddev/pkg/globalconfig"
+	"github.com/ddev/ddev/pkg/idlemanager"
 	"github.com/ddev/ddev/pkg/netutil"
 	"github.com/ddev/ddev/pkg/nodeps"
 	"github.com/ddev/ddev/pkg/output"
@@ -64,11 +66,13 @@ const (
 )
 
 // DatabaseDefault is the default database/version
-var DatabaseDefault = DatabaseDesc{nodeps.MariaDB, nodeps.MariaDBDefaultVersion}
+var DatabaseDefault = DatabaseDesc{Type: nodeps.MariaDB, Version: nodeps.MariaDBDefaultVersion}
 
 type DatabaseDesc struct {
-	Type    string `yaml:"type"`
-	Version string `yaml:"version"`
+	Type       string            `yaml:"type"`
+	Version    string            `yaml:"version"`
+	Extensions []string          `yaml:"extensions,omitempty"`
+	Settings   map[string]string `yaml:"settings,omitempty"`
 }
 
 type WebExposedPort struct {
@@ -79,9 +83,10 @@ type WebExposedPort struct {
 }
 
 type WebExtraDaemon struct {
-	Name      string `yaml:"name"`
-	Command   string `yaml:"command"`
-	Directory string `yaml:"directory"`
+	Name       string `yaml:"name"`
+	Command    string `yaml:"command"`
+	Directory  string `yaml:"directory"`
+	PHPVersion string `yaml:"php_version,omitempty"`
 }
 
 // DdevApp is the struct that represents a DDEV app, mostly its config
@@ -93,11 +98,15 @@ type DdevApp struct {
 	Docroot                   string                `yaml:"docroot"`
 	PHPVersion                string                `yaml:"php_version"`
 	WebserverType             string                `yaml:"webserver_type"`
+	FrankenPHPWorker          string                `yaml:"frankenphp_worker,omitempty"`
+	PHPPools                  []PHPPool             `yaml:"php_pools,omitempty"`
 	WebImage                  string                `yaml:"webimage,omitempty"`
 	DBImage                   string                `yaml:"dbimage,omitempty"`
 	RouterHTTPPort            string                `yaml:"router_http_port,omitempty"`
 	RouterHTTPSPort           string                `yaml:"router_https_port,omitempty"`
 	XdebugEnabled             bool                  `yaml:"xdebug_enabled"`
+	XdebugMode                types.XdebugMode      `yaml:"xdebug_mode,omitempty"`
+	XdebugStartWithRequest    string                `yaml:"xdebug_start_with_request,omitempty"`
 	NoProjectMount            bool                  `yaml:"no_project_mount,omitempty"`
 	AdditionalHostnames       []string              `yaml:"additional_hostnames"`
 	AdditionalFQDNs           []string              `yaml:"additional_fqdns"`
@@ -144,6 +153,10 @@ type DdevApp struct {
 	WebExtraExposedPorts      []WebExposedPort      `yaml:"web_extra_exposed_ports,omitempty"`
 	WebExtraDaemons           []WebExtraDaemon      `yaml:"web_extra_daemons,omitempty"`
 	OverrideConfig            bool                  `yaml:"override_config,omitempty"`
+	NameFrom                  string                `yaml:"name_from,omitempty"`
+	WorktreeSeedDB            bool                  `yaml:"worktree_seed_db,omitempty"`
+	Bootstrap                 []BootstrapStep       `yaml:"bootstrap,omitempty"`
+	HealthChecks              []HealthCheck         `yaml:"health_checks,omitempty"`
 	DisableUploadDirsWarning  bool                  `yaml:"disable_upload_dirs_warning,omitempty"`
 	DdevVersionConstraint     string                `yaml:"ddev_version_constraint,omitempty"`
 	XHGuiHTTPSPort            string                `yaml:"xhgui_https_port,omitempty"`
@@ -152,6 +165,12 @@ type DdevApp struct {
 	XHProfMode                types.XHProfMode      `yaml:"xhprof_mode,omitempty"`
 	ComposeYaml               *composeTypes.Project `yaml:"-"`
 	NoCache                   bool                  `yaml:"-"`
+
+	// Services are the built-in optional services, like redis and opensearch
+	Services map[string]ServiceDesc `yaml:"services,omitempty"`
+
+	// nameFromSuffix is what name_from added to the configured name
+	nameFromSuffix string
 }
 
 // SkipHooks Global variable that's set from --skip-hooks global flag.
@@ -701,7 +720,7 @@ func (app *DdevApp) GetPhpVersion() string {
 	return v
 }
 
-// GetWebserverType returns the app's webserver type (nginx-fpm/apache-fpm/generic)
+// GetWebserverType returns the app's webserver type (nginx-fpm/apache-fpm/caddy-fpm/frankenphp/generic)
 func (app *DdevApp) GetWebserverType() string {
 	v := nodeps.WebserverDefault
 	if app.WebserverType != "" {
@@ -710,6 +729,16 @@ func (app *DdevApp) GetWebserverType() string {
 	return v
 }
 
+// GetFrankenPHPWorker returns the absolute in-container path of the FrankenPHP
+// worker script, or an empty string if worker mode is not configured.
+// frankenphp_worker is relative to the project root.
+func (app *DdevApp) GetFrankenPHPWorker() string {
+	if app.FrankenPHPWorker == "" {
+		return ""
+	}
+	return path.Join(app.GetAbsAppRoot(true), app.FrankenPHPWorker)
+}
+
 // GetPrimaryRouterHTTPPort returns app's primary router http port
 // It has to choose from (highest to lowest priority):
 // 1. Empty string if webserver type is generic and no web_extra_exposed_ports are defined
@@ -884,7 +913,9 @@ func (app *DdevApp) GetDBDumpCommand() string {
 }
 
 // ImportDB takes a source sql dump and imports it to an active site's database container.
-func (app *DdevApp) ImportDB(dumpFile string, extractPath string, progress bool, noDrop bool, targetDB string) error {
+func (app *DdevApp) ImportDB(dumpFile string, extractPath string, progress bool, noDrop bool, targetDB string) (err error) {
+	span := app.startSpan("app.import-db")
+	defer func() { span.End(err) }()
 	_ = app.DockerEnv()
 	if err := dockerutil.CheckAvailableSpace(); err != nil {
 		util.Warning("Warning: %v", err)
@@ -907,11 +938,14 @@ func (app *DdevApp) ImportDB(dumpFile string, extractPath string, progress bool,
 		_ = os.RemoveAll(dbPath)
 	}()
 
+	span.Phase("pre-import-db-hooks")
 	err = app.ProcessHooks("pre-import-db")
 	if err != nil {
 		return err
 	}
 
+	span.Phase("extract")
+
 	// If they don't provide an import path and we're not on a tty (piped in stuff)
 	// then prompt for path to db
 	if dumpFile == "" && isatty.IsTerminal(os.Stdin.Fd()) {
@@ -1014,6 +1048,7 @@ func (app *DdevApp) ImportDB(dumpFile string, extractPath string, progress bool,
 		}
 	}
 
+	span.Phase("import")
 	err = app.MutagenSyncFlush()
 	if err != nil {
 		return err
@@ -1112,6 +1147,7 @@ func (app *DdevApp) ImportDB(dumpFile string, extractPath string, progress bool,
 		return fmt.Errorf("failed to clean up %s after import: %v", dbPath, err)
 	}
 
+	span.Phase("post-import-db-hooks")
 	err = app.ProcessHooks("post-import-db")
 	if err != nil {
 		return err
@@ -1504,8 +1540,13 @@ func (app *DdevApp) composeBuild(args ...string) (string, error) {
 }
 
 // Start initiates docker-compose up
-func (app *DdevApp) Start() error {
-	var err error
+func (app *DdevApp) Start() (err error) {
+	span := app.startSpan("app.start")
+	defer func() { span.End(err) }()
+
+	if err = app.CheckPolicy(); err != nil {
+		return err
+	}
 
 	RunUpgradeCheck()
 
@@ -1521,6 +1562,11 @@ func (app *DdevApp) Start() error {
 	// not set.
 	app.ComposeYaml = nil
 
+	// The project is in use again, so the idle manager stops routing its
+	// requests and counts its idle time from now
+	idlemanager.ClearStopped(app.Name)
+	idlemanager.RecordActivity(app.Name)
+
 	// Set up ports to be replaced with ephemeral ports if needed
 	app.RouterHTTPPort = app.GetPrimaryRouterHTTPPort()
 	app.RouterHTTPSPort = app.GetPrimaryRouterHTTPSPort()
@@ -1547,11 +1593,14 @@ func (app *DdevApp) Start() error {
 	}
 	warnWSL2WindowsFilesystem(app)
 	warnWSL2NoneMode()
+	span.Phase("pre-start-hooks")
 	err = app.ProcessHooks("pre-start")
 	if err != nil {
 		return err
 	}
 
+	span.Phase("pull-images")
+
 	// WriteConfig .ddev-docker-compose-*.yaml
 	err = app.WriteDockerComposeYAML()
 	if err != nil {
@@ -1567,6 +1616,8 @@ func (app *DdevApp) Start() error {
 		util.Warning("Unable to pull Docker images: %v", pullErr)
 	}
 
+	span.Phase("configure")
+
 	// dbNeedsInitialization means the database volume has no database in it yet,
 	// so the db container will seed it from a base_db seed during this start.
 	dbNeedsInitialization := false
@@ -1631,6 +1682,10 @@ func (app *DdevApp) Start() error {
 		}
 	}
 
+	// A new worktree gets the database of the main worktree once its
+	// database volume is created
+	seedDB := app.needsWorktreeDBSeed()
+
 	volumesNeeded := []string{"ddev-global-cache"}
 	if globalconfig.DdevGlobalConfig.NoBindMounts {
 		volumesNeeded = append(volumesNeeded, app.Name+"-ddev-config")
@@ -1839,6 +1894,7 @@ func (app *DdevApp) Start() error {
 		}
 	}
 
+	span.Phase("build-images")
 	if output.JSONOutput {
 		output.UserOut.Printf("Building project images...")
 	} else {
@@ -1901,6 +1957,7 @@ func (app *DdevApp) Start() error {
 		}
 	}
 
+	span.Phase("compose-up")
 	util.Debug("Executing docker-compose -f %s up -d", app.DockerComposeFullRenderedYAMLPath())
 
 	upProject, upErr := dockerutil.LoadComposeProject([]string{app.DockerComposeFullRenderedYAMLPath()}, api.ProjectLoadOptions{
@@ -1909,6 +1966,9 @@ func (app *DdevApp) Start() error {
 	if upErr != nil {
 		return upErr
 	}
+	if upErr = resolveComposeSecrets(upProject); upErr != nil {
+		return upErr
+	}
 	upCtx, upSvc, upErr := dockerutil.NewComposeService()
 	if upErr != nil {
 		return upErr
@@ -1955,6 +2015,7 @@ func (app *DdevApp) Start() error {
 		}
 	}
 
+	span.Phase("mutagen-sync")
 	if app.IsMutagenEnabled() {
 		if cliErr := dockerutil.CheckDockerCLI(); cliErr != nil {
 			return fmt.Errorf("mutagen requires a working docker CLI to connect to containers, but: %v\nInstall docker CLI and ensure it is in PATH, then retry", cliErr)
@@ -2002,6 +2063,9 @@ func (app *DdevApp) Start() error {
 		} else {
 			util.Error("Mutagen sync completed with problems in %s.\nRun 'ddev utility mutagen-diagnose' for detailed diagnostics and fixes", dur)
 		}
+		if conflicts, err := app.MutagenConflicts(); err == nil && len(conflicts) > 0 {
+			util.Warning("Mutagen has %d sync conflicts, files changed both on the host and in the container.\nSee them with 'ddev mutagen conflicts' and resolve them with 'ddev mutagen resolve'", len(conflicts))
+		}
 		err = fileutil.TemplateStringToFile(`#ddev-generated`, nil, app.GetConfigPath("mutagen/.start-synced"))
 		if err != nil {
 			util.Warning("Could not create file %s: %v", app.GetConfigPath("mutagen/.start-synced"), err)
@@ -2048,6 +2112,7 @@ func (app *DdevApp) Start() error {
 		}
 	}
 
+	span.Phase("wait-containers")
 	// Wait for web/db containers to become healthy
 	dependers := []string{"web"}
 	if !app.IsDBOmitted() {
@@ -2075,6 +2140,12 @@ func (app *DdevApp) Start() error {
 		util.Debug(`mysql 8, php 5.6-7.3, set mysql_native_password`)
 	}
 
+	if waitErr == nil && len(app.Database.Extensions) > 0 {
+		if err = app.CreatePostgresExtensions(); err != nil {
+			util.Warning("Unable to create database.extensions: %v", err)
+		}
+	}
+
 	if globalconfig.DdevVerbose {
 		logOut, logErr := app.CaptureLogs("web", true, "200")
 		if logErr != nil {
@@ -2088,6 +2159,12 @@ func (app *DdevApp) Start() error {
 		util.Failed("Failed waiting for web/db containers to become ready: %v", waitErr)
 	}
 
+	if seedDB {
+		if err = app.seedDBFromMainWorktree(); err != nil {
+			util.Warning("Unable to seed the database from the main worktree: %v", err)
+		}
+	}
+
 	// WebExtraDaemons have to be started after Mutagen sync is done, because so often
 	// they depend on code being synced into the container/volume
 	if len(app.WebExtraDaemons) > 0 {
@@ -2100,6 +2177,7 @@ func (app *DdevApp) Start() error {
 		}
 	}
 
+	span.Phase("router")
 	// Start the router in the background; it's independent of the steps below,
 	// and waiting for it to become ready is the slowest part of startup.
 	var routerWg sync.WaitGroup
@@ -2110,6 +2188,18 @@ func (app *DdevApp) Start() error {
 		})
 	}
 
+	if globalconfig.DdevGlobalConfig.XdebugProxy {
+		if err = dbgpproxy.Start(); err != nil {
+			util.Warning("Failed to start the Xdebug proxy: %v", err)
+		}
+	}
+
+	if idlemanager.IsEnabled() {
+		if err = idlemanager.Start(); err != nil {
+			util.Warning("Failed to start the idle manager: %v", err)
+		}
+	}
+
 	err = PopulateGlobalCustomCommandFiles()
 	if err != nil {
 		util.Warning("Failed to populate global custom command files: %v", err)
@@ -2166,11 +2256,17 @@ func (app *DdevApp) Start() error {
 		return err
 	}
 
+	span.Phase("post-start-hooks")
 	err = app.ProcessHooks("post-start")
 	if err != nil {
 		return err
 	}
 
+	span.Phase("health-checks")
+	if err = app.checkHealthAfterStart(); err != nil {
+		return err
+	}
+
 	if logStderr != "" {
 		util.Warning(`%s
 Some components of the project %s were not installed properly.
@@ -2221,6 +2317,10 @@ func (app *DdevApp) StartOptionalProfiles(profiles []string) error {
 		util.Warning("Failed to start optional compose profiles '%s': %v", profiles, err)
 		return err
 	}
+	if err = resolveComposeSecrets(upProject); err != nil {
+		util.Warning("Failed to start optional compose profiles '%s': %v", profiles, err)
+		return err
+	}
 	upCtx, upSvc, err := dockerutil.NewComposeService()
 	if err != nil {
 		util.Warning("Failed to start optional compose profiles '%s': %v", profiles, err)
@@ -2406,9 +2506,16 @@ func (app *DdevApp) GetMaxContainerWaitTime() int {
 func (app *DdevApp) CheckExistingAppInApproot() error {
 	pList := globalconfig.GetGlobalProjectList()
 	for name, v := range pList {
-		if app.AppRoot == v.AppRoot && name != app.Name {
-			return fmt.Errorf(`this project root '%s' already contains a project named '%s'. You may want to remove the existing project with "ddev stop --unlist %s"`, v.AppRoot, name, name)
+		if app.AppRoot != v.AppRoot || name == app.Name {
+			continue
 		}
+		if app.isNameFromSibling(name) {
+			if err := app.removeNameFromSibling(name); err != nil {
+				return err
+			}
+			continue
+		}
+		return fmt.Errorf(`this project root '%s' already contains a project named '%s'. You may want to remove the existing project with "ddev stop --unlist %s"`, v.AppRoot, name, name)
 	}
 	return nil
 }
@@ -2432,6 +2539,10 @@ func (app *DdevApp) GenerateWebserverConfig() error {
 		"README.nginx_full.txt":         app.GetConfigPath(filepath.Join("nginx_full", "README.nginx_full.txt")),
 		"README.apache.txt":             app.GetConfigPath(filepath.Join("apache", "README.apache.txt")),
 		"apache_second_docroot_example": app.GetConfigPath(filepath.Join("apache", "seconddocroot.conf.example")),
+		"caddy":                         app.GetConfigPath(filepath.Join("caddy", "Caddyfile")),
+		"README.caddy.txt":              app.GetConfigPath(filepath.Join("caddy", "README.caddy.txt")),
+		"frankenphp":                    app.GetConfigPath(filepath.Join("frankenphp", "Caddyfile")),
+		"README.frankenphp.txt":         app.GetConfigPath(filepath.Join("frankenphp", "README.frankenphp.txt")),
 	}
 	for t, configPath := range items {
 		err := os.MkdirAll(filepath.Dir(configPath), 0755)
@@ -2460,12 +2571,12 @@ func (app *DdevApp) GenerateWebserverConfig() error {
 		}
 		content := string(c)
 		docroot := app.GetAbsDocroot(true)
-		err = fileutil.TemplateStringToFile(content, map[string]any{"Docroot": docroot}, configPath)
+		err = fileutil.TemplateStringToFile(content, map[string]any{"Docroot": docroot, "FrankenPHPWorker": app.GetFrankenPHPWorker(), "PHPPools": app.PHPPools}, configPath)
 		if err != nil {
 			return err
 		}
 	}
-	return nil
+	return app.GeneratePHPPoolsConfig()
 }
 
 func (app *DdevApp) GeneratePostgresConfig() error {
@@ -2754,16 +2865,7 @@ func (app *DdevApp) Logs(service string, follow bool, timestamps bool, tailLines
 		return err
 	}
 
-	var c *container.Summary
-	// Let people access ddev-router and ddev-ssh-agent logs as well.
-	if service == "ddev-router" || service == "ddev-ssh-agent" {
-		c, err = dockerutil.FindContainerByLabels(map[string]string{
-			"com.docker.compose.service": service,
-			"com.docker.compose.oneoff":  "False",
-		})
-	} else {
-		c, err = app.FindContainerByType(service)
-	}
+	c, err := app.findLogContainer(service)
 	if err != nil {
 		return err
 	}
@@ -2806,16 +2908,7 @@ func (app *DdevApp) CaptureLogs(service string, timestamps bool, tailLines strin
 		return "", err
 	}
 
-	var c *container.Summary
-	// Let people access ddev-router and ddev-ssh-agent logs as well.
-	if service == "ddev-router" || service == "ddev-ssh-agent" {
-		c, err = dockerutil.FindContainerByLabels(map[string]string{
-			"com.docker.compose.service": service,
-			"com.docker.compose.oneoff":  "False",
-		})
-	} else {
-		c, err = app.FindContainerByType(service)
-	}
+	c, err := app.findLogContainer(service)
 	if err != nil {
 		return "", err
 	}
@@ -2984,6 +3077,8 @@ func (app *DdevApp) DockerEnv() map[string]string {
 		"DDEV_ROUTER_HTTP_PORT":          app.GetPrimaryRouterHTTPPort(),
 		"DDEV_ROUTER_HTTPS_PORT":         app.GetPrimaryRouterHTTPSPort(),
 		"DDEV_XDEBUG_ENABLED":            strconv.FormatBool(app.XdebugEnabled),
+		"DDEV_XDEBUG_MODE":               app.GetXdebugMode(),
+		"DDEV_XDEBUG_START_WITH_REQUEST": app.GetXdebugStartWithRequest(),
 		"DDEV_XHPROF_MODE":               app.GetXHProfMode(),
 		"DDEV_PRIMARY_URL":               primaryURL,
 		"DDEV_PRIMARY_URL_PORT":          primaryURLPort,
@@ -3278,6 +3373,10 @@ func (app *DdevApp) Snapshot(snapshotName string, uncompressed bool) (string, er
 	if err != nil {
 		return "", err
 	}
+	err = app.snapshotBuiltinServices(snapshotName)
+	if err != nil {
+		return "", err
+	}
 	err = app.ProcessHooks("post-snapshot")
 	if err != nil {
 		return snapshotFile, fmt.Errorf("failed to process post-snapshot hooks: %v", err)
@@ -3361,17 +3460,21 @@ func fullDBFromVersion(v string) string {
 }
 
 // Stop stops and Removes the Docker containers for the project in current directory.
-func (app *DdevApp) Stop(removeData bool, createSnapshot bool) error {
+func (app *DdevApp) Stop(removeData bool, createSnapshot bool) (err error) {
+	span := app.startSpan("app.stop")
+	span.SetAttribute("ddev.remove_data", strconv.FormatBool(removeData))
+	defer func() { span.End(err) }()
 	_ = app.DockerEnv()
-	var err error
 
 	clear(EphemeralRouterPortsAssigned)
 	if app.Name == "" {
 		return fmt.Errorf("invalid app.Name provided to app.Stop(), app=%v", app)
 	}
+	idlemanager.ClearStopped(app.Name)
 
 	status, _ := app.SiteStatus()
 	if status != SiteStopped {
+		span.Phase("pre-stop-hooks")
 		err = app.ProcessHooks("pre-stop")
 		if err != nil {
 			return fmt.Errorf("failed to process pre-stop hooks: %v", err)
@@ -3379,6 +3482,7 @@ func (app *DdevApp) Stop(removeData bool, createSnapshot bool) error {
 	}
 
 	if createSnapshot {
+		span.Phase("snapshot")
 		if status != SiteRunning {
 			util.Warning("Must start non-running project to do database snapshot")
 			err = app.Start()
@@ -3393,6 +3497,7 @@ func (app *DdevApp) Stop(removeData bool, createSnapshot bool) error {
 		}
 	}
 
+	span.Phase("cleanup")
 	if app.IsMutagenEnabled() {
 		err = SyncAndPauseMutagenSession(app)
 		if err != nil {
@@ -3437,12 +3542,17 @@ func (app *DdevApp) Stop(removeData bool, createSnapshot bool) error {
 
 	// Remove data/database/projectInfo/hosts entry if we need to.
 	if removeData {
+		span.Phase("remove-data")
 		if app.IsMutagenEnabled() {
 			err = TerminateMutagenSync(app)
 			if err != nil {
 				util.Warning("Unable to terminate Mutagen session %s: %v", MutagenSyncName(app.Name), err)
 			}
 		}
+		// The bootstrap steps run again with the next 'ddev up'
+		if err = app.ResetBootstrapState(); err != nil {
+			util.Warning("Unable to remove %s: %v", app.GetBootstrapStatePath(), err)
+		}
 		// Remove .ddev/settings if it exists
 		if fileutil.FileExists(app.GetConfigPath("settings")) {
 			err = os.RemoveAll(app.GetConfigPath("settings"))
@@ -3475,6 +3585,7 @@ func (app *DdevApp) Stop(removeData bool, createSnapshot bool) error {
 	}
 
 	if status != SiteStopped {
+		span.Phase("post-stop-hooks")
 		err = app.ProcessHooks("post-stop")
 		if err != nil {
 			return fmt.Errorf("failed to process post-stop hooks: %v", err)
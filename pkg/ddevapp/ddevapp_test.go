@@ -3374,6 +3374,32 @@ func TestDdevLogs(t *testing.T) {
 	assert.NoError(err)
 	assert.Contains(out, "MySQL init process done. Ready for start up.")
 
+	// Several services are interleaved by timestamp
+	var entries []ddevapp.LogEntry
+	err = app.StreamLogs(ddevapp.LogsOptions{Services: []string{"web", "db"}}, func(entry ddevapp.LogEntry) {
+		entries = append(entries, entry)
+	})
+	assert.NoError(err)
+	services := map[string]bool{}
+	for i, entry := range entries {
+		services[entry.Service] = true
+		if i > 0 {
+			assert.False(entry.Timestamp.Before(entries[i-1].Timestamp), "log entries should be sorted by timestamp")
+		}
+	}
+	assert.Equal(map[string]bool{"web": true, "db": true}, services)
+
+	entries = nil
+	err = app.StreamLogs(ddevapp.LogsOptions{Services: []string{"web", "db"}, Grep: regexp.MustCompile("Server started")}, func(entry ddevapp.LogEntry) {
+		entries = append(entries, entry)
+	})
+	assert.NoError(err)
+	require.NotEmpty(t, entries)
+	for _, entry := range entries {
+		assert.Equal("web", entry.Service)
+		assert.Contains(entry.Message, "Server started")
+	}
+
 	// Test that we can get logs when project is stopped also
 	err = app.Pause()
 	assert.NoError(err)
@@ -37,6 +37,8 @@ type DrupalSettings struct {
 	DockerIP         string
 	DBPublishedPort  int
 	HasDBContainer   bool
+	RedisHost        string
+	RedisPort        int
 }
 
 // NewDrupalSettings produces a DrupalSettings object with default.
@@ -61,6 +63,10 @@ func NewDrupalSettings(app *DdevApp) *DrupalSettings {
 		DBPublishedPort:  dbPublishedPort,
 		HasDBContainer:   !app.IsDBOmitted(),
 	}
+	if name := app.GetCacheService(); name != "" {
+		settings.RedisHost = name
+		settings.RedisPort = builtinServices[name].port
+	}
 	if app.Type == "drupal6" {
 		settings.DatabaseDriver = "mysqli"
 	}
@@ -462,7 +468,7 @@ func drupalConfigOverrideAction(app *DdevApp) error {
 	if !app.IsDBOmitted() {
 		if dbType, err := app.GetExistingDBType(); err == nil && dbType == "" {
 			app.Database = DatabaseDefault
-		} else if app.Database.Type == DatabaseDefault.Type && app.Database != DatabaseDefault && drupalVersion >= 8 {
+		} else if app.Database.Type == DatabaseDefault.Type && app.Database.Version != DatabaseDefault.Version && drupalVersion >= 8 {
 			defaultType := DatabaseDefault.Type + ":" + DatabaseDefault.Version
 			util.Warning("Default database type is %s, but the current actual database type is %s, you may want to migrate with 'ddev utility migrate-database %s'.", defaultType, dbType, defaultType)
 		}
@@ -23,6 +23,13 @@ $databases['default']['default']['driver'] = $driver;
 
 {{ end -}}
 $settings['hash_salt'] = '{{ $config.HashSalt }}';
+{{- if $config.RedisHost }}
+
+// Use the built-in redis service when the Redis module is enabled.
+$settings['redis.connection']['interface'] = 'PhpRedis';
+$settings['redis.connection']['host'] = '{{ $config.RedisHost }}';
+$settings['redis.connection']['port'] = {{ $config.RedisPort }};
+{{- end }}
 
 // Recommended setting for Drupal 10 only
 $settings['state_cache'] = TRUE;
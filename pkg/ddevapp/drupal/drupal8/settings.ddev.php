@@ -30,6 +30,13 @@ $databases['default']['default']['driver'] = $driver;
 
 {{ end -}}
 $settings['hash_salt'] = '{{ $config.HashSalt }}';
+{{- if $config.RedisHost }}
+
+// Use the built-in redis service when the Redis module is enabled.
+$settings['redis.connection']['interface'] = 'PhpRedis';
+$settings['redis.connection']['host'] = '{{ $config.RedisHost }}';
+$settings['redis.connection']['port'] = {{ $config.RedisPort }};
+{{- end }}
 
 // This will prevent Drupal from setting read-only permissions on sites/default.
 $settings['skip_permissions_hardening'] = TRUE;
@@ -0,0 +1,229 @@
+package ddevapp
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/ddev/ddev/pkg/globalconfig"
+	"github.com/ddev/ddev/pkg/util"
+)
+
+// Defaults of the health_checks entries. The timeout of 'ddev health' is
+// longer than the one at the end of 'ddev start', which shouldn't wait long
+// for a broken site.
+const (
+	HealthCheckDefaultStatus       = http.StatusOK
+	HealthCheckDefaultTimeout      = 30
+	HealthCheckDefaultStartTimeout = 5
+)
+
+// healthCheckSnippetLength is how much of the body is shown for a failing check
+const healthCheckSnippetLength = 300
+
+// HealthCheck is an entry of health_checks in config.yaml, an HTTP request
+// to the project that must succeed after it starts
+type HealthCheck struct {
+	// Path is the path of the URL, relative to the primary URL of the project
+	Path string `yaml:"path"`
+	// Status is the expected status code, 200 by default. Redirects aren't followed.
+	Status int `yaml:"status,omitempty"`
+	// BodyRegex is a regular expression the response body must match
+	BodyRegex string `yaml:"body_regex,omitempty"`
+	// Timeout is how long to retry the check until it passes, in seconds
+	Timeout int `yaml:"timeout,omitempty"`
+}
+
+// HealthCheckResult is the outcome of a HealthCheck
+type HealthCheckResult struct {
+	Check HealthCheck
+	URL   string
+	// Status is the status code of the last response, 0 if there was none
+	Status int
+	// Snippet is the start of the body of the last response
+	Snippet string
+	// Err is why the check failed, nil if it passed
+	Err error
+}
+
+// Validate checks the values of the health check
+func (c HealthCheck) Validate() error {
+	if !strings.HasPrefix(c.Path, "/") {
+		return fmt.Errorf("path '%s' must start with /", c.Path)
+	}
+	if c.Status != 0 && (c.Status < 100 || c.Status > 599) {
+		return fmt.Errorf("status %d isn't an HTTP status code", c.Status)
+	}
+	if c.Timeout < 0 {
+		return errors.New("timeout can't be negative")
+	}
+	if _, err := regexp.Compile(c.BodyRegex); err != nil {
+		return fmt.Errorf("invalid body_regex: %v", err)
+	}
+	return nil
+}
+
+// GetStatus returns the expected status code
+func (c HealthCheck) GetStatus() int {
+	if c.Status == 0 {
+		return HealthCheckDefaultStatus
+	}
+	return c.Status
+}
+
+// GetTimeout returns how long to retry the check until it passes,
+// defaultTimeout if the check has no timeout
+func (c HealthCheck) GetTimeout(defaultTimeout time.Duration) time.Duration {
+	if c.Timeout == 0 {
+		return defaultTimeout
+	}
+	return time.Duration(c.Timeout) * time.Second
+}
+
+// NewHealthCheckClient returns the HTTP client of the health checks, which
+// doesn't follow redirects and trusts rootCAs, or the system CAs if nil
+func NewHealthCheckClient(rootCAs *x509.CertPool) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{RootCAs: rootCAs}
+	return &http.Client{
+		CheckRedirect: func(_ *http.Request, _ []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+		Timeout:   10 * time.Second,
+		Transport: transport,
+	}
+}
+
+// Run requests baseURL with the path of the check using client, retrying
+// until the response is as expected or the timeout expires, defaultTimeout
+// if the check has none
+func (c HealthCheck) Run(client *http.Client, baseURL string, defaultTimeout time.Duration) HealthCheckResult {
+	result := HealthCheckResult{Check: c, URL: strings.TrimSuffix(baseURL, "/") + c.Path}
+	bodyRegex, err := regexp.Compile(c.BodyRegex)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	deadline := time.Now().Add(c.GetTimeout(defaultTimeout))
+	for {
+		result.Status, result.Snippet, result.Err = 0, "", nil
+		resp, err := client.Get(result.URL)
+		if err == nil {
+			body, readErr := io.ReadAll(resp.Body)
+			_ = resp.Body.Close()
+			result.Status = resp.StatusCode
+			result.Snippet = healthCheckSnippet(string(body))
+			switch {
+			case readErr != nil:
+				err = fmt.Errorf("unable to read the response: %v", readErr)
+			case resp.StatusCode != c.GetStatus():
+				err = fmt.Errorf("status code was %d, not %d", resp.StatusCode, c.GetStatus())
+			case !bodyRegex.Match(body):
+				err = fmt.Errorf("body doesn't match '%s'", c.BodyRegex)
+			}
+		}
+		result.Err = err
+		if err == nil || time.Now().After(deadline) {
+			return result
+		}
+		time.Sleep(time.Second)
+	}
+}
+
+// healthCheckSnippet returns the start of body on one line
+func healthCheckSnippet(body string) string {
+	snippet := []rune(strings.Join(strings.Fields(body), " "))
+	if len(snippet) > healthCheckSnippetLength {
+		return string(snippet[:healthCheckSnippetLength]) + "..."
+	}
+	return string(snippet)
+}
+
+// RunHealthChecks runs the health_checks of the project, in order,
+// retrying each for defaultTimeout unless it has its own timeout
+func (app *DdevApp) RunHealthChecks(defaultTimeout time.Duration) []HealthCheckResult {
+	baseURL, rootCAs := app.healthCheckBase()
+	client := NewHealthCheckClient(rootCAs)
+	results := make([]HealthCheckResult, 0, len(app.HealthChecks))
+	for _, c := range app.HealthChecks {
+		results = append(results, c.Run(client, baseURL, defaultTimeout))
+	}
+	return results
+}
+
+// healthCheckBase returns the URL the health checks are relative to, and the
+// CAs to trust for it: the primary URL with the mkcert CA, or the HTTP URL
+// if the mkcert CA can't be read, since its certificates can't be verified
+func (app *DdevApp) healthCheckBase() (string, *x509.CertPool) {
+	baseURL := app.GetPrimaryURL()
+	if !strings.HasPrefix(baseURL, "https://") || globalconfig.DdevGlobalConfig.UseLetsEncrypt {
+		return baseURL, nil
+	}
+	rootCAs, err := loadMkcertRootCAs(globalconfig.GetCAROOT())
+	if err != nil {
+		util.Debug("Using the HTTP URL for health checks: %v", err)
+		return app.GetHTTPURL(), nil
+	}
+	return baseURL, rootCAs
+}
+
+// loadMkcertRootCAs returns the system CAs with the mkcert CA in caRoot
+func loadMkcertRootCAs(caRoot string) (*x509.CertPool, error) {
+	if caRoot == "" {
+		return nil, errors.New("mkcert CAROOT is unknown")
+	}
+	pem, err := os.ReadFile(filepath.Join(caRoot, "rootCA.pem"))
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the mkcert CA: %v", err)
+	}
+	rootCAs, err := x509.SystemCertPool()
+	if err != nil {
+		rootCAs = x509.NewCertPool()
+	}
+	if !rootCAs.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificate in %s", filepath.Join(caRoot, "rootCA.pem"))
+	}
+	return rootCAs, nil
+}
+
+// FormatHealthCheckFailures describes the failed health checks with a
+// snippet of their response, or returns an empty string if all passed
+func FormatHealthCheckFailures(results []HealthCheckResult) string {
+	var failures []string
+	for _, r := range results {
+		if r.Err == nil {
+			continue
+		}
+		failure := fmt.Sprintf("%s: %v", r.URL, r.Err)
+		if r.Snippet != "" {
+			failure += fmt.Sprintf("\n  Response: %s", r.Snippet)
+		}
+		failures = append(failures, failure)
+	}
+	return strings.Join(failures, "\n")
+}
+
+// checkHealthAfterStart runs the health checks at the end of Start, their
+// failure fails the start only with fail_on_hook_fail
+func (app *DdevApp) checkHealthAfterStart() error {
+	if len(app.HealthChecks) == 0 {
+		return nil
+	}
+	failures := FormatHealthCheckFailures(app.RunHealthChecks(HealthCheckDefaultStartTimeout * time.Second))
+	if failures == "" {
+		return nil
+	}
+	if app.FailOnHookFail || app.FailOnHookFailGlobal {
+		return fmt.Errorf("health checks of %s failed:\n%s", app.Name, failures)
+	}
+	util.Warning("Project %s started, but health checks failed:\n%s", app.Name, failures)
+	return nil
+}
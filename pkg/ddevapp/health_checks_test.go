@@ -0,0 +1,95 @@
+package ddevapp
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestHealthCheckValidate checks the validation of health_checks entries
+func TestHealthCheckValidate(t *testing.T) {
+	require.NoError(t, HealthCheck{Path: "/", Status: 302, BodyRegex: "ok$", Timeout: 5}.Validate())
+	require.ErrorContains(t, HealthCheck{Path: "user/login"}.Validate(), "must start with /")
+	require.ErrorContains(t, HealthCheck{Path: "/", Status: 42}.Validate(), "isn't an HTTP status code")
+	require.ErrorContains(t, HealthCheck{Path: "/", BodyRegex: "("}.Validate(), "invalid body_regex")
+	require.ErrorContains(t, HealthCheck{Path: "/", Timeout: -1}.Validate(), "negative")
+}
+
+// TestHealthCheckRun checks the status code and body assertions
+func TestHealthCheckRun(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			_, _ = w.Write([]byte("<html><title>Welcome</title></html>"))
+		case "/admin":
+			http.Redirect(w, r, "/login", http.StatusFound)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte("Fatal error:\n  " + strings.Repeat("x", 400)))
+		}
+	}))
+	defer server.Close()
+
+	client := NewHealthCheckClient(nil)
+	result := HealthCheck{Path: "/", BodyRegex: "<title>Welcome"}.Run(client, server.URL+"/", time.Second)
+	require.NoError(t, result.Err)
+	require.Equal(t, server.URL+"/", result.URL)
+	require.Equal(t, http.StatusOK, result.Status)
+
+	result = HealthCheck{Path: "/admin", Status: http.StatusFound}.Run(client, server.URL, time.Second)
+	require.NoError(t, result.Err)
+
+	result = HealthCheck{Path: "/", BodyRegex: "Goodbye", Timeout: 1}.Run(client, server.URL, time.Minute)
+	require.ErrorContains(t, result.Err, "body doesn't match")
+
+	result = HealthCheck{Path: "/broken"}.Run(client, server.URL, time.Second)
+	require.ErrorContains(t, result.Err, "status code was 500, not 200")
+	require.True(t, strings.HasPrefix(result.Snippet, "Fatal error: xxx"))
+	require.Len(t, result.Snippet, healthCheckSnippetLength+3)
+
+	failures := FormatHealthCheckFailures([]HealthCheckResult{result, {URL: server.URL + "/"}})
+	require.Contains(t, failures, server.URL+"/broken: status code was 500")
+	require.NotContains(t, failures, server.URL+"/:")
+}
+
+// TestHealthCheckTLS checks that the health checks trust the mkcert CA
+func TestHealthCheckTLS(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	result := HealthCheck{Path: "/"}.Run(NewHealthCheckClient(nil), server.URL, time.Second)
+	require.ErrorContains(t, result.Err, "certificate")
+
+	// The test server's certificate stands in for the mkcert CA
+	caRoot := t.TempDir()
+	_, err := loadMkcertRootCAs(caRoot)
+	require.ErrorContains(t, err, "unable to read the mkcert CA")
+	_, err = loadMkcertRootCAs("")
+	require.Error(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(caRoot, "rootCA.pem"), pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0644))
+	rootCAs, err := loadMkcertRootCAs(caRoot)
+	require.NoError(t, err)
+
+	result = HealthCheck{Path: "/", BodyRegex: "^ok$"}.Run(NewHealthCheckClient(rootCAs), server.URL, time.Second)
+	require.NoError(t, result.Err)
+	require.Equal(t, http.StatusOK, result.Status)
+
+	require.NoError(t, os.WriteFile(filepath.Join(caRoot, "rootCA.pem"), []byte("not a certificate"), 0644))
+	_, err = loadMkcertRootCAs(caRoot)
+	require.ErrorContains(t, err, "no certificate")
+}
+
+// TestHealthCheckGetTimeout checks the default and configured timeouts
+func TestHealthCheckGetTimeout(t *testing.T) {
+	require.Equal(t, HealthCheckDefaultStartTimeout*time.Second, HealthCheck{Path: "/"}.GetTimeout(HealthCheckDefaultStartTimeout*time.Second))
+	require.Equal(t, 60*time.Second, HealthCheck{Path: "/", Timeout: 60}.GetTimeout(HealthCheckDefaultStartTimeout*time.Second))
+}
@@ -0,0 +1,220 @@
+package ddevapp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ddev/ddev/pkg/globalconfig"
+	"github.com/ddev/ddev/pkg/idlemanager"
+)
+
+// idleManager stops idle projects and starts the projects it stopped when
+// they're requested. Projects are started and stopped one at a time.
+type idleManager struct {
+	logf func(format string, a ...any)
+
+	// mu serializes starting and stopping projects
+	mu sync.Mutex
+	// starting holds the projects being started on demand
+	starting sync.Map
+	// requests is the last count of requests of each router
+	requests map[string]float64
+}
+
+// RunIdleManager checks for idle projects every idlemanager.CheckInterval
+// and answers the requests for the projects it stopped on listener, until
+// ctx is done
+func RunIdleManager(ctx context.Context, listener net.Listener, logf func(format string, a ...any)) error {
+	m := &idleManager{logf: logf}
+	server := &http.Server{Handler: m, ReadHeaderTimeout: 10 * time.Second}
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- server.Serve(listener)
+	}()
+
+	ticker := time.NewTicker(idlemanager.CheckInterval)
+	defer ticker.Stop()
+	for {
+		m.checkIdleProjects()
+		select {
+		case <-ctx.Done():
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			_ = server.Shutdown(shutdownCtx)
+			return nil
+		case err := <-serveErr:
+			if errors.Is(err, http.ErrServerClosed) {
+				return nil
+			}
+			return err
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkIdleProjects records the HTTP requests of the running projects since
+// the last check and stops the projects that have been idle for too long
+func (m *idleManager) checkIdleProjects() {
+	// The configuration and projects may have changed since the last check
+	if err := globalconfig.ReadGlobalConfig(); err != nil {
+		m.logf("Unable to read the global configuration: %v", err)
+	}
+	if err := globalconfig.ReadProjectList(); err != nil {
+		m.logf("Unable to read the project list: %v", err)
+	}
+	apps, err := GetProjects(true)
+	if err != nil {
+		m.logf("Unable to list the running projects: %v", err)
+		return
+	}
+	names := make([]string, 0, len(apps))
+	for _, app := range apps {
+		names = append(names, app.Name)
+	}
+
+	requests, err := idlemanager.RouterRequests(globalconfig.DdevGlobalConfig.TraefikMonitorPort)
+	if err != nil {
+		// The router may not be running, all projects may be stopped
+		if len(apps) > 0 {
+			m.logf("Unable to read the requests handled by the router: %v", err)
+		}
+	} else {
+		// The counters before the first check are activity of unknown age
+		if m.requests != nil {
+			for router, count := range requests {
+				if previous, ok := m.requests[router]; ok && count == previous {
+					continue
+				}
+				if name := idlemanager.ProjectForRouter(router, names); name != "" {
+					idlemanager.RecordActivity(name)
+				}
+			}
+		}
+		m.requests = requests
+	}
+
+	now := time.Now()
+	for _, app := range apps {
+		last, ok := idlemanager.LastActivity(app.Name)
+		if !ok {
+			// Running since before the idle manager was enabled
+			idlemanager.RecordActivity(app.Name)
+			continue
+		}
+		if !idlemanager.IsIdle(app.Name, last, now) {
+			continue
+		}
+		if _, starting := m.starting.Load(app.Name); starting {
+			continue
+		}
+		m.stopIdleProject(app, now.Sub(last))
+	}
+}
+
+// stopIdleProject stops a project, after taking a snapshot with
+// idle_snapshot, and routes its requests to the idle manager
+func (m *idleManager) stopIdleProject(app *DdevApp, idle time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.logf("Stopping %s, idle for %s", app.Name, idle.Round(time.Minute))
+	if globalconfig.DdevGlobalConfig.IdleSnapshot {
+		name, err := app.Snapshot(fmt.Sprintf("%s_idle_%s", app.Name, time.Now().Format("20060102150405")), false)
+		if err != nil {
+			// Keep the project running rather than risk the database
+			m.logf("Not stopping %s, the snapshot failed: %v", app.Name, err)
+			return
+		}
+		m.logf("Created snapshot %s of %s", name, app.Name)
+	}
+	if err := app.Stop(false, false); err != nil {
+		m.logf("Failed to stop %s: %v", app.Name, err)
+		return
+	}
+	if err := idlemanager.MarkStopped(app.Name); err != nil {
+		m.logf("Unable to record that %s was stopped: %v", app.Name, err)
+		return
+	}
+	if err := PushGlobalTraefikConfig(GetActiveProjects()); err != nil {
+		m.logf("Unable to route the requests for %s to the idle manager: %v", app.Name, err)
+	}
+	m.logf("Stopped %s", app.Name)
+}
+
+// ServeHTTP answers the requests for the projects stopped by the idle
+// manager with a page that reloads until the project has started
+func (m *idleManager) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == idlemanager.PingPath {
+		_, _ = fmt.Fprintln(w, "ok")
+		return
+	}
+
+	host := r.Host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	app := idleStoppedApp(host)
+	if app == nil {
+		http.Error(w, fmt.Sprintf("No project stopped by the DDEV idle manager has the hostname %s", host), http.StatusNotFound)
+		return
+	}
+
+	if _, alreadyStarting := m.starting.LoadOrStore(app.Name, true); !alreadyStarting {
+		go m.startProject(app)
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Retry-After", "5")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusServiceUnavailable)
+	name := html.EscapeString(app.Name)
+	_, _ = fmt.Fprintf(w, `<!DOCTYPE html>
+<html><head><meta charset="utf-8"><meta http-equiv="refresh" content="5"><title>Starting %[1]s</title></head>
+<body style="font-family: sans-serif; text-align: center; margin-top: 20vh">
+<h1>Starting %[1]s&hellip;</h1>
+<p>The DDEV idle manager stopped this project after it wasn't used for a while. This page reloads until it's running.</p>
+</body></html>
+`, name)
+}
+
+// startProject starts a project requested after the idle manager stopped it
+func (m *idleManager) startProject(app *DdevApp) {
+	defer m.starting.Delete(app.Name)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.logf("Starting %s, it was requested", app.Name)
+	if err := app.Start(); err != nil {
+		m.logf("Failed to start %s: %v", app.Name, err)
+		return
+	}
+	m.logf("Started %s", app.Name)
+}
+
+// idleStoppedApp returns the project stopped by the idle manager with the
+// hostname, or nil
+func idleStoppedApp(host string) *DdevApp {
+	for _, name := range idlemanager.StoppedProjects() {
+		p := globalconfig.GetProject(name)
+		if p == nil {
+			continue
+		}
+		app, err := NewApp(p.AppRoot, true)
+		if err != nil {
+			continue
+		}
+		for _, h := range app.GetHostnames() {
+			if strings.EqualFold(h, host) {
+				return app
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,23 @@
+package ddevapp
+
+import (
+	"github.com/ddev/ddev/pkg/instrumentation"
+)
+
+// startSpan starts an instrumentation span for a project operation like
+// app.start, with attributes describing the project. The project is
+// identified by its ProtectedID() rather than its name.
+func (app *DdevApp) startSpan(name string) *instrumentation.Span {
+	if !instrumentation.Enabled() {
+		return nil
+	}
+	instrumentation.SetRootAttribute("ddev.project.type", app.Type)
+	return instrumentation.StartSpan(name, map[string]string{
+		"ddev.project.id":               app.ProtectedID(),
+		"ddev.project.type":             app.Type,
+		"ddev.project.php_version":      app.PHPVersion,
+		"ddev.project.webserver_type":   app.WebserverType,
+		"ddev.project.database":         app.Database.Type + ":" + app.Database.Version,
+		"ddev.project.performance_mode": string(app.GetPerformanceMode()),
+	})
+}
@@ -2,6 +2,7 @@ package ddevapp
 
 import (
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
 	"slices"
@@ -73,6 +74,8 @@ func laravelPostStartAction(app *DdevApp) error {
 		envMap["DB_PASSWORD"] = "db"
 		envMap["DB_CONNECTION"] = dbConnection
 	}
+	// Point Laravel's redis connection at the built-in redis or valkey service
+	maps.Copy(envMap, app.getBuiltinServiceEnv("REDIS_HOST", "REDIS_PORT", "", ""))
 	err = WriteProjectEnvFile(envFilePath, envMap, envText)
 	if err != nil {
 		return err
@@ -2,6 +2,7 @@ package ddevapp
 
 import (
 	"bytes"
+	"fmt"
 	"time"
 
 	"github.com/ddev/ddev/pkg/fileutil"
@@ -31,6 +32,12 @@ type ListCommandSettings struct {
 
 	// TypeFilter contains the project type which is then used to filter the project list
 	TypeFilter string
+
+	// Resources, if set, shows the CPU, memory and disk use of the projects instead
+	Resources bool
+
+	// SortBy is the order of the resource list, one of ResourceSortKeys
+	SortBy string
 }
 
 // List provides the functionality for `ddev list`
@@ -53,6 +60,8 @@ func List(settings ListCommandSettings) {
 
 		if len(apps) < 1 {
 			output.UserOut.WithField("raw", appDescs).Println("No DDEV projects were found.")
+		} else if settings.Resources {
+			listResources(apps, settings)
 		} else {
 			t := CreateAppTable(&out, settings.WrapTableText)
 			for _, app := range apps {
@@ -152,3 +161,58 @@ func CreateAppTable(out *bytes.Buffer, wrapTableText bool) table.Writer {
 	t.SetOutputMirror(out)
 	return t
 }
+
+// listResources renders the CPU, memory and disk use of the projects, with
+// a row for each running service below its project
+func listResources(apps []*DdevApp, settings ListCommandSettings) {
+	var filtered []*DdevApp
+	for _, app := range apps {
+		if settings.TypeFilter == "" || settings.TypeFilter == app.Type {
+			filtered = append(filtered, app)
+		}
+	}
+	resources, err := GetProjectResources(filtered)
+	if err != nil {
+		util.Failed("Failed to get the resource use of projects: %v", err)
+	}
+	if err = SortProjectResources(resources, settings.SortBy); err != nil {
+		util.Failed("%v", err)
+	}
+
+	var out bytes.Buffer
+	t := table.NewWriter()
+	t.SetOutputMirror(&out)
+	styles.SetGlobalTableStyle(t, false)
+	t.AppendHeader(table.Row{"Name", "Status", "CPU", "Memory", "Volumes", "Mutagen", "Snapshots", "Disk"})
+	var cpu float64
+	var memory, disk int64
+	for _, r := range resources {
+		t.AppendRow(table.Row{r.Name, FormatSiteStatus(r.Status), formatCPU(r.CPUPercent, r.Status), formatMemory(r.MemoryBytes, r.Status),
+			util.FormatBytes(r.VolumeBytes), util.FormatBytes(r.MutagenBytes), util.FormatBytes(r.SnapshotBytes), util.FormatBytes(r.DiskBytes())})
+		for _, svc := range r.Services {
+			t.AppendRow(table.Row{"  " + svc.Service, "", fmt.Sprintf("%.1f%%", svc.CPUPercent), util.FormatBytes(svc.MemoryBytes), "", "", "", ""})
+		}
+		cpu += r.CPUPercent
+		memory += r.MemoryBytes
+		disk += r.DiskBytes()
+	}
+	t.AppendFooter(table.Row{"Total", "", fmt.Sprintf("%.1f%%", cpu), util.FormatBytes(memory), "", "", "", util.FormatBytes(disk)})
+	t.Render()
+	output.UserOut.WithField("raw", resources).Print(out.String())
+}
+
+// formatCPU formats the CPU use of a project, which only running projects have
+func formatCPU(percent float64, status string) string {
+	if status != SiteRunning {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f%%", percent)
+}
+
+// formatMemory formats the memory use of a project, which only running projects have
+func formatMemory(bytes int64, status string) string {
+	if status != SiteRunning {
+		return "-"
+	}
+	return util.FormatBytes(bytes)
+}
@@ -0,0 +1,198 @@
+package ddevapp
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ddev/ddev/pkg/dockerutil"
+	"github.com/ddev/ddev/pkg/util"
+	"github.com/moby/moby/api/pkg/stdcopy"
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/client"
+)
+
+// LogEntry is a line of the log of a service container
+type LogEntry struct {
+	Service   string    `json:"service"`
+	Stream    string    `json:"stream"`
+	Timestamp time.Time `json:"timestamp"`
+	Message   string    `json:"message"`
+}
+
+// LogsOptions selects the lines returned by StreamLogs
+type LogsOptions struct {
+	// Services are the compose services to read, plus ddev-router and ddev-ssh-agent
+	Services []string
+	Follow   bool
+	// Tail is the number of lines to show from the end of each log, or "all"
+	Tail string
+	// Since and Until are timestamps or relative durations like "10m"
+	Since string
+	Until string
+	// Grep keeps only the lines matching it when it isn't nil
+	Grep *regexp.Regexp
+}
+
+// findLogContainer returns the container of a service of the project, or
+// of the global ddev-router and ddev-ssh-agent
+func (app *DdevApp) findLogContainer(service string) (*container.Summary, error) {
+	// Let people access ddev-router and ddev-ssh-agent logs as well.
+	if service == "ddev-router" || service == "ddev-ssh-agent" {
+		return dockerutil.FindContainerByLabels(map[string]string{
+			"com.docker.compose.service": service,
+			"com.docker.compose.oneoff":  "False",
+		})
+	}
+	return app.FindContainerByType(service)
+}
+
+// LogServices returns the services of the project that have a container,
+// running or not, sorted by name
+func (app *DdevApp) LogServices() ([]string, error) {
+	containers, err := dockerutil.GetAppContainers(app.GetName())
+	if err != nil {
+		return nil, err
+	}
+	var services []string
+	for _, c := range containers {
+		if service := c.Labels["com.docker.compose.service"]; service != "" && !slices.Contains(services, service) {
+			services = append(services, service)
+		}
+	}
+	slices.Sort(services)
+	return services, nil
+}
+
+// StreamLogs reads the logs of several services and calls handle for each
+// line. Without Follow the lines of all services are sorted by timestamp,
+// with Follow they are handled as they arrive.
+func (app *DdevApp) StreamLogs(opts LogsOptions, handle func(LogEntry)) error {
+	ctx, apiClient, err := dockerutil.GetDockerClient()
+	if err != nil {
+		return err
+	}
+
+	type serviceContainer struct {
+		service string
+		id      string
+	}
+	var containers []serviceContainer
+	for _, service := range opts.Services {
+		c, err := app.findLogContainer(service)
+		if err != nil {
+			return err
+		}
+		if c == nil {
+			util.Warning("No running service container %s was found", service)
+			continue
+		}
+		containers = append(containers, serviceContainer{service: service, id: c.ID})
+	}
+
+	var (
+		mu      sync.Mutex
+		entries []LogEntry
+		errs    []error
+		wg      sync.WaitGroup
+	)
+	emit := func(entry LogEntry) {
+		if opts.Grep != nil && !opts.Grep.MatchString(entry.Message) {
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		if opts.Follow {
+			handle(entry)
+			return
+		}
+		entries = append(entries, entry)
+	}
+
+	for _, c := range containers {
+		wg.Go(func() {
+			rc, err := apiClient.ContainerLogs(ctx, c.id, client.ContainerLogsOptions{
+				ShowStdout: true,
+				ShowStderr: true,
+				Follow:     opts.Follow,
+				Timestamps: true,
+				Tail:       opts.Tail,
+				Since:      opts.Since,
+				Until:      opts.Until,
+			})
+			if err != nil {
+				mu.Lock()
+				errs = append(errs, fmt.Errorf("failed to get logs of %s: %v", c.service, err))
+				mu.Unlock()
+				return
+			}
+			defer rc.Close()
+
+			stdout := &logLineWriter{emit: func(line string) { emit(parseLogLine(c.service, "stdout", line)) }}
+			stderr := &logLineWriter{emit: func(line string) { emit(parseLogLine(c.service, "stderr", line)) }}
+			_, err = stdcopy.StdCopy(stdout, stderr, rc)
+			stdout.flush()
+			stderr.flush()
+			if err != nil {
+				mu.Lock()
+				errs = append(errs, fmt.Errorf("failed to copy container logs of %s: %v", c.service, err))
+				mu.Unlock()
+			}
+		})
+	}
+	wg.Wait()
+
+	slices.SortStableFunc(entries, func(a, b LogEntry) int { return a.Timestamp.Compare(b.Timestamp) })
+	for _, entry := range entries {
+		handle(entry)
+	}
+	if len(errs) > 0 {
+		return errs[0]
+	}
+	return nil
+}
+
+// parseLogLine splits the timestamp that docker adds with Timestamps from
+// a log line
+func parseLogLine(service string, stream string, line string) LogEntry {
+	entry := LogEntry{Service: service, Stream: stream, Message: strings.TrimSuffix(line, "\r")}
+	if prefix, message, found := strings.Cut(entry.Message, " "); found {
+		if ts, err := time.Parse(time.RFC3339Nano, prefix); err == nil {
+			entry.Timestamp = ts
+			entry.Message = message
+		}
+	}
+	return entry
+}
+
+// logLineWriter calls emit for every complete line written to it
+type logLineWriter struct {
+	buf  []byte
+	emit func(line string)
+}
+
+// Write implements io.Writer
+func (w *logLineWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		w.emit(string(w.buf[:i]))
+		w.buf = w.buf[i+1:]
+	}
+	return len(p), nil
+}
+
+// flush emits a last line that has no newline
+func (w *logLineWriter) flush() {
+	if len(w.buf) > 0 {
+		w.emit(string(w.buf))
+		w.buf = nil
+	}
+}
@@ -0,0 +1,29 @@
+package ddevapp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestParseLogLine checks that the docker timestamp is split from log lines
+func TestParseLogLine(t *testing.T) {
+	entry := parseLogLine("web", "stderr", "2024-01-02T15:04:05.123456789Z PHP Notice:  Undefined variable\r")
+	require.Equal(t, LogEntry{Service: "web", Stream: "stderr", Timestamp: time.Date(2024, 1, 2, 15, 4, 5, 123456789, time.UTC), Message: "PHP Notice:  Undefined variable"}, entry)
+
+	entry = parseLogLine("db", "stdout", "no timestamp here")
+	require.True(t, entry.Timestamp.IsZero())
+	require.Equal(t, "no timestamp here", entry.Message)
+}
+
+// TestLogLineWriter checks that writes are split into lines
+func TestLogLineWriter(t *testing.T) {
+	var lines []string
+	w := &logLineWriter{emit: func(line string) { lines = append(lines, line) }}
+	_, _ = w.Write([]byte("first\nsec"))
+	_, _ = w.Write([]byte("ond\n\nlast"))
+	require.Equal(t, []string{"first", "second", ""}, lines)
+	w.flush()
+	require.Equal(t, []string{"first", "second", "", "last"}, lines)
+}
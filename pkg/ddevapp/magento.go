@@ -143,6 +143,20 @@ func createMagento2SettingsFile(app *DdevApp) (string, error) {
 		}
 
 		templateVars := map[string]any{"DBHostname": "db"}
+		if name := app.GetCacheService(); name != "" {
+			templateVars["CacheHost"] = name
+			templateVars["CachePort"] = builtinServices[name].port
+		}
+		// Magento names its search engines opensearch, elasticsearch7 and elasticsearch8
+		if name := app.GetSearchService(); name != "" {
+			engine := name
+			if name == ServiceElasticsearch {
+				engine = name + app.GetBuiltinServiceVersion(name)
+			}
+			templateVars["SearchEngine"] = engine
+			templateVars["SearchHost"] = name
+			templateVars["SearchPort"] = builtinServices[name].port
+		}
 		err = fileutil.TemplateStringToFile(string(content), templateVars, app.SiteSettingsPath)
 		if err != nil {
 			return "", err
@@ -33,6 +33,38 @@ return [
     ],
     'x-frame-options' => 'SAMEORIGIN',
     'MAGE_MODE' => 'default',
+{{- if .CacheHost }}
+    'session' => [
+        'save' => 'redis',
+        'redis' => [
+            'host' => '{{ .CacheHost }}',
+            'port' => '{{ .CachePort }}',
+            'database' => '2'
+        ]
+    ],
+    'cache' => [
+        'frontend' => [
+            'default' => [
+                'id_prefix' => '40d_',
+                'backend' => 'Magento\\Framework\\Cache\\Backend\\Redis',
+                'backend_options' => [
+                    'server' => '{{ .CacheHost }}',
+                    'port' => '{{ .CachePort }}',
+                    'database' => '0'
+                ]
+            ],
+            'page_cache' => [
+                'id_prefix' => '40d_',
+                'backend' => 'Magento\\Framework\\Cache\\Backend\\Redis',
+                'backend_options' => [
+                    'server' => '{{ .CacheHost }}',
+                    'port' => '{{ .CachePort }}',
+                    'database' => '1'
+                ]
+            ]
+        ]
+    ],
+{{- else }}
     'session' => [
         'save' => 'files'
     ],
@@ -46,6 +78,20 @@ return [
             ]
         ]
     ],
+{{- end }}
+{{- if .SearchEngine }}
+    'system' => [
+        'default' => [
+            'catalog' => [
+                'search' => [
+                    'engine' => '{{ .SearchEngine }}',
+                    '{{ .SearchEngine }}_server_hostname' => '{{ .SearchHost }}',
+                    '{{ .SearchEngine }}_server_port' => '{{ .SearchPort }}'
+                ]
+            ]
+        ]
+    ],
+{{- end }}
     'lock' => [
         'provider' => 'db',
         'config' => [
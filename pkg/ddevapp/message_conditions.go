@@ -0,0 +1,122 @@
+package ddevapp
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/ddev/ddev/pkg/config/remoteconfig"
+	"github.com/ddev/ddev/pkg/globalconfig"
+	"github.com/ddev/ddev/pkg/util"
+	"go.yaml.in/yaml/v4"
+)
+
+// Message conditions about the project in the current directory, so
+// messages from extra sources can target projects
+func init() {
+	remoteconfig.AddArgCondition("ProjectType", "The current project has this type, like ProjectType:drupal11", func(arg string) bool {
+		app := messageConditionApp()
+		return app != nil && strings.EqualFold(app.Type, arg)
+	})
+	remoteconfig.AddArgCondition("Addon", "An add-on is installed in the current project, by name or repository, optionally with a version constraint, like Addon:ddev/ddev-redis@<2.1.0", func(arg string) bool {
+		app := messageConditionApp()
+		return app != nil && hasAddon(app, arg)
+	})
+	remoteconfig.AddArgCondition("ProjectConfig", "A value of the current project's config is set, or has the value, like ProjectConfig:php_version=8.1 or ProjectConfig:database.type=postgres", func(arg string) bool {
+		app := messageConditionApp()
+		if app == nil {
+			return false
+		}
+		config, err := app.GetProcessedProjectConfigYAML()
+		if err != nil {
+			util.Debug("Unable to get the project config for message conditions: %v", err)
+			return false
+		}
+		return configValueMatches(config, arg)
+	})
+	remoteconfig.AddArgCondition("GlobalConfig", "A value of the global config is set, or has the value, like GlobalConfig:performance_mode=mutagen", func(arg string) bool {
+		config, err := yaml.Marshal(globalconfig.DdevGlobalConfig)
+		if err != nil {
+			return false
+		}
+		return configValueMatches(config, arg)
+	})
+}
+
+// messageConditionApp returns the project in the current directory, loaded
+// once from its configuration, or nil outside a project
+var messageConditionApp = sync.OnceValue(func() *DdevApp {
+	appRoot, err := GetActiveAppRoot("")
+	if err != nil {
+		return nil
+	}
+	app, err := NewApp(appRoot, true)
+	if err != nil {
+		util.Debug("Unable to load the project in %s for message conditions: %v", appRoot, err)
+		return nil
+	}
+	return app
+})
+
+// hasAddon reports whether an add-on like "redis", "ddev/ddev-redis" or
+// "ddev/ddev-redis@<2.1.0" is installed in the project
+func hasAddon(app *DdevApp, arg string) bool {
+	name, constraint, _ := strings.Cut(arg, "@")
+	for _, manifest := range GetInstalledAddons(app) {
+		if !strings.EqualFold(manifest.Name, name) && !strings.EqualFold(manifest.Repository, name) {
+			continue
+		}
+		if constraint == "" {
+			return true
+		}
+		match, err := util.SemverValidate(constraint, manifest.Version)
+		if err != nil {
+			util.Debug("Unable to compare add-on %s version '%s' with '%s': %v", name, manifest.Version, constraint, err)
+			return false
+		}
+		return match
+	}
+	return false
+}
+
+// configValueMatches reports whether a dotted key like database.type has
+// the value in a YAML config, for an arg like "database.type=postgres", or
+// is set to something other than an empty or false value, for an arg like
+// "use_dns_when_possible"
+func configValueMatches(config []byte, arg string) bool {
+	key, expected, hasValue := strings.Cut(arg, "=")
+	var value any = map[string]any{}
+	if err := yaml.Unmarshal(config, &value); err != nil {
+		return false
+	}
+	for part := range strings.SplitSeq(strings.TrimSpace(key), ".") {
+		m, ok := value.(map[string]any)
+		if !ok {
+			return false
+		}
+		if value, ok = m[part]; !ok {
+			return false
+		}
+	}
+	if value == nil {
+		return false
+	}
+	actual := strings.TrimSpace(yamlScalarString(value))
+	if hasValue {
+		return actual == strings.TrimSpace(expected)
+	}
+	return actual != "" && actual != "false" && actual != "0" && actual != "[]" && actual != "{}"
+}
+
+// yamlScalarString formats a value of a YAML config for comparison
+func yamlScalarString(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	default:
+		out, err := yaml.Marshal(v)
+		if err != nil {
+			return ""
+		}
+		return strings.TrimSpace(string(out))
+	}
+}
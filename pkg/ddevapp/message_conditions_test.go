@@ -0,0 +1,144 @@
+package ddevapp
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ddev/ddev/pkg/config/remoteconfig"
+	"github.com/ddev/ddev/pkg/config/types"
+	"github.com/ddev/ddev/pkg/globalconfig"
+	"github.com/stretchr/testify/require"
+)
+
+// TestConfigValueMatches checks matching dotted keys and values of a YAML config
+func TestConfigValueMatches(t *testing.T) {
+	config := []byte(`php_version: "8.1"
+xdebug_enabled: false
+use_dns_when_possible: true
+router_http_port: 80
+additional_hostnames: []
+web_environment:
+  - FOO=bar
+database:
+  type: postgres
+  version: "16"
+empty_value:
+`)
+	testCases := []struct {
+		arg      string
+		expected bool
+	}{
+		{"php_version=8.1", true},
+		{"php_version=8.2", false},
+		{" php_version = 8.1 ", true},
+		{"php_version", true},
+		{"database.type=postgres", true},
+		{"database.type=mariadb", false},
+		{"database.version=16", true},
+		{"database.missing", false},
+		{"database.type.deeper", false},
+		{"router_http_port=80", true},
+		{"use_dns_when_possible", true},
+		{"use_dns_when_possible=true", true},
+		{"xdebug_enabled", false},
+		{"xdebug_enabled=false", true},
+		{"additional_hostnames", false},
+		{"web_environment", true},
+		{"empty_value", false},
+		{"missing", false},
+		{"missing=", false},
+	}
+	for _, tc := range testCases {
+		require.Equal(t, tc.expected, configValueMatches(config, tc.arg), tc.arg)
+	}
+	require.False(t, configValueMatches([]byte("not: [valid"), "not"))
+}
+
+// TestHasAddon checks finding installed add-ons by name, repository and version
+func TestHasAddon(t *testing.T) {
+	app := &DdevApp{AppRoot: t.TempDir()}
+	writeTestAddonManifest(t, app, "redis", "ddev/ddev-redis", "v2.0.1")
+	writeTestAddonManifest(t, app, "solr", "ddev/ddev-solr", "not-a-version")
+
+	testCases := []struct {
+		arg      string
+		expected bool
+	}{
+		{"redis", true},
+		{"Redis", true},
+		{"ddev/ddev-redis", true},
+		{"ddev/ddev-redis@<2.1.0", true},
+		{"ddev/ddev-redis@>=2.1.0", false},
+		{"redis@^2", true},
+		{"redis@invalid constraint", false},
+		{"solr", true},
+		{"solr@>=1", false},
+		{"memcached", false},
+		{"ddev/ddev-memcached@<2", false},
+	}
+	for _, tc := range testCases {
+		require.Equal(t, tc.expected, hasAddon(app, tc.arg), tc.arg)
+	}
+}
+
+// TestMessageConditions checks the project and global message conditions
+func TestMessageConditions(t *testing.T) {
+	origApp := messageConditionApp
+	origPerformanceMode := globalconfig.DdevGlobalConfig.PerformanceMode
+	t.Cleanup(func() {
+		messageConditionApp = origApp
+		globalconfig.DdevGlobalConfig.PerformanceMode = origPerformanceMode
+	})
+
+	app := &DdevApp{AppRoot: t.TempDir(), Type: "drupal11"}
+	require.NoError(t, os.MkdirAll(app.GetConfigPath(""), 0755))
+	require.NoError(t, os.WriteFile(app.GetConfigPath("config.yaml"), []byte(`name: conditions
+type: drupal11
+php_version: "8.3"
+database:
+  type: postgres
+  version: "16"
+`), 0644))
+	writeTestAddonManifest(t, app, "redis", "ddev/ddev-redis", "v2.0.1")
+	globalconfig.DdevGlobalConfig.PerformanceMode = types.PerformanceModeMutagen
+
+	inProject := func() *DdevApp { return app }
+	outsideProject := func() *DdevApp { return nil }
+
+	testCases := []struct {
+		condition string
+		appFunc   func() *DdevApp
+		expected  bool
+	}{
+		{"ProjectType:drupal11", inProject, true},
+		{"ProjectType:Drupal11", inProject, true},
+		{"ProjectType:laravel", inProject, false},
+		{"!ProjectType:laravel", inProject, true},
+		{"ProjectType:drupal11", outsideProject, false},
+		{"Addon:ddev/ddev-redis", inProject, true},
+		{"Addon:redis@<2.1.0", inProject, true},
+		{"Addon:ddev/ddev-solr", inProject, false},
+		{"Addon:redis", outsideProject, false},
+		{"ProjectConfig:php_version=8.3", inProject, true},
+		{"ProjectConfig:database.type=postgres", inProject, true},
+		{"ProjectConfig:database.type=mariadb", inProject, false},
+		{"ProjectConfig:php_version", outsideProject, false},
+		{"GlobalConfig:performance_mode=mutagen", outsideProject, true},
+		{"GlobalConfig:performance_mode=none", outsideProject, false},
+	}
+	for _, tc := range testCases {
+		messageConditionApp = tc.appFunc
+		result, known := remoteconfig.CheckCondition(tc.condition)
+		require.True(t, known, tc.condition)
+		require.Equal(t, tc.expected, result, tc.condition)
+	}
+}
+
+// writeTestAddonManifest records an installed add-on in the project
+func writeTestAddonManifest(t *testing.T, app *DdevApp, name string, repository string, version string) {
+	dir := filepath.Join(app.GetConfigPath(AddonMetadataDir), name)
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	manifest := "name: " + name + "\nrepository: " + repository + "\nversion: " + version + "\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "manifest.yaml"), []byte(manifest), 0644))
+}
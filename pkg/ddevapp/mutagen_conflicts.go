@@ -0,0 +1,228 @@
+package ddevapp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ddev/ddev/pkg/dockerutil"
+)
+
+const (
+	// MutagenKeepHost resolves a sync conflict with the host's version
+	MutagenKeepHost = "host"
+	// MutagenKeepContainer resolves a sync conflict with the container's version
+	MutagenKeepContainer = "container"
+	// mutagenConflictPreviewLines is the number of lines shown of each side
+	mutagenConflictPreviewLines = 5
+)
+
+// MutagenConflictSide is one side of a sync conflict, the host (alpha) or
+// the web container (beta)
+type MutagenConflictSide struct {
+	Exists  bool      `json:"exists"`
+	Kind    string    `json:"kind,omitempty"`
+	Size    int64     `json:"size,omitempty"`
+	ModTime time.Time `json:"modified,omitzero"`
+	Preview string    `json:"preview,omitempty"`
+}
+
+// MutagenConflict is a path changed on both sides since the last sync
+type MutagenConflict struct {
+	Path      string              `json:"path"`
+	Host      MutagenConflictSide `json:"host"`
+	Container MutagenConflictSide `json:"container"`
+}
+
+// mutagenConflict is a conflict as listed by `mutagen sync list`, the
+// changes of both sides aren't needed, their current state is read instead
+type mutagenConflict struct {
+	Root string `json:"root"`
+}
+
+// MutagenConflicts returns the sync conflicts of the project, without
+// the details of their sides, see MutagenConflictDetails
+func (app *DdevApp) MutagenConflicts() ([]MutagenConflict, error) {
+	_, _, session, err := app.MutagenStatus()
+	if err != nil {
+		return nil, err
+	}
+	raw, ok := session["conflicts"]
+	if !ok {
+		return nil, nil
+	}
+	b, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+	var listed []mutagenConflict
+	if err = json.Unmarshal(b, &listed); err != nil {
+		return nil, fmt.Errorf("unable to parse Mutagen conflicts: %v", err)
+	}
+	conflicts := make([]MutagenConflict, 0, len(listed))
+	for _, c := range listed {
+		conflicts = append(conflicts, MutagenConflict{Path: c.Root})
+	}
+	slices.SortFunc(conflicts, func(a, b MutagenConflict) int { return strings.Compare(a.Path, b.Path) })
+	return conflicts, nil
+}
+
+// MutagenConflictDetails reads the current state of both sides of a
+// conflict, with the first lines of files
+func (app *DdevApp) MutagenConflictDetails(conflict *MutagenConflict) error {
+	hostPath := filepath.Join(app.AppRoot, filepath.FromSlash(conflict.Path))
+	if fi, err := os.Lstat(hostPath); err == nil {
+		conflict.Host = MutagenConflictSide{Exists: true, Kind: fileKind(fi.Mode()), Size: fi.Size(), ModTime: fi.ModTime()}
+		if fi.Mode().IsRegular() {
+			if content, err := readHead(hostPath, 4096); err == nil {
+				conflict.Host.Preview = conflictPreview(content)
+			}
+		}
+	}
+
+	// %F is the file type, %s the size and %Y the modification time
+	out, _, err := app.Exec(&ExecOpts{
+		RawCmd: []string{"bash", "-c", `stat -c '%F|%s|%Y' -- "$1" && if [ -f "$1" ]; then head -c 4096 -- "$1"; fi`, "bash", path.Join(app.GetAbsAppRoot(true), conflict.Path)},
+	})
+	if err != nil {
+		// The path doesn't exist in the container
+		return nil
+	}
+	statLine, content, _ := strings.Cut(out, "\n")
+	fields := strings.Split(statLine, "|")
+	if len(fields) != 3 {
+		return fmt.Errorf("unexpected stat output for %s in the web container: %s", conflict.Path, statLine)
+	}
+	size, _ := strconv.ParseInt(fields[1], 10, 64)
+	modTime, _ := strconv.ParseInt(fields[2], 10, 64)
+	conflict.Container = MutagenConflictSide{Exists: true, Kind: containerFileKind(fields[0]), Size: size, ModTime: time.Unix(modTime, 0)}
+	if conflict.Container.Kind == "file" {
+		conflict.Container.Preview = conflictPreview([]byte(content))
+	}
+	return nil
+}
+
+// ResolveMutagenConflict makes both sides of a conflicted path the same,
+// using the host's or the container's version, and syncs. conflictPath
+// is relative to the project root.
+func (app *DdevApp) ResolveMutagenConflict(conflictPath string, keep string) error {
+	if keep != MutagenKeepHost && keep != MutagenKeepContainer {
+		return fmt.Errorf("--keep must be '%s' or '%s'", MutagenKeepHost, MutagenKeepContainer)
+	}
+	if filepath.IsAbs(conflictPath) {
+		rel, err := filepath.Rel(app.AppRoot, conflictPath)
+		if err != nil {
+			return err
+		}
+		conflictPath = rel
+	}
+	conflictPath = path.Clean(filepath.ToSlash(conflictPath))
+	if conflictPath == "." || conflictPath == ".." || strings.HasPrefix(conflictPath, "../") {
+		return fmt.Errorf("'%s' isn't a path in the project", conflictPath)
+	}
+
+	conflicts, err := app.MutagenConflicts()
+	if err != nil {
+		return err
+	}
+	if !slices.ContainsFunc(conflicts, func(c MutagenConflict) bool {
+		return conflictPath == c.Path || strings.HasPrefix(conflictPath, c.Path+"/")
+	}) {
+		return fmt.Errorf("there is no sync conflict for '%s', see 'ddev mutagen conflicts'", conflictPath)
+	}
+
+	conflict := MutagenConflict{Path: conflictPath}
+	if err = app.MutagenConflictDetails(&conflict); err != nil {
+		return err
+	}
+	hostPath := filepath.Join(app.AppRoot, filepath.FromSlash(conflictPath))
+	containerPath := path.Join(app.GetAbsAppRoot(true), conflictPath)
+	webContainer := GetContainerName(app, "web")
+
+	switch keep {
+	case MutagenKeepHost:
+		if _, stderr, err := app.Exec(&ExecOpts{RawCmd: []string{"rm", "-rf", "--", containerPath}}); err != nil {
+			return fmt.Errorf("unable to remove %s in the web container: %v %s", containerPath, err, stderr)
+		}
+		if conflict.Host.Exists {
+			target := path.Dir(containerPath)
+			if conflict.Host.Kind == "directory" {
+				target = containerPath
+			}
+			if err = dockerutil.CopyIntoContainer(hostPath, webContainer, target, ""); err != nil {
+				return fmt.Errorf("unable to copy %s into the web container: %v", conflictPath, err)
+			}
+		}
+	case MutagenKeepContainer:
+		if err = os.RemoveAll(hostPath); err != nil {
+			return err
+		}
+		if conflict.Container.Exists {
+			if err = dockerutil.CopyFromContainer(webContainer, containerPath, filepath.Dir(hostPath)); err != nil {
+				return fmt.Errorf("unable to copy %s from the web container: %v", conflictPath, err)
+			}
+		}
+	}
+
+	return app.MutagenSyncFlush()
+}
+
+// fileKind names the type of a host file like Mutagen does
+func fileKind(mode os.FileMode) string {
+	switch {
+	case mode.IsDir():
+		return "directory"
+	case mode&os.ModeSymlink != 0:
+		return "symlink"
+	case mode.IsRegular():
+		return "file"
+	}
+	return "other"
+}
+
+// containerFileKind names the type reported by stat %F like fileKind
+func containerFileKind(statType string) string {
+	switch {
+	case statType == "directory":
+		return "directory"
+	case statType == "symbolic link":
+		return "symlink"
+	case strings.Contains(statType, "regular"):
+		return "file"
+	}
+	return "other"
+}
+
+// readHead returns at most n bytes from the start of a file
+func readHead(name string, n int) ([]byte, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	buf := make([]byte, n)
+	read, err := f.Read(buf)
+	if err != nil && read == 0 {
+		return nil, err
+	}
+	return buf[:read], nil
+}
+
+// conflictPreview returns the first lines of a file's content
+func conflictPreview(content []byte) string {
+	if bytes.IndexByte(content, 0) >= 0 {
+		return "(binary)"
+	}
+	lines := strings.Split(strings.TrimRight(string(content), "\n"), "\n")
+	if len(lines) > mutagenConflictPreviewLines {
+		lines = append(lines[:mutagenConflictPreviewLines], "...")
+	}
+	return strings.Join(lines, "\n")
+}
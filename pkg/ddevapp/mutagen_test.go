@@ -335,3 +335,87 @@ func TestMutagenDiagnose(t *testing.T) {
 		require.False(t, result.HasProblems, "HasProblems should be false when sync status is 'ok'")
 	}
 }
+
+// TestMutagenConflicts tests listing and resolving Mutagen sync conflicts
+func TestMutagenConflicts(t *testing.T) {
+	if nodeps.IsWindows() {
+		t.Skip("TestMutagenConflicts takes way too long on Windows, skipping")
+	}
+	origDir, _ := os.Getwd()
+	site := TestSites[0]
+	app, err := ddevapp.NewApp(site.Dir, false)
+	require.NoError(t, err)
+	origPerformanceMode := app.GetPerformanceMode()
+	app.SetPerformanceMode(types.PerformanceModeMutagen)
+	err = app.WriteConfig()
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		_ = os.Chdir(origDir)
+		_ = os.Remove(filepath.Join(app.AppRoot, "conflict-host.txt"))
+		_ = os.Remove(filepath.Join(app.AppRoot, "conflict-container.txt"))
+		_ = os.Remove(app.GetConfigPath("mutagen/mutagen.yml"))
+		err = app.Stop(true, false)
+		require.NoError(t, err)
+		app.SetPerformanceMode(origPerformanceMode)
+		err = app.WriteConfig()
+		require.NoError(t, err)
+	})
+	err = app.Start()
+	require.NoError(t, err)
+
+	// With the default two-way-resolved mode the host always wins, so use
+	// two-way-safe, which reports files created on both sides as conflicts
+	mutagenYml := app.GetConfigPath("mutagen/mutagen.yml")
+	content, err := os.ReadFile(mutagenYml)
+	require.NoError(t, err)
+	content = []byte(strings.ReplaceAll(strings.ReplaceAll(string(content), nodeps.DdevFileSignature, ""), "two-way-resolved", "two-way-safe"))
+	err = os.WriteFile(mutagenYml, content, 0644)
+	require.NoError(t, err)
+	err = app.Restart()
+	require.NoError(t, err)
+
+	conflicts, err := app.MutagenConflicts()
+	require.NoError(t, err)
+	require.Empty(t, conflicts)
+
+	err = ddevapp.PauseMutagenSync(app)
+	require.NoError(t, err)
+	for _, name := range []string{"conflict-host.txt", "conflict-container.txt"} {
+		err = os.WriteFile(filepath.Join(app.AppRoot, name), []byte("from the host\n"), 0644)
+		require.NoError(t, err)
+		_, _, err = app.Exec(&ddevapp.ExecOpts{Cmd: "echo 'from the container' > /var/www/html/" + name})
+		require.NoError(t, err)
+	}
+	err = ddevapp.ResumeMutagenSync(app)
+	require.NoError(t, err)
+	err = app.MutagenSyncFlush()
+	require.NoError(t, err)
+
+	conflicts, err = app.MutagenConflicts()
+	require.NoError(t, err)
+	require.Len(t, conflicts, 2)
+	require.Equal(t, "conflict-container.txt", conflicts[0].Path)
+	err = app.MutagenConflictDetails(&conflicts[0])
+	require.NoError(t, err)
+	require.Equal(t, "from the host", conflicts[0].Host.Preview)
+	require.Equal(t, "from the container", conflicts[0].Container.Preview)
+	require.Equal(t, "file", conflicts[0].Container.Kind)
+
+	err = app.ResolveMutagenConflict("conflict-host.txt", ddevapp.MutagenKeepHost)
+	require.NoError(t, err)
+	err = app.ResolveMutagenConflict(filepath.Join(app.AppRoot, "conflict-container.txt"), ddevapp.MutagenKeepContainer)
+	require.NoError(t, err)
+	err = app.ResolveMutagenConflict("conflict-host.txt", ddevapp.MutagenKeepHost)
+	require.ErrorContains(t, err, "there is no sync conflict")
+
+	conflicts, err = app.MutagenConflicts()
+	require.NoError(t, err)
+	require.Empty(t, conflicts)
+	out, _, err := app.Exec(&ddevapp.ExecOpts{Cmd: "cat /var/www/html/conflict-host.txt"})
+	require.NoError(t, err)
+	require.Equal(t, "from the host\n", out)
+	content, err = os.ReadFile(filepath.Join(app.AppRoot, "conflict-container.txt"))
+	require.NoError(t, err)
+	require.Equal(t, "from the container\n", string(content))
+}
@@ -0,0 +1,223 @@
+package ddevapp
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/ddev/ddev/pkg/dockerutil"
+	ddevexec "github.com/ddev/ddev/pkg/exec"
+	"github.com/ddev/ddev/pkg/globalconfig"
+	"github.com/ddev/ddev/pkg/nodeps"
+	"github.com/ddev/ddev/pkg/util"
+)
+
+// Values of name_from, which derive the project name from the git checkout
+const (
+	// NameFromGitBranch suffixes the name with the checked out branch,
+	// except for the default branch
+	NameFromGitBranch = "git-branch"
+	// NameFromWorktree suffixes the name with the name of the git worktree,
+	// except in the main worktree
+	NameFromWorktree = "worktree"
+)
+
+// ValidNameFromValues are the values allowed for name_from
+var ValidNameFromValues = []string{NameFromGitBranch, NameFromWorktree}
+
+// maxProjectNameLength keeps the project name a valid hostname label
+const maxProjectNameLength = 63
+
+// gitCheckout describes the git worktree a project is in
+type gitCheckout struct {
+	// TopLevel is the root directory of the worktree
+	TopLevel string
+	// MainTopLevel is the root directory of the main worktree, empty for a bare repository
+	MainTopLevel string
+	// Worktree is the name of a linked worktree, empty in the main worktree
+	Worktree string
+	// Branch is the checked out branch, empty when HEAD is detached
+	Branch string
+	// DefaultBranch is the branch origin/HEAD points to, empty if unknown
+	DefaultBranch string
+}
+
+// getGitCheckout returns the git worktree dir is in
+func getGitCheckout(dir string) (*gitCheckout, error) {
+	out, err := ddevexec.RunHostCommand("git", "-C", dir, "rev-parse", "--path-format=absolute", "--show-toplevel", "--git-dir", "--git-common-dir")
+	if err != nil {
+		return nil, fmt.Errorf("%s isn't in a git checkout: %v, output=%s", dir, err, strings.TrimSpace(out))
+	}
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 3 {
+		return nil, fmt.Errorf("unexpected output of git rev-parse in %s: %s", dir, out)
+	}
+	c := &gitCheckout{TopLevel: lines[0]}
+	gitDir, commonDir := lines[1], lines[2]
+	if filepath.Base(commonDir) == ".git" {
+		c.MainTopLevel = filepath.Dir(commonDir)
+	}
+	if filepath.Clean(gitDir) != filepath.Clean(commonDir) {
+		c.Worktree = filepath.Base(gitDir)
+	}
+	if branch, err := ddevexec.RunHostCommand("git", "-C", dir, "symbolic-ref", "--quiet", "--short", "HEAD"); err == nil {
+		c.Branch = strings.TrimSpace(branch)
+	}
+	if defaultBranch, err := ddevexec.RunHostCommand("git", "-C", dir, "symbolic-ref", "--quiet", "--short", "refs/remotes/origin/HEAD"); err == nil {
+		c.DefaultBranch = strings.TrimPrefix(strings.TrimSpace(defaultBranch), "origin/")
+	}
+	return c, nil
+}
+
+// nameSuffix returns what name_from adds to the project name in this
+// checkout, or an empty string if the configured name is used as is
+func (c *gitCheckout) nameSuffix(nameFrom string) string {
+	switch nameFrom {
+	case NameFromWorktree:
+		return c.Worktree
+	case NameFromGitBranch:
+		if c.Branch == "" || c.Branch == c.DefaultBranch {
+			return ""
+		}
+		if c.DefaultBranch == "" && slices.Contains([]string{"main", "master"}, c.Branch) {
+			return ""
+		}
+		return c.Branch
+	}
+	return ""
+}
+
+var nonNameCharacters = regexp.MustCompile(`[^a-z0-9]+`)
+
+// nameWithSuffix appends the suffix to name, so the result is still a valid
+// project name and hostname
+func nameWithSuffix(name string, suffix string) string {
+	suffix = strings.Trim(nonNameCharacters.ReplaceAllString(strings.ToLower(suffix), "-"), "-")
+	if suffix == "" {
+		return name
+	}
+	if room := maxProjectNameLength - len(name) - 1; len(suffix) > room {
+		suffix = strings.TrimRight(suffix[:max(room, 0)], "-")
+		if suffix == "" {
+			return name
+		}
+	}
+	return name + "-" + suffix
+}
+
+// ApplyNameFrom derives the project name from the git checkout according to
+// name_from, so every worktree or branch is a separate project, with its own
+// hostnames, database volume and Mutagen session. It can be called again
+// after name_from changed. The name in config.yaml isn't changed.
+func (app *DdevApp) ApplyNameFrom() error {
+	app.Name = strings.TrimSuffix(app.Name, app.nameFromSuffix)
+	app.nameFromSuffix = ""
+	if app.NameFrom == "" {
+		return nil
+	}
+	if !slices.Contains(ValidNameFromValues, app.NameFrom) {
+		return fmt.Errorf("invalid name_from '%s', must be one of %v", app.NameFrom, ValidNameFromValues)
+	}
+	checkout, err := getGitCheckout(app.AppRoot)
+	if err != nil {
+		return err
+	}
+	name := nameWithSuffix(app.Name, checkout.nameSuffix(app.NameFrom))
+	app.nameFromSuffix = strings.TrimPrefix(name, app.Name)
+	app.Name = name
+	return nil
+}
+
+// isNameFromSibling reports whether name is the name of this project on
+// another branch, derived by name_from from the same configured name, like
+// after switching branches in the same directory
+func (app *DdevApp) isNameFromSibling(name string) bool {
+	if app.NameFrom == "" {
+		return false
+	}
+	baseName := strings.TrimSuffix(app.Name, app.nameFromSuffix)
+	return name == baseName || strings.HasPrefix(name, baseName+"-")
+}
+
+// removeNameFromSibling stops the project under the name it had on another
+// branch, if it's running, and removes it from the project list, so this
+// directory has one project. Its database volume is kept.
+func (app *DdevApp) removeNameFromSibling(name string) error {
+	sibling := *app
+	sibling.Name = name
+	sibling.nameFromSuffix = strings.TrimPrefix(name, strings.TrimSuffix(app.Name, app.nameFromSuffix))
+	if status, _ := sibling.SiteStatus(); status == SiteRunning || status == SitePaused {
+		util.Success("Stopping %s, the name of this project on another branch", name)
+		if err := sibling.Stop(false, false); err != nil {
+			return fmt.Errorf("unable to stop %s, the name of this project on another branch: %v", name, err)
+		}
+	}
+	return globalconfig.RemoveProjectInfo(name)
+}
+
+// IsNameDerived reports whether name_from changed the configured project name
+func (app *DdevApp) IsNameDerived() bool {
+	return app.nameFromSuffix != ""
+}
+
+// GetMainWorktreeAppRoot returns the directory of the same project in the
+// main git worktree, which is AppRoot in the main worktree
+func (app *DdevApp) GetMainWorktreeAppRoot() (string, error) {
+	checkout, err := getGitCheckout(app.AppRoot)
+	if err != nil {
+		return "", err
+	}
+	if checkout.MainTopLevel == "" {
+		return "", fmt.Errorf("the git repository of %s has no main worktree", app.AppRoot)
+	}
+	appRoot, err := filepath.EvalSymlinks(app.AppRoot)
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(checkout.TopLevel, appRoot)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(checkout.MainTopLevel, rel), nil
+}
+
+// needsWorktreeDBSeed reports whether the database of a project with a
+// derived name should be seeded from the main worktree, which is only done
+// when its database volume doesn't exist yet
+func (app *DdevApp) needsWorktreeDBSeed() bool {
+	if !app.WorktreeSeedDB || !app.IsNameDerived() || app.IsDBOmitted() {
+		return false
+	}
+	volume := app.GetMariaDBVolumeName()
+	if app.Database.Type == nodeps.Postgres {
+		volume = app.GetPostgresVolumeName()
+	}
+	return !dockerutil.VolumeExists(volume)
+}
+
+// seedDBFromMainWorktree restores the latest snapshot of the project in the
+// main worktree into the database
+func (app *DdevApp) seedDBFromMainWorktree() error {
+	mainAppRoot, err := app.GetMainWorktreeAppRoot()
+	if err != nil {
+		return err
+	}
+	mainApp, err := NewApp(mainAppRoot, true)
+	if err != nil {
+		return fmt.Errorf("unable to read the project in the main worktree %s: %v", mainAppRoot, err)
+	}
+	snapshotName, err := mainApp.GetLatestSnapshot()
+	if err != nil {
+		return fmt.Errorf("no snapshot of %s to seed the database from, create one with 'ddev snapshot' in %s", mainApp.Name, mainAppRoot)
+	}
+	srcDir, destDir := mainApp.GetConfigPath("db_snapshots"), app.GetConfigPath("db_snapshots")
+	if srcDir != destDir {
+		if _, err = copySnapshotFiles(srcDir, destDir, snapshotName); err != nil {
+			return err
+		}
+	}
+	util.Success("Seeding the database of %s from snapshot %s of %s", app.Name, snapshotName, mainApp.Name)
+	return app.RestoreSnapshot(snapshotName)
+}
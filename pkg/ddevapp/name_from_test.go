@@ -0,0 +1,113 @@
+package ddevapp
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	ddevexec "github.com/ddev/ddev/pkg/exec"
+	"github.com/ddev/ddev/pkg/globalconfig"
+	"github.com/stretchr/testify/require"
+)
+
+// TestNameWithSuffix checks that derived names stay valid project names
+func TestNameWithSuffix(t *testing.T) {
+	require.Equal(t, "shop", nameWithSuffix("shop", ""))
+	require.Equal(t, "shop", nameWithSuffix("shop", "/"))
+	require.Equal(t, "shop-feature-cart-v2", nameWithSuffix("shop", "feature/Cart_v2"))
+
+	long := nameWithSuffix("shop", strings.Repeat("a", 40)+"-"+strings.Repeat("b", 40))
+	require.Len(t, long, maxProjectNameLength)
+	require.NoError(t, ValidateProjectName(long))
+}
+
+// TestApplyNameFrom checks the names derived from branches and worktrees
+func TestApplyNameFrom(t *testing.T) {
+	repo := t.TempDir()
+	git := func(dir string, args ...string) {
+		out, err := ddevexec.RunHostCommand("git", append([]string{"-C", dir, "-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		require.NoError(t, err, out)
+	}
+	git(repo, "init", "--quiet", "--initial-branch=main")
+	git(repo, "commit", "--quiet", "--allow-empty", "-m", "initial")
+	worktree := filepath.Join(t.TempDir(), "shop-review")
+	git(repo, "worktree", "add", "--quiet", "-b", "feature/cart", worktree)
+
+	app := &DdevApp{Name: "shop", AppRoot: repo, NameFrom: NameFromWorktree}
+	require.NoError(t, app.ApplyNameFrom())
+	require.Equal(t, "shop", app.Name)
+	require.False(t, app.IsNameDerived())
+
+	app = &DdevApp{Name: "shop", AppRoot: worktree, NameFrom: NameFromWorktree}
+	require.NoError(t, app.ApplyNameFrom())
+	require.Equal(t, "shop-shop-review", app.Name)
+	require.True(t, app.IsNameDerived())
+	mainAppRoot, err := app.GetMainWorktreeAppRoot()
+	require.NoError(t, err)
+	expected, _ := filepath.EvalSymlinks(repo)
+	require.Equal(t, expected, mainAppRoot)
+
+	// Changing name_from replaces the derived part of the name
+	app.NameFrom = NameFromGitBranch
+	require.NoError(t, app.ApplyNameFrom())
+	require.Equal(t, "shop-feature-cart", app.Name)
+	app.NameFrom = ""
+	require.NoError(t, app.ApplyNameFrom())
+	require.Equal(t, "shop", app.Name)
+
+	app = &DdevApp{Name: "shop", AppRoot: repo, NameFrom: NameFromGitBranch}
+	require.NoError(t, app.ApplyNameFrom())
+	require.Equal(t, "shop", app.Name)
+	git(repo, "checkout", "--quiet", "-b", "hotfix")
+	require.NoError(t, app.ApplyNameFrom())
+	require.Equal(t, "shop-hotfix", app.Name)
+
+	app = &DdevApp{Name: "shop", AppRoot: t.TempDir(), NameFrom: NameFromGitBranch}
+	require.Error(t, app.ApplyNameFrom())
+	app.NameFrom = "branch"
+	require.ErrorContains(t, app.ApplyNameFrom(), "invalid name_from")
+}
+
+// TestCheckExistingAppInApprootNameFrom checks that switching branches in
+// the same directory with name_from replaces the project of the previous
+// branch instead of failing
+func TestCheckExistingAppInApprootNameFrom(t *testing.T) {
+	repo := t.TempDir()
+	git := func(args ...string) {
+		out, err := ddevexec.RunHostCommand("git", append([]string{"-C", repo, "-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		require.NoError(t, err, out)
+	}
+	git("init", "--quiet", "--initial-branch=main")
+	git("commit", "--quiet", "--allow-empty", "-m", "initial")
+	t.Cleanup(func() {
+		for _, name := range []string{"tnamefrom", "tnamefrom-feature", "unrelated"} {
+			_ = globalconfig.RemoveProjectInfo(name)
+		}
+	})
+
+	app := &DdevApp{Name: "tnamefrom", AppRoot: repo, NameFrom: NameFromGitBranch}
+	require.NoError(t, app.ApplyNameFrom())
+	require.NoError(t, globalconfig.SetProjectAppRoot(app.Name, repo))
+
+	git("checkout", "--quiet", "-b", "feature")
+	require.NoError(t, app.ApplyNameFrom())
+	require.Equal(t, "tnamefrom-feature", app.Name)
+	require.NoError(t, app.CheckExistingAppInApproot())
+	require.Nil(t, globalconfig.GetProject("tnamefrom"))
+	require.NoError(t, globalconfig.SetProjectAppRoot(app.Name, repo))
+
+	git("checkout", "--quiet", "main")
+	require.NoError(t, app.ApplyNameFrom())
+	require.Equal(t, "tnamefrom", app.Name)
+	require.NoError(t, app.CheckExistingAppInApproot())
+	require.Nil(t, globalconfig.GetProject("tnamefrom-feature"))
+
+	// Another project in the same directory is still an error
+	require.NoError(t, globalconfig.SetProjectAppRoot("unrelated", repo))
+	require.ErrorContains(t, app.CheckExistingAppInApproot(), "already contains a project named 'unrelated'")
+	app.NameFrom = ""
+	require.NoError(t, app.ApplyNameFrom())
+	require.NoError(t, globalconfig.RemoveProjectInfo("unrelated"))
+	require.NoError(t, globalconfig.SetProjectAppRoot("tnamefrom-feature", repo))
+	require.ErrorContains(t, app.CheckExistingAppInApproot(), "already contains a project named 'tnamefrom-feature'")
+}
@@ -0,0 +1,162 @@
+package ddevapp
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+
+	"github.com/ddev/ddev/pkg/fileutil"
+	"github.com/ddev/ddev/pkg/nodeps"
+)
+
+// PHPPool is an additional php-fpm pool running a different PHP version
+// in the web container, serving requests below Path.
+type PHPPool struct {
+	Version string `yaml:"version"`
+	Path    string `yaml:"path"`
+}
+
+// phpPoolPathRegex restricts pool paths to simple URL path prefixes, so they
+// can be used verbatim in nginx, Apache, and Caddy config.
+var phpPoolPathRegex = regexp.MustCompile(`^(/[A-Za-z0-9._~-]+)+$`)
+
+// Socket returns the in-container php-fpm socket for the pool
+func (p PHPPool) Socket() string {
+	return PHPPoolSocket(p.Version)
+}
+
+// PHPPoolSocket returns the in-container php-fpm socket used by the
+// additional pool for the given PHP version
+func PHPPoolSocket(version string) string {
+	return fmt.Sprintf("/run/php/php%s-fpm.sock", version)
+}
+
+// PHPPoolBinDir returns the in-container directory holding the php binary
+// for the given PHP version, which can be prepended to $PATH so that
+// `php` resolves to that version
+func PHPPoolBinDir(version string) string {
+	return fmt.Sprintf("/usr/local/lib/ddev/php%s/bin", version)
+}
+
+// GetPHPPoolVersions returns the unique PHP versions used by php_pools,
+// excluding the project's main php_version
+func (app *DdevApp) GetPHPPoolVersions() []string {
+	var versions []string
+	for _, pool := range app.PHPPools {
+		if pool.Version != app.PHPVersion && !slices.Contains(versions, pool.Version) {
+			versions = append(versions, pool.Version)
+		}
+	}
+	return versions
+}
+
+// GetPHPBinDir returns the directory to prepend to $PATH to run the given
+// PHP version, or an empty string for the project's main php_version
+func (app *DdevApp) GetPHPBinDir(version string) (string, error) {
+	if version == "" || version == app.PHPVersion {
+		return "", nil
+	}
+	if !slices.Contains(app.GetPHPPoolVersions(), version) {
+		return "", fmt.Errorf("PHP %s is not available in the %s project, use php_version: %s or one of the php_pools versions: %v", version, app.Name, app.PHPVersion, app.GetPHPPoolVersions())
+	}
+	return PHPPoolBinDir(version), nil
+}
+
+// validatePHPPools checks the php_pools configuration
+func (app *DdevApp) validatePHPPools() error {
+	if len(app.PHPPools) > 0 && !slices.Contains([]string{nodeps.WebserverNginxFPM, nodeps.WebserverApacheFPM, nodeps.WebserverCaddyFPM}, app.GetWebserverType()) {
+		return fmt.Errorf("the %s project has php_pools, but they can only be used with webserver_type: %s, %s, or %s", app.Name, nodeps.WebserverNginxFPM, nodeps.WebserverApacheFPM, nodeps.WebserverCaddyFPM)
+	}
+	usedPaths := make(map[string]bool)
+	for _, pool := range app.PHPPools {
+		if !nodeps.IsValidPHPVersion(pool.Version) {
+			return fmt.Errorf("the %s project has an unsupported 'version: %s' for 'path: %s' in php_pools, DDEV only supports the following versions: %v", app.Name, pool.Version, pool.Path, nodeps.GetValidPHPVersions())
+		}
+		if pool.Version == app.PHPVersion {
+			return fmt.Errorf("the %s project has 'version: %s' for 'path: %s' in php_pools, which is the same as php_version, remove it from php_pools", app.Name, pool.Version, pool.Path)
+		}
+		if !phpPoolPathRegex.MatchString(pool.Path) {
+			return fmt.Errorf("the %s project has an invalid 'path: %s' in php_pools, it must be a URL path prefix like '/legacy' without a trailing slash", app.Name, pool.Path)
+		}
+		if usedPaths[pool.Path] {
+			return fmt.Errorf("the %s project has a duplicate 'path: %s' in php_pools", app.Name, pool.Path)
+		}
+		usedPaths[pool.Path] = true
+	}
+	for _, daemon := range app.WebExtraDaemons {
+		if _, err := app.GetPHPBinDir(daemon.PHPVersion); err != nil {
+			return fmt.Errorf("the %s project has an invalid 'php_version: %s' for 'name: %s' in web_extra_daemons: %v", app.Name, daemon.PHPVersion, daemon.Name, err)
+		}
+	}
+	return nil
+}
+
+// phpPoolsBuildContent writes the supervisord config for each additional
+// php-fpm pool into .webimageBuild and returns the Dockerfile content that
+// installs and configures the pool PHP versions
+func (app *DdevApp) phpPoolsBuildContent() (string, error) {
+	var content string
+	for _, version := range app.GetPHPPoolVersions() {
+		program := "php-fpm" + version
+		supervisorConf := fmt.Sprintf(`[program:%s]
+command = /usr/sbin/php-fpm%s --nodaemonize --force-stderr --allow-to-run-as-root
+priority=5
+stdout_logfile=/var/tmp/logpipe
+stdout_logfile_maxbytes=0
+redirect_stderr=true
+autorestart=true
+startretries=3
+`, program, version)
+		err := os.WriteFile(app.GetConfigPath(fmt.Sprintf(".webimageBuild/%s.conf", program)), []byte(supervisorConf), 0755)
+		if err != nil {
+			return "", fmt.Errorf("failed to write .webimageBuild/%s.conf: %v", program, err)
+		}
+
+		content = content + fmt.Sprintf("\n### DDEV-injected php_pools PHP %s\n", version)
+		if _, ok := nodeps.PreinstalledPHPVersions[version]; !ok {
+			content = content + fmt.Sprintf("RUN /usr/local/bin/install_php_extensions.sh \"php%s\" \"${TARGETARCH}\"\n", version)
+		}
+		binDir := PHPPoolBinDir(version)
+		content = content + fmt.Sprintf(`RUN sed -i 's#^listen = .*#listen = %s#' /etc/php/%s/fpm/pool.d/www.conf && mkdir -p %s && ln -sf /usr/bin/php%s %s/php && chmod -fR ugo+w /etc/php/%s
+ADD %s.conf /etc/supervisor/conf.d
+RUN chmod 644 /etc/supervisor/conf.d/%s.conf
+`, PHPPoolSocket(version), version, binDir, version, binDir, version, program, program)
+	}
+	return content, nil
+}
+
+// GeneratePHPPoolsConfig writes .ddev/nginx/php-pools.conf, which routes each
+// php_pools path to its php-fpm pool. Apache and Caddy route the pools
+// directly in their generated site config.
+func (app *DdevApp) GeneratePHPPoolsConfig() error {
+	configPath := app.GetConfigPath(filepath.Join("nginx", "php-pools.conf"))
+	enabled := len(app.PHPPools) > 0 && app.GetWebserverType() == nodeps.WebserverNginxFPM
+	if fileutil.FileExists(configPath) {
+		sigExists, err := fileutil.FgrepStringInFile(configPath, nodeps.DdevFileSignature)
+		if err != nil {
+			return err
+		}
+		// If the signature doesn't exist, they have taken over the file, so skip it
+		if !sigExists {
+			return nil
+		}
+		if !enabled {
+			return os.Remove(configPath)
+		}
+	}
+	if !enabled {
+		return nil
+	}
+
+	c, err := webserverConfigAssets.ReadFile("webserver_config_assets/nginx-php-pools.conf")
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(filepath.Dir(configPath), 0755)
+	if err != nil {
+		return err
+	}
+	return fileutil.TemplateStringToFile(string(c), map[string]any{"PHPPools": app.PHPPools}, configPath)
+}
@@ -0,0 +1,58 @@
+package ddevapp
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/ddev/ddev/pkg/dockerutil"
+	"github.com/ddev/ddev/pkg/globalconfig"
+)
+
+// CheckPolicy returns an error describing every way the project or this
+// machine violates the organization policy, see globalconfig.Policy
+func (app *DdevApp) CheckPolicy() error {
+	policy := globalconfig.DdevPolicy
+	if policy == nil {
+		return nil
+	}
+	var violations []string
+	if policy.DownloadError != nil {
+		violations = append(violations, fmt.Sprintf("the policy couldn't be downloaded from %s: %v", policy.URL, policy.DownloadError))
+	}
+	if policy.DdevVersionConstraint != "" {
+		if err := CheckDdevVersionConstraint(policy.DdevVersionConstraint, "ddev_version_constraint", ""); err != nil {
+			violations = append(violations, err.Error())
+		}
+	}
+	violations = append(violations, globalconfig.GetPolicyConflicts()...)
+
+	for _, provider := range []string{globalconfig.DdevGlobalConfig.ShareDefaultProvider, app.ShareDefaultProvider} {
+		if provider != "" && !policy.AllowsShareProvider(provider) {
+			violations = append(violations, fmt.Sprintf("share_default_provider '%s' is not allowed, use one of %s", provider, strings.Join(policy.AllowedShareProviders, ", ")))
+		}
+	}
+
+	if len(policy.RequiredRegistryMirrors) > 0 {
+		info, err := dockerutil.GetDockerClientInfo()
+		if err != nil {
+			return err
+		}
+		var mirrors []string
+		if info.RegistryConfig != nil {
+			for _, m := range info.RegistryConfig.Mirrors {
+				mirrors = append(mirrors, strings.TrimSuffix(m, "/"))
+			}
+		}
+		for _, required := range policy.RequiredRegistryMirrors {
+			if !slices.Contains(mirrors, strings.TrimSuffix(required, "/")) {
+				violations = append(violations, fmt.Sprintf("the Docker provider must use the registry mirror %s, add it to \"registry-mirrors\" in its daemon.json", required))
+			}
+		}
+	}
+
+	if len(violations) == 0 {
+		return nil
+	}
+	return fmt.Errorf("unable to start the '%s' project because of the organization policy in %s:\n  - %s", app.Name, policy.Source, strings.Join(violations, "\n  - "))
+}
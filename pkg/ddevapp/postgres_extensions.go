@@ -0,0 +1,240 @@
+package ddevapp
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/ddev/ddev/pkg/nodeps"
+)
+
+// postgresExtension describes what DDEV needs to provide a Postgres extension
+type postgresExtension struct {
+	name     string   // name for CREATE EXTENSION, if different from the database.extensions name
+	packages []string // Debian packages, %s is replaced by the Postgres major version
+	preload  string   // library to add to shared_preload_libraries
+}
+
+// postgresExtensions are the extensions that need more than CREATE EXTENSION.
+// Extensions not listed here, like hstore, pg_trgm or uuid-ossp, ship with
+// the Postgres server and are only created.
+var postgresExtensions = map[string]postgresExtension{
+	"hypopg":             {packages: []string{"postgresql-%s-hypopg"}},
+	"pg_cron":            {packages: []string{"postgresql-%s-cron"}, preload: "pg_cron"},
+	"pg_partman":         {packages: []string{"postgresql-%s-partman"}},
+	"pg_stat_statements": {preload: "pg_stat_statements"},
+	"pgaudit":            {packages: []string{"postgresql-%s-pgaudit"}, preload: "pgaudit"},
+	"pgrouting":          {packages: []string{"postgresql-%s-pgrouting"}},
+	"pgvector":           {name: "vector", packages: []string{"postgresql-%s-pgvector"}},
+	"postgis":            {packages: []string{"postgresql-%s-postgis-3", "postgresql-%s-postgis-3-scripts"}},
+	"vector":             {packages: []string{"postgresql-%s-pgvector"}},
+}
+
+var (
+	postgresExtensionNameRegex = regexp.MustCompile(`^[a-z0-9_-]+$`)
+	postgresSettingNameRegex   = regexp.MustCompile(`^[a-z][a-z0-9_]*(\.[a-z][a-z0-9_]*)?$`)
+	postgresConfAssignRegex    = regexp.MustCompile(`^\s*([a-zA-Z][a-zA-Z0-9_.]*)\s*=?\s*`)
+)
+
+// PostgresExtensionConfigFile is the file in /etc/postgresql/conf.d that holds
+// database.settings and the shared_preload_libraries needed by database.extensions
+const PostgresExtensionConfigFile = "ddev-database.conf"
+
+// validateDatabaseExtensions checks database.extensions and database.settings
+func (app *DdevApp) validateDatabaseExtensions() error {
+	if len(app.Database.Extensions) == 0 && len(app.Database.Settings) == 0 {
+		return nil
+	}
+	if app.Database.Type != nodeps.Postgres {
+		return fmt.Errorf("the %s project has database.extensions or database.settings, but they can only be used with database type %s", app.Name, nodeps.Postgres)
+	}
+	for _, ext := range app.Database.Extensions {
+		if !postgresExtensionNameRegex.MatchString(ext) {
+			return fmt.Errorf("the %s project has an invalid extension '%s' in database.extensions", app.Name, ext)
+		}
+	}
+	for key := range app.Database.Settings {
+		if !postgresSettingNameRegex.MatchString(key) {
+			return fmt.Errorf("the %s project has an invalid setting '%s' in database.settings", app.Name, key)
+		}
+	}
+	return nil
+}
+
+// GetPostgresExtensionPackages returns the Debian packages that provide database.extensions
+func (app *DdevApp) GetPostgresExtensionPackages() []string {
+	version := app.Database.Version
+	if version == nodeps.Postgres9 {
+		version = "9.6"
+	}
+	var packages []string
+	for _, ext := range app.Database.Extensions {
+		for _, p := range postgresExtensions[ext].packages {
+			p = fmt.Sprintf(p, version)
+			if !slices.Contains(packages, p) {
+				packages = append(packages, p)
+			}
+		}
+	}
+	return packages
+}
+
+// GetPostgresPreloadLibraries returns shared_preload_libraries for the project,
+// combining the database.settings value with the libraries database.extensions need
+func (app *DdevApp) GetPostgresPreloadLibraries() []string {
+	var libs []string
+	for _, lib := range strings.Split(app.Database.Settings["shared_preload_libraries"], ",") {
+		lib = strings.Trim(strings.TrimSpace(lib), `'"`)
+		if lib != "" && !slices.Contains(libs, lib) {
+			libs = append(libs, lib)
+		}
+	}
+	for _, ext := range app.Database.Extensions {
+		if lib := postgresExtensions[ext].preload; lib != "" && !slices.Contains(libs, lib) {
+			libs = append(libs, lib)
+		}
+	}
+	return libs
+}
+
+// getPostgresExtensionNames returns the names used in CREATE EXTENSION for database.extensions
+func (app *DdevApp) getPostgresExtensionNames() []string {
+	var names []string
+	for _, ext := range app.Database.Extensions {
+		if name := postgresExtensions[ext].name; name != "" {
+			ext = name
+		}
+		if !slices.Contains(names, ext) {
+			names = append(names, ext)
+		}
+	}
+	return names
+}
+
+// getPostgresManagedSettings returns all server settings DDEV manages from
+// database.extensions and database.settings
+func (app *DdevApp) getPostgresManagedSettings() map[string]string {
+	settings := make(map[string]string)
+	for key, value := range app.Database.Settings {
+		settings[key] = value
+	}
+	if libs := app.GetPostgresPreloadLibraries(); len(libs) > 0 {
+		settings["shared_preload_libraries"] = strings.Join(libs, ",")
+	}
+	// pg_cron only schedules jobs in one database, use the project database by default
+	if slices.Contains(app.Database.Extensions, "pg_cron") {
+		if _, ok := settings["cron.database_name"]; !ok {
+			settings["cron.database_name"] = "db"
+		}
+	}
+	return settings
+}
+
+// postgresExtensionConfig returns the contents of PostgresExtensionConfigFile,
+// or an empty string if there is nothing to configure
+func (app *DdevApp) postgresExtensionConfig() string {
+	settings := app.getPostgresManagedSettings()
+	if len(settings) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(settings))
+	for key := range settings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteString("# Generated from database.extensions and database.settings in .ddev/config.yaml\n")
+	b.WriteString(nodeps.DdevFileSignature + "\n")
+	for _, key := range keys {
+		value := strings.Trim(strings.TrimSpace(settings[key]), `'`)
+		_, _ = fmt.Fprintf(&b, "%s = '%s'\n", key, strings.ReplaceAll(value, `'`, `''`))
+	}
+	return b.String()
+}
+
+// postgresExtensionsBuildContent writes PostgresExtensionConfigFile into
+// .dbimageBuild and returns the Dockerfile content that installs the
+// extension packages and adds the config to /etc/postgresql/conf.d
+func (app *DdevApp) postgresExtensionsBuildContent() (string, error) {
+	if app.Database.Type != nodeps.Postgres {
+		return "", nil
+	}
+	var content string
+	if packages := app.GetPostgresExtensionPackages(); len(packages) > 0 {
+		content = content + fmt.Sprintf(`
+### DDEV-injected database.extensions packages
+RUN (timeout %d apt-get update || true) && DEBIAN_FRONTEND=noninteractive apt-get install -y -o Dpkg::Options::="--force-confold" --no-install-recommends --no-install-suggests %s
+`, app.GetMaxContainerWaitTime(), strings.Join(packages, " "))
+	}
+	if conf := app.postgresExtensionConfig(); conf != "" {
+		err := os.WriteFile(app.GetConfigPath(".dbimageBuild/"+PostgresExtensionConfigFile), []byte(conf), 0644)
+		if err != nil {
+			return "", fmt.Errorf("failed to write .dbimageBuild/%s: %v", PostgresExtensionConfigFile, err)
+		}
+		content = content + fmt.Sprintf(`
+### DDEV-injected database.settings
+ADD %[1]s /etc/postgresql/conf.d
+RUN chmod 644 /etc/postgresql/conf.d/%[1]s
+`, PostgresExtensionConfigFile)
+	}
+	return content, nil
+}
+
+// CreatePostgresExtensions runs CREATE EXTENSION for database.extensions in
+// every database that accepts connections, including template1 so databases
+// created later get them as well
+func (app *DdevApp) CreatePostgresExtensions() error {
+	if app.Database.Type != nodeps.Postgres || app.IsDBOmitted() {
+		return nil
+	}
+	names := app.getPostgresExtensionNames()
+	if len(names) == 0 {
+		return nil
+	}
+	var sql strings.Builder
+	for _, name := range names {
+		_, _ = fmt.Fprintf(&sql, `CREATE EXTENSION IF NOT EXISTS \"%s\" CASCADE; `, name)
+	}
+	_, stderr, err := app.Exec(&ExecOpts{
+		Service: "db",
+		Cmd: fmt.Sprintf(`set -eu -o pipefail
+for database in $(psql -t -A -d postgres -c "SELECT datname FROM pg_database WHERE datallowconn"); do
+  psql -q -v ON_ERROR_STOP=1 -d "${database}" -c "%s" >/dev/null
+done`, sql.String()),
+	})
+	if err != nil {
+		return fmt.Errorf("%v: %s", err, stderr)
+	}
+	return nil
+}
+
+// findPostgresSettingConflicts returns the keys of settings that are
+// assigned in the Postgres config file confPath
+func findPostgresSettingConflicts(confPath string, settings map[string]string) ([]string, error) {
+	f, err := os.Open(confPath)
+	if err != nil {
+		return nil, err
+	}
+	// nolint: errcheck
+	defer f.Close()
+
+	var conflicts []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		m := postgresConfAssignRegex.FindStringSubmatch(scanner.Text())
+		if m == nil {
+			continue
+		}
+		key := strings.ToLower(m[1])
+		if _, ok := settings[key]; ok && !slices.Contains(conflicts, key) {
+			conflicts = append(conflicts, key)
+		}
+	}
+	sort.Strings(conflicts)
+	return conflicts, scanner.Err()
+}
@@ -0,0 +1,66 @@
+package ddevapp
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ddev/ddev/pkg/nodeps"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPostgresExtensionConfig checks the packages, preload libraries and
+// generated config for database.extensions and database.settings
+func TestPostgresExtensionConfig(t *testing.T) {
+	app := &DdevApp{Name: "pgext", Database: DatabaseDesc{Type: nodeps.Postgres, Version: nodeps.Postgres9}}
+	require.NoError(t, app.validateDatabaseExtensions())
+	require.Empty(t, app.postgresExtensionConfig())
+
+	app.Database.Extensions = []string{"postgis", "pgvector", "pg_cron", "hstore", "vector"}
+	app.Database.Settings = map[string]string{
+		"shared_buffers":           "256MB",
+		"shared_preload_libraries": "auto_explain",
+		"auto_explain.log_format":  "it's json",
+	}
+	require.NoError(t, app.validateDatabaseExtensions())
+
+	require.Equal(t, []string{"postgresql-9.6-postgis-3", "postgresql-9.6-postgis-3-scripts", "postgresql-9.6-pgvector", "postgresql-9.6-cron"}, app.GetPostgresExtensionPackages())
+	require.Equal(t, []string{"auto_explain", "pg_cron"}, app.GetPostgresPreloadLibraries())
+	require.Equal(t, []string{"postgis", "vector", "pg_cron", "hstore"}, app.getPostgresExtensionNames())
+	require.Equal(t, "# Generated from database.extensions and database.settings in .ddev/config.yaml\n"+
+		nodeps.DdevFileSignature+"\n"+
+		"auto_explain.log_format = 'it''s json'\n"+
+		"cron.database_name = 'db'\n"+
+		"shared_buffers = '256MB'\n"+
+		"shared_preload_libraries = 'auto_explain,pg_cron'\n", app.postgresExtensionConfig())
+
+	app.Database.Settings = map[string]string{"Bad Key": "1"}
+	require.ErrorContains(t, app.validateDatabaseExtensions(), "invalid setting 'Bad Key'")
+	app.Database.Settings = nil
+	app.Database.Extensions = []string{"postgis; DROP"}
+	require.ErrorContains(t, app.validateDatabaseExtensions(), "invalid extension")
+	app.Database = DatabaseDesc{Type: nodeps.MariaDB, Version: nodeps.MariaDBDefaultVersion, Extensions: []string{"postgis"}}
+	require.ErrorContains(t, app.validateDatabaseExtensions(), "can only be used with database type postgres")
+}
+
+// TestFindPostgresSettingConflicts checks that only uncommented settings in a
+// custom postgresql.conf are reported
+func TestFindPostgresSettingConflicts(t *testing.T) {
+	confPath := filepath.Join(t.TempDir(), "postgresql.conf")
+	require.NoError(t, os.WriteFile(confPath, []byte(`# custom config
+#shared_buffers = 128MB
+shared_buffers = 512MB
+  Work_Mem=64MB
+max_connections = 100
+shared_preload_libraries = 'pg_stat_statements'	# (change requires restart)
+`), 0644))
+
+	conflicts, err := findPostgresSettingConflicts(confPath, map[string]string{
+		"shared_buffers":           "256MB",
+		"work_mem":                 "16MB",
+		"shared_preload_libraries": "pg_cron",
+		"effective_cache_size":     "1GB",
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"shared_buffers", "shared_preload_libraries", "work_mem"}, conflicts)
+}
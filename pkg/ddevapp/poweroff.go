@@ -1,7 +1,9 @@
 package ddevapp
 
 import (
+	"github.com/ddev/ddev/pkg/dbgpproxy"
 	"github.com/ddev/ddev/pkg/dockerutil"
+	"github.com/ddev/ddev/pkg/idlemanager"
 	"github.com/ddev/ddev/pkg/util"
 )
 
@@ -36,6 +38,14 @@ func PowerOff() {
 
 	StopMutagenDaemon("")
 
+	if err := dbgpproxy.Stop(); err != nil {
+		util.Warning("Failed to stop the Xdebug proxy: %v", err)
+	}
+
+	if err := idlemanager.Stop(); err != nil {
+		util.Warning("Failed to stop the idle manager: %v", err)
+	}
+
 	// Clean up Traefik staging directories after all projects are stopped
 	// This prevents issues when downgrading DDEV versions
 	if err := CleanupGlobalTraefikStaging(); err != nil {
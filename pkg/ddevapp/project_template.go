@@ -0,0 +1,237 @@
+package ddevapp
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	ddevexec "github.com/ddev/ddev/pkg/exec"
+	"github.com/ddev/ddev/pkg/fileutil"
+	"github.com/otiai10/copy"
+	"go.yaml.in/yaml/v4"
+)
+
+// ProjectTemplateFileName is the manifest of a template for 'ddev new'
+const ProjectTemplateFileName = "ddev-template.yaml"
+
+// ProjectTemplate is the manifest of a template for 'ddev new', a git
+// repository or directory with everything needed to create a project
+type ProjectTemplate struct {
+	Name                  string `yaml:"name"`
+	Description           string `yaml:"description,omitempty"`
+	DdevVersionConstraint string `yaml:"ddev_version_constraint,omitempty"`
+	// Config holds .ddev/config.yaml values, they win over the defaults of the project type
+	Config map[string]any `yaml:"config,omitempty"`
+	// AddOns are installed with 'ddev add-on get' before the project starts
+	AddOns []string `yaml:"add_ons,omitempty"`
+	// Files is a directory of the template copied into the project, "files" by default
+	Files  string                `yaml:"files,omitempty"`
+	Create ProjectTemplateCreate `yaml:"create,omitempty"`
+	// Database is a dump imported with 'ddev import-db'
+	Database string `yaml:"database,omitempty"`
+	// Snapshot is a 'ddev snapshot' file restored into the database
+	Snapshot string `yaml:"snapshot,omitempty"`
+	// PostCreate are commands run in the web container at the end
+	PostCreate []string `yaml:"post_create,omitempty"`
+
+	// Dir is the directory the template was read from
+	Dir string `yaml:"-"`
+}
+
+// ProjectTemplateCreate is the way a template creates the code of the
+// project, at most one of the fields is set
+type ProjectTemplateCreate struct {
+	// Composer holds the arguments of 'ddev composer create-project'
+	Composer string `yaml:"composer,omitempty"`
+	// Git is a repository cloned into the project directory before it's configured
+	Git string `yaml:"git,omitempty"`
+	// Exec is a command run in the web container once the project started
+	Exec string `yaml:"exec,omitempty"`
+}
+
+// snapshotFileName matches the file names of 'ddev snapshot', the name of
+// the snapshot is the first group
+var snapshotFileName = regexp.MustCompile(`^(.+)-(mariadb|mysql|postgres)_[0-9.]*\.` + snapshotExtensionPattern + `$`)
+
+// FetchProjectTemplate returns the directory of a template, which is source
+// if it's a directory, or a shallow clone of the git repository source, a
+// URL or a GitHub owner/repo. The returned cleanup removes the clone.
+func FetchProjectTemplate(source string) (string, func(), error) {
+	noCleanup := func() {}
+	if fileutil.IsDirectory(source) {
+		dir, err := filepath.Abs(source)
+		return dir, noCleanup, err
+	}
+
+	repoURL := source
+	if !strings.Contains(source, "://") && !strings.HasPrefix(source, "git@") {
+		parts := strings.Split(source, "/")
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return "", noCleanup, fmt.Errorf("template '%s' is neither a directory, a git URL nor a GitHub owner/repo", source)
+		}
+		repoURL = "https://github.com/" + source + ".git"
+	}
+
+	tmpDir, err := os.MkdirTemp("", "ddev-template-")
+	if err != nil {
+		return "", noCleanup, err
+	}
+	cleanup := func() {
+		_ = os.RemoveAll(tmpDir)
+	}
+	out, err := ddevexec.RunHostCommand("git", "clone", "--depth", "1", "--quiet", repoURL, tmpDir)
+	if err != nil {
+		cleanup()
+		return "", noCleanup, fmt.Errorf("unable to clone template %s: %v, output=%s", repoURL, err, strings.TrimSpace(out))
+	}
+	return tmpDir, cleanup, nil
+}
+
+// ReadProjectTemplate reads and validates the manifest of the template in dir
+func ReadProjectTemplate(dir string) (*ProjectTemplate, error) {
+	manifest := filepath.Join(dir, ProjectTemplateFileName)
+	content, err := os.ReadFile(manifest)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the template manifest: %v", err)
+	}
+	t := &ProjectTemplate{}
+	if err = yaml.Unmarshal(content, t); err != nil {
+		return nil, fmt.Errorf("unable to parse %s: %v", manifest, err)
+	}
+	t.Dir = dir
+	if err = t.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid %s: %v", manifest, err)
+	}
+	return t, nil
+}
+
+// Validate checks that the manifest is consistent and that the files it
+// names are in the template
+func (t *ProjectTemplate) Validate() error {
+	creates := 0
+	for _, c := range []string{t.Create.Composer, t.Create.Git, t.Create.Exec} {
+		if c != "" {
+			creates++
+		}
+	}
+	if creates > 1 {
+		return errors.New("create can only have one of composer, git and exec")
+	}
+	if t.Database != "" && t.Snapshot != "" {
+		return errors.New("database and snapshot can't both be set")
+	}
+	if t.Snapshot != "" && !snapshotFileName.MatchString(filepath.Base(t.Snapshot)) {
+		return fmt.Errorf("snapshot %s isn't a file created by 'ddev snapshot', like name-mariadb_10.11.gz", t.Snapshot)
+	}
+	if _, ok := t.Config["name"]; ok {
+		return errors.New("config can't set the name, it comes from the project directory or --project-name")
+	}
+	for _, f := range []string{t.Database, t.Snapshot} {
+		if f == "" {
+			continue
+		}
+		path, err := t.path(f)
+		if err != nil {
+			return err
+		}
+		if !fileutil.FileExists(path) {
+			return fmt.Errorf("%s doesn't exist in the template", f)
+		}
+	}
+	if t.Files != "" {
+		path, err := t.path(t.Files)
+		if err != nil {
+			return err
+		}
+		if !fileutil.IsDirectory(path) {
+			return fmt.Errorf("files directory %s doesn't exist in the template", t.Files)
+		}
+	}
+	return nil
+}
+
+// path returns the absolute path of a file of the template, which can't be
+// outside of it
+func (t *ProjectTemplate) path(rel string) (string, error) {
+	if filepath.IsAbs(rel) {
+		return "", fmt.Errorf("%s must be relative to the template directory", rel)
+	}
+	path := filepath.Join(t.Dir, rel)
+	if r, err := filepath.Rel(t.Dir, path); err != nil || r == ".." || strings.HasPrefix(r, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%s is outside of the template directory", rel)
+	}
+	return path, nil
+}
+
+// CopyFiles copies sub, a path in the files directory of the template, or
+// the whole directory if sub is empty, to the same path in the project
+// directory, replacing existing files
+func (t *ProjectTemplate) CopyFiles(projectDir string, sub string) error {
+	files := t.Files
+	if files == "" {
+		files = "files"
+	}
+	src, err := t.path(filepath.Join(files, sub))
+	if err != nil {
+		return err
+	}
+	if !fileutil.IsDirectory(src) {
+		return nil
+	}
+	return copy.Copy(src, filepath.Join(projectDir, sub))
+}
+
+// ApplyConfig sets the config values of the template on app. They're set
+// before and after the overrides of the project type, so the type can be
+// set by the template and its other values win.
+func (t *ProjectTemplate) ApplyConfig(app *DdevApp) error {
+	if len(t.Config) == 0 {
+		return app.ConfigFileOverrideAction(false)
+	}
+	content, err := yaml.Marshal(t.Config)
+	if err != nil {
+		return err
+	}
+	if err = yaml.Unmarshal(content, app); err != nil {
+		return fmt.Errorf("invalid config in the template: %v", err)
+	}
+	if err = app.ConfigFileOverrideAction(false); err != nil {
+		return err
+	}
+	return yaml.Unmarshal(content, app)
+}
+
+// DatabasePath returns the absolute path of the database dump of the
+// template, or an empty string if it has none
+func (t *ProjectTemplate) DatabasePath() string {
+	if t.Database == "" {
+		return ""
+	}
+	path, _ := t.path(t.Database)
+	return path
+}
+
+// InstallSnapshot copies the snapshot of the template into the snapshots of
+// the project and returns its name for RestoreSnapshot, or an empty string
+// if the template has none
+func (t *ProjectTemplate) InstallSnapshot(app *DdevApp) (string, error) {
+	if t.Snapshot == "" {
+		return "", nil
+	}
+	src, err := t.path(t.Snapshot)
+	if err != nil {
+		return "", err
+	}
+	snapshotDir := app.GetConfigPath("db_snapshots")
+	if err = os.MkdirAll(snapshotDir, 0755); err != nil {
+		return "", err
+	}
+	base := filepath.Base(src)
+	if err = fileutil.CopyFile(src, filepath.Join(snapshotDir, base)); err != nil {
+		return "", err
+	}
+	return snapshotFileName.FindStringSubmatch(base)[1], nil
+}
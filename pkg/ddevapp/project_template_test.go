@@ -0,0 +1,81 @@
+package ddevapp
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// writeTemplateFiles creates files, relative path to content, in dir
+func writeTemplateFiles(t *testing.T, dir string, files map[string]string) {
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+}
+
+// TestReadProjectTemplate checks reading and validating template manifests
+func TestReadProjectTemplate(t *testing.T) {
+	dir := t.TempDir()
+	writeTemplateFiles(t, dir, map[string]string{
+		ProjectTemplateFileName: `name: drupal-starter
+config:
+  type: drupal11
+  docroot: web
+add_ons: [ddev/ddev-redis]
+create:
+  composer: drupal/recommended-project
+snapshot: seed/starter-mariadb_10.11.gz
+post_create:
+  - drush site:install -y
+`,
+		"seed/starter-mariadb_10.11.gz": "",
+	})
+	tmpl, err := ReadProjectTemplate(dir)
+	require.NoError(t, err)
+	require.Equal(t, "drupal-starter", tmpl.Name)
+	require.Equal(t, "drupal11", tmpl.Config["type"])
+	require.Equal(t, []string{"ddev/ddev-redis"}, tmpl.AddOns)
+	require.Equal(t, "drupal/recommended-project", tmpl.Create.Composer)
+	require.Equal(t, []string{"drush site:install -y"}, tmpl.PostCreate)
+
+	for manifest, expectedErr := range map[string]string{
+		"name: x\ncreate:\n  composer: a/b\n  exec: npm init\n": "only have one of",
+		"name: x\ndatabase: db.sql.gz\n":                        "doesn't exist in the template",
+		"name: x\ndatabase: ../db.sql.gz\n":                     "outside of the template",
+		"name: x\nsnapshot: seed.sql.gz\n":                      "isn't a file created by 'ddev snapshot'",
+		"name: x\nconfig:\n  name: other\n":                     "can't set the name",
+		"name: x\nfiles: missing\n":                             "files directory missing",
+	} {
+		writeTemplateFiles(t, dir, map[string]string{ProjectTemplateFileName: manifest})
+		_, err = ReadProjectTemplate(dir)
+		require.ErrorContains(t, err, expectedErr, manifest)
+	}
+}
+
+// TestProjectTemplateCopyFiles checks copying the files of a template
+func TestProjectTemplateCopyFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeTemplateFiles(t, dir, map[string]string{
+		"files/.ddev/config.local.yaml": "php_version: \"8.3\"\n",
+		"files/web/settings.php":        "<?php // template\n",
+	})
+	tmpl := &ProjectTemplate{Dir: dir}
+	projectDir := t.TempDir()
+
+	require.NoError(t, tmpl.CopyFiles(projectDir, ".ddev"))
+	require.FileExists(t, filepath.Join(projectDir, ".ddev/config.local.yaml"))
+	require.NoFileExists(t, filepath.Join(projectDir, "web/settings.php"))
+
+	writeTemplateFiles(t, projectDir, map[string]string{"web/settings.php": "<?php // created\n"})
+	require.NoError(t, tmpl.CopyFiles(projectDir, ""))
+	content, err := os.ReadFile(filepath.Join(projectDir, "web/settings.php"))
+	require.NoError(t, err)
+	require.Equal(t, "<?php // template\n", string(content))
+
+	// Templates don't need a files directory
+	require.NoError(t, (&ProjectTemplate{Dir: t.TempDir()}).CopyFiles(projectDir, ""))
+}
@@ -0,0 +1,195 @@
+package ddevapp
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/ddev/ddev/pkg/dockerutil"
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/client"
+)
+
+// ResourceSortKeys are the valid values of SortProjectResources
+var ResourceSortKeys = []string{"name", "cpu", "memory", "disk"}
+
+// ServiceResources is the live CPU and memory use of a service container
+type ServiceResources struct {
+	Service string `json:"service"`
+	// CPUPercent is relative to one CPU, like `docker stats`
+	CPUPercent  float64 `json:"cpu_percent"`
+	MemoryBytes int64   `json:"memory_bytes"`
+	MemoryLimit int64   `json:"memory_limit"`
+}
+
+// ProjectResources is the resource use of a project, live for its running
+// services and on disk for its volumes and snapshots
+type ProjectResources struct {
+	Name          string             `json:"name"`
+	Status        string             `json:"status"`
+	CPUPercent    float64            `json:"cpu_percent"`
+	MemoryBytes   int64              `json:"memory_bytes"`
+	Services      []ServiceResources `json:"services,omitempty"`
+	VolumeBytes   int64              `json:"volume_bytes"`
+	MutagenBytes  int64              `json:"mutagen_bytes"`
+	SnapshotBytes int64              `json:"snapshot_bytes"`
+}
+
+// DiskBytes is the total on-disk size of the project's volumes and snapshots
+func (r ProjectResources) DiskBytes() int64 {
+	return r.VolumeBytes + r.MutagenBytes + r.SnapshotBytes
+}
+
+// GetProjectResources returns the resource use of the projects. Container
+// stats are sampled in parallel, which takes about a second.
+func GetProjectResources(apps []*DdevApp) ([]ProjectResources, error) {
+	ctx, apiClient, err := dockerutil.GetDockerClient()
+	if err != nil {
+		return nil, err
+	}
+	// Read the volume sizes once for all projects, like GetVolumeSize
+	// and GetAllMutagenVolumes do for one volume
+	volumeSizes, err := dockerutil.ParseDockerSystemDf()
+	if err != nil {
+		return nil, err
+	}
+
+	resources := make([]ProjectResources, len(apps))
+	var wg sync.WaitGroup
+	for i, app := range apps {
+		status, _ := app.SiteStatus()
+		r := &resources[i]
+		*r = ProjectResources{Name: app.Name, Status: status}
+
+		for name, size := range volumeSizes {
+			if app.isProjectVolume(name) {
+				r.VolumeBytes += size.SizeBytes
+			}
+		}
+		if size, ok := volumeSizes[GetMutagenVolumeName(app)]; ok {
+			r.MutagenBytes = size.SizeBytes
+		}
+		if snapshots, err := app.ListSnapshots(); err == nil {
+			for _, s := range snapshots {
+				r.SnapshotBytes += s.Size
+			}
+		}
+
+		containers, err := dockerutil.GetAppContainers(app.Name)
+		if err != nil {
+			return nil, err
+		}
+		var mu sync.Mutex
+		for _, c := range containers {
+			if c.State != container.StateRunning {
+				continue
+			}
+			service := c.Labels["com.docker.compose.service"]
+			wg.Go(func() {
+				stats, err := containerStats(ctx, apiClient, c.ID)
+				if err != nil {
+					return
+				}
+				s := ServiceResources{
+					Service:     service,
+					CPUPercent:  cpuPercent(stats),
+					MemoryBytes: memoryUsage(stats),
+					MemoryLimit: int64(stats.MemoryStats.Limit),
+				}
+				mu.Lock()
+				defer mu.Unlock()
+				r.Services = append(r.Services, s)
+				r.CPUPercent += s.CPUPercent
+				r.MemoryBytes += s.MemoryBytes
+			})
+		}
+	}
+	wg.Wait()
+
+	for i := range resources {
+		slices.SortFunc(resources[i].Services, func(a, b ServiceResources) int {
+			return strings.Compare(a.Service, b.Service)
+		})
+	}
+	return resources, nil
+}
+
+// isProjectVolume reports whether a volume holds data of the project, the
+// database, built-in services and compose volumes. The Mutagen volume is
+// counted separately.
+func (app *DdevApp) isProjectVolume(name string) bool {
+	if name == app.GetMariaDBVolumeName() || name == app.GetPostgresVolumeName() {
+		return true
+	}
+	for _, svc := range app.GetBuiltinServiceNames() {
+		if name == app.GetBuiltinServiceVolumeName(svc) {
+			return true
+		}
+	}
+	// Project names can't contain "_", so the prefix is unambiguous
+	return strings.HasPrefix(name, app.GetComposeProjectName()+"_") && name != GetMutagenVolumeName(app)
+}
+
+// containerStats samples the stats of a container twice to compute CPU usage
+func containerStats(ctx context.Context, apiClient client.APIClient, id string) (container.StatsResponse, error) {
+	var stats container.StatsResponse
+	res, err := apiClient.ContainerStats(ctx, id, client.ContainerStatsOptions{IncludePreviousSample: true})
+	if err != nil {
+		return stats, err
+	}
+	defer res.Body.Close()
+	err = json.NewDecoder(res.Body).Decode(&stats)
+	return stats, err
+}
+
+// cpuPercent computes the CPU usage between the two samples like `docker stats`
+func cpuPercent(stats container.StatsResponse) float64 {
+	cpuDelta := float64(stats.CPUStats.CPUUsage.TotalUsage) - float64(stats.PreCPUStats.CPUUsage.TotalUsage)
+	systemDelta := float64(stats.CPUStats.SystemUsage) - float64(stats.PreCPUStats.SystemUsage)
+	onlineCPUs := float64(stats.CPUStats.OnlineCPUs)
+	if onlineCPUs == 0 {
+		onlineCPUs = float64(len(stats.CPUStats.CPUUsage.PercpuUsage))
+	}
+	if cpuDelta <= 0 || systemDelta <= 0 {
+		return 0
+	}
+	return cpuDelta / systemDelta * onlineCPUs * 100
+}
+
+// memoryUsage returns the memory used without the page cache, like `docker stats`
+func memoryUsage(stats container.StatsResponse) int64 {
+	usage := stats.MemoryStats.Usage
+	// cgroup v1 reports total_inactive_file, cgroup v2 inactive_file
+	for _, key := range []string{"total_inactive_file", "inactive_file"} {
+		if v, ok := stats.MemoryStats.Stats[key]; ok && v < usage {
+			return int64(usage - v)
+		}
+	}
+	return int64(usage)
+}
+
+// SortProjectResources sorts by name, or by cpu, memory or disk use with
+// the largest first
+func SortProjectResources(resources []ProjectResources, by string) error {
+	var compare func(a, b ProjectResources) int
+	switch by {
+	case "", "name":
+		compare = func(a, b ProjectResources) int { return strings.Compare(a.Name, b.Name) }
+	case "cpu":
+		compare = func(a, b ProjectResources) int { return cmp.Compare(b.CPUPercent, a.CPUPercent) }
+	case "memory":
+		compare = func(a, b ProjectResources) int { return cmp.Compare(b.MemoryBytes, a.MemoryBytes) }
+	case "disk":
+		compare = func(a, b ProjectResources) int { return cmp.Compare(b.DiskBytes(), a.DiskBytes()) }
+	default:
+		return fmt.Errorf("invalid sort key '%s', valid keys are %s", by, strings.Join(ResourceSortKeys, ", "))
+	}
+	slices.SortStableFunc(resources, func(a, b ProjectResources) int {
+		return cmp.Or(compare(a, b), strings.Compare(a.Name, b.Name))
+	})
+	return nil
+}
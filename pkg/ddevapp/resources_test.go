@@ -0,0 +1,62 @@
+package ddevapp
+
+import (
+	"testing"
+
+	"github.com/moby/moby/api/types/container"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCPUPercent checks the CPU use computed from two stats samples
+func TestCPUPercent(t *testing.T) {
+	var stats container.StatsResponse
+	stats.PreCPUStats.CPUUsage.TotalUsage = 1000
+	stats.PreCPUStats.SystemUsage = 10000
+	stats.CPUStats.CPUUsage.TotalUsage = 1500
+	stats.CPUStats.SystemUsage = 20000
+	stats.CPUStats.OnlineCPUs = 4
+	require.InDelta(t, 20.0, cpuPercent(stats), 0.001)
+
+	// Without a previous sample there is no usage
+	stats.PreCPUStats = container.CPUStats{}
+	stats.CPUStats.SystemUsage = 0
+	require.Equal(t, 0.0, cpuPercent(stats))
+}
+
+// TestMemoryUsage checks that the page cache isn't counted as used memory
+func TestMemoryUsage(t *testing.T) {
+	stats := container.StatsResponse{MemoryStats: container.MemoryStats{Usage: 1000}}
+	require.Equal(t, int64(1000), memoryUsage(stats))
+
+	stats.MemoryStats.Stats = map[string]uint64{"inactive_file": 300}
+	require.Equal(t, int64(700), memoryUsage(stats))
+
+	stats.MemoryStats.Stats = map[string]uint64{"total_inactive_file": 200}
+	require.Equal(t, int64(800), memoryUsage(stats))
+}
+
+// TestSortProjectResources checks the resource sort orders
+func TestSortProjectResources(t *testing.T) {
+	resources := []ProjectResources{
+		{Name: "b", CPUPercent: 5, MemoryBytes: 10, VolumeBytes: 300},
+		{Name: "c", CPUPercent: 50, MemoryBytes: 10, SnapshotBytes: 100},
+		{Name: "a", CPUPercent: 5, MemoryBytes: 30, MutagenBytes: 200},
+	}
+	names := func() []string {
+		var n []string
+		for _, r := range resources {
+			n = append(n, r.Name)
+		}
+		return n
+	}
+
+	require.NoError(t, SortProjectResources(resources, "name"))
+	require.Equal(t, []string{"a", "b", "c"}, names())
+	require.NoError(t, SortProjectResources(resources, "cpu"))
+	require.Equal(t, []string{"c", "a", "b"}, names())
+	require.NoError(t, SortProjectResources(resources, "memory"))
+	require.Equal(t, []string{"a", "b", "c"}, names())
+	require.NoError(t, SortProjectResources(resources, "disk"))
+	require.Equal(t, []string{"b", "a", "c"}, names())
+	require.Error(t, SortProjectResources(resources, "size"))
+}
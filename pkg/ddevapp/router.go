@@ -16,6 +16,7 @@ import (
 	ddevImages "github.com/ddev/ddev/pkg/docker"
 	"github.com/ddev/ddev/pkg/dockerutil"
 	"github.com/ddev/ddev/pkg/globalconfig"
+	"github.com/ddev/ddev/pkg/idlemanager"
 	"github.com/ddev/ddev/pkg/netutil"
 	"github.com/ddev/ddev/pkg/nodeps"
 	"github.com/ddev/ddev/pkg/output"
@@ -262,25 +263,32 @@ func generateRouterCompose(activeApps []*DdevApp) (string, error) {
 		}
 	}
 
+	// The router only reaches the host for the idle manager
+	hostDockerInternalExtraHost := ""
+	if idlemanager.IsEnabled() {
+		hostDockerInternalExtraHost = dockerutil.GetHostDockerInternal().ExtraHost
+	}
+
 	templateVars := map[string]any{
-		"Username":                   username,
-		"UID":                        uid,
-		"GID":                        gid,
-		"router_image":               ddevImages.GetRouterImage(),
-		"ports":                      exposedPorts,
-		"router_bind_all_interfaces": globalconfig.DdevGlobalConfig.RouterBindAllInterfaces || dockerutil.IsRemoteDockerHost(),
-		"dockerIP":                   dockerIP,
-		"letsencrypt":                globalconfig.DdevGlobalConfig.UseLetsEncrypt,
-		"letsencrypt_email":          globalconfig.DdevGlobalConfig.LetsEncryptEmail,
-		"Router":                     globalconfig.DdevGlobalConfig.Router,
-		"TraefikMonitorPort":         globalconfig.DdevGlobalConfig.TraefikMonitorPort,
-		"Timezone":                   timezone,
-		"Hostnames":                  determineRouterHostnames(activeApps),
-		"IsPodman":                   dockerutil.IsPodman(),
-		"IsRootless":                 dockerutil.IsRootless(),
-		"UseKeepID":                  dockerutil.UseKeepID(),
-		"PortSubstitutionsLabel":     RouterPortSubstitutionsLabel,
-		"PortSubstitutions":          formatRouterPortSubstitutions(portSubstitutions),
+		"Username":                    username,
+		"UID":                         uid,
+		"GID":                         gid,
+		"router_image":                ddevImages.GetRouterImage(),
+		"ports":                       exposedPorts,
+		"router_bind_all_interfaces":  globalconfig.DdevGlobalConfig.RouterBindAllInterfaces || dockerutil.IsRemoteDockerHost(),
+		"dockerIP":                    dockerIP,
+		"letsencrypt":                 globalconfig.DdevGlobalConfig.UseLetsEncrypt,
+		"letsencrypt_email":           globalconfig.DdevGlobalConfig.LetsEncryptEmail,
+		"Router":                      globalconfig.DdevGlobalConfig.Router,
+		"TraefikMonitorPort":          globalconfig.DdevGlobalConfig.TraefikMonitorPort,
+		"Timezone":                    timezone,
+		"Hostnames":                   determineRouterHostnames(activeApps),
+		"IsPodman":                    dockerutil.IsPodman(),
+		"IsRootless":                  dockerutil.IsRootless(),
+		"UseKeepID":                   dockerutil.UseKeepID(),
+		"PortSubstitutionsLabel":      RouterPortSubstitutionsLabel,
+		"PortSubstitutions":           formatRouterPortSubstitutions(portSubstitutions),
+		"HostDockerInternalExtraHost": hostDockerInternalExtraHost,
 	}
 
 	t, err := template.New("router_compose_template.yaml").Funcs(getTemplateFuncMap()).ParseFS(bundledAssets, "router_compose_template.yaml")
@@ -0,0 +1,32 @@
+package ddevapp
+
+import (
+	"fmt"
+
+	composeTypes "github.com/compose-spec/compose-go/v2/types"
+	"github.com/ddev/ddev/pkg/secrets"
+)
+
+// resolveComposeSecrets replaces the environment values of the services that
+// reference a secret, like the web_environment entry API_TOKEN=secret:api-token,
+// with the value of the secret. It only changes the loaded project, so the
+// values are never written in the generated compose files.
+func resolveComposeSecrets(project *composeTypes.Project) error {
+	for serviceName, service := range project.Services {
+		for key, value := range service.Environment {
+			if value == nil {
+				continue
+			}
+			name, ok := secrets.Reference(*value)
+			if !ok {
+				continue
+			}
+			secret, err := secrets.Resolve(name)
+			if err != nil {
+				return fmt.Errorf("unable to resolve %s of the %s service: %w", key, serviceName, err)
+			}
+			service.Environment[key] = &secret
+		}
+	}
+	return nil
+}
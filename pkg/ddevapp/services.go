@@ -0,0 +1,377 @@
+package ddevapp
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+
+	"github.com/ddev/ddev/pkg/dockerutil"
+	"github.com/ddev/ddev/pkg/fileutil"
+	"github.com/ddev/ddev/pkg/util"
+	"go.yaml.in/yaml/v4"
+)
+
+// ServiceDesc configures a built-in optional service in the services section
+// of config.yaml
+type ServiceDesc struct {
+	Version string `yaml:"version,omitempty"`
+}
+
+// Built-in optional services
+const (
+	ServiceRedis         = "redis"
+	ServiceValkey        = "valkey"
+	ServiceOpenSearch    = "opensearch"
+	ServiceElasticsearch = "elasticsearch"
+)
+
+// builtinService describes how DDEV runs a built-in optional service
+type builtinService struct {
+	image          string            // image repository
+	tags           map[string]string // image tag for each supported version
+	defaultVersion string
+	port           int
+	dataDir        string   // in-container data directory, kept in a volume
+	healthcheck    string   // shell command run by the compose healthcheck
+	environment    []string // container environment
+	kind           string   // "cache" or "search", only one of each kind can be enabled
+	cli            string   // redis-compatible client used for snapshots
+}
+
+// builtinServices are the services that can be enabled in the services section of config.yaml
+var builtinServices = map[string]builtinService{
+	ServiceRedis: {
+		image:          "redis",
+		tags:           map[string]string{"6": "6", "7": "7", "8": "8"},
+		defaultVersion: "7",
+		port:           6379,
+		dataDir:        "/data",
+		healthcheck:    "redis-cli ping | grep -q PONG",
+		kind:           "cache",
+		cli:            "redis-cli",
+	},
+	ServiceValkey: {
+		image:          "valkey/valkey",
+		tags:           map[string]string{"7": "7", "8": "8"},
+		defaultVersion: "8",
+		port:           6379,
+		dataDir:        "/data",
+		healthcheck:    "valkey-cli ping | grep -q PONG",
+		kind:           "cache",
+		cli:            "valkey-cli",
+	},
+	ServiceOpenSearch: {
+		image:          "opensearchproject/opensearch",
+		tags:           map[string]string{"1": "1", "2": "2", "3": "3"},
+		defaultVersion: "2",
+		port:           9200,
+		dataDir:        "/usr/share/opensearch/data",
+		healthcheck:    "curl -fsS http://localhost:9200/_cluster/health >/dev/null",
+		environment: []string{
+			"discovery.type=single-node",
+			"bootstrap.memory_lock=false",
+			"DISABLE_SECURITY_PLUGIN=true",
+			"DISABLE_INSTALL_DEMO_CONFIG=true",
+			"OPENSEARCH_JAVA_OPTS=-Xms512m -Xmx512m",
+		},
+		kind: "search",
+	},
+	ServiceElasticsearch: {
+		image:          "elasticsearch",
+		tags:           map[string]string{"7": "7.17.28", "8": "8.18.0"},
+		defaultVersion: "8",
+		port:           9200,
+		dataDir:        "/usr/share/elasticsearch/data",
+		healthcheck:    "curl -fsS http://localhost:9200/_cluster/health >/dev/null",
+		environment: []string{
+			"discovery.type=single-node",
+			"xpack.security.enabled=false",
+			"ES_JAVA_OPTS=-Xms512m -Xmx512m",
+		},
+		kind: "search",
+	},
+}
+
+// BuiltinServiceTemplate is the data used to render a built-in service
+// in the .ddev-docker-compose-base.yaml
+type BuiltinServiceTemplate struct {
+	Name        string
+	Image       string
+	Port        int
+	DataDir     string
+	Healthcheck string
+	Environment []string
+	VolumeName  string
+	DescribeURL string
+}
+
+// GetValidBuiltinServices returns the names of the built-in services
+func GetValidBuiltinServices() []string {
+	names := make([]string, 0, len(builtinServices))
+	for name := range builtinServices {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GetValidBuiltinServiceVersions returns the supported versions of a built-in service
+func GetValidBuiltinServiceVersions(name string) []string {
+	versions := make([]string, 0, len(builtinServices[name].tags))
+	for v := range builtinServices[name].tags {
+		versions = append(versions, v)
+	}
+	sort.Strings(versions)
+	return versions
+}
+
+// GetBuiltinServiceNames returns the enabled built-in services, sorted
+func (app *DdevApp) GetBuiltinServiceNames() []string {
+	names := make([]string, 0, len(app.Services))
+	for name := range app.Services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GetBuiltinServiceVersion returns the configured version of an enabled built-in
+// service, or its default version
+func (app *DdevApp) GetBuiltinServiceVersion(name string) string {
+	if v := app.Services[name].Version; v != "" {
+		return v
+	}
+	return builtinServices[name].defaultVersion
+}
+
+// getBuiltinServiceOfKind returns the enabled service of a kind, or an empty string
+func (app *DdevApp) getBuiltinServiceOfKind(kind string) string {
+	for _, name := range app.GetBuiltinServiceNames() {
+		if builtinServices[name].kind == kind {
+			return name
+		}
+	}
+	return ""
+}
+
+// GetCacheService returns the enabled redis-compatible service, or an empty string
+func (app *DdevApp) GetCacheService() string {
+	return app.getBuiltinServiceOfKind("cache")
+}
+
+// GetSearchService returns the enabled search service, or an empty string
+func (app *DdevApp) GetSearchService() string {
+	return app.getBuiltinServiceOfKind("search")
+}
+
+// GetSearchServiceURL returns the in-network URL of the enabled search
+// service, or an empty string
+func (app *DdevApp) GetSearchServiceURL() string {
+	name := app.GetSearchService()
+	if name == "" {
+		return ""
+	}
+	return fmt.Sprintf("http://%s:%d", name, builtinServices[name].port)
+}
+
+// validateBuiltinServices checks the services section of config.yaml
+func (app *DdevApp) validateBuiltinServices() error {
+	kinds := make(map[string]string)
+	for _, name := range app.GetBuiltinServiceNames() {
+		svc, ok := builtinServices[name]
+		if !ok {
+			return fmt.Errorf("the %s project has an unsupported service '%s' in services, DDEV only supports the following services: %v", app.Name, name, GetValidBuiltinServices())
+		}
+		version := app.GetBuiltinServiceVersion(name)
+		if _, ok := svc.tags[version]; !ok {
+			return fmt.Errorf("the %s project has an unsupported 'version: %s' for %s in services, DDEV only supports the following versions: %v", app.Name, version, name, GetValidBuiltinServiceVersions(name))
+		}
+		if other, ok := kinds[svc.kind]; ok {
+			return fmt.Errorf("the %s project has both %s and %s in services, only one of them can be used", app.Name, other, name)
+		}
+		kinds[svc.kind] = name
+	}
+	if len(app.GetBuiltinServiceNames()) == 0 {
+		return nil
+	}
+	composeServices, err := app.getCustomComposeServices()
+	if err != nil {
+		return err
+	}
+	for _, name := range app.GetBuiltinServiceNames() {
+		if file, ok := composeServices[name]; ok {
+			return fmt.Errorf("the %s project has %s in services, but %s also defines a '%s' service, probably from an add-on; remove one of them", app.Name, name, file, name)
+		}
+	}
+	return nil
+}
+
+// getCustomComposeServices maps the services fully defined, with an image or
+// a build, in .ddev/docker-compose.*.yaml files to the file defining them.
+// Services that only override some settings are left out.
+func (app *DdevApp) getCustomComposeServices() (map[string]string, error) {
+	services := make(map[string]string)
+	files, err := filepath.Glob(app.GetConfigPath("docker-compose.*.y*ml"))
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		var compose struct {
+			Services map[string]map[string]any `yaml:"services"`
+		}
+		if err = yaml.Unmarshal(content, &compose); err != nil {
+			return nil, fmt.Errorf("unable to parse %s: %v", file, err)
+		}
+		for name, service := range compose.Services {
+			_, hasImage := service["image"]
+			_, hasBuild := service["build"]
+			if hasImage || hasBuild {
+				services[name] = filepath.Join(".ddev", filepath.Base(file))
+			}
+		}
+	}
+	return services, nil
+}
+
+// GetBuiltinServiceVolumeName returns the data volume name of a built-in service
+func (app *DdevApp) GetBuiltinServiceVolumeName(name string) string {
+	return fmt.Sprintf("ddev-%s-%s", app.Name, name)
+}
+
+// getBuiltinServiceTemplates returns the template data for the enabled built-in services
+func (app *DdevApp) getBuiltinServiceTemplates() []BuiltinServiceTemplate {
+	var templates []BuiltinServiceTemplate
+	for _, name := range app.GetBuiltinServiceNames() {
+		svc, ok := builtinServices[name]
+		if !ok {
+			continue
+		}
+		describeURL := fmt.Sprintf("%s:%d", name, svc.port)
+		if svc.kind == "search" {
+			describeURL = "http://" + describeURL
+		}
+		templates = append(templates, BuiltinServiceTemplate{
+			Name:        name,
+			Image:       svc.image + ":" + svc.tags[app.GetBuiltinServiceVersion(name)],
+			Port:        svc.port,
+			DataDir:     svc.dataDir,
+			Healthcheck: svc.healthcheck,
+			Environment: svc.environment,
+			VolumeName:  app.GetBuiltinServiceVolumeName(name),
+			DescribeURL: describeURL,
+		})
+	}
+	return templates
+}
+
+// getBuiltinServiceSnapshotFile returns the file in .ddev/db_snapshots that holds
+// the data of a built-in service for a database snapshot
+func getBuiltinServiceSnapshotFile(snapshotName string, name string) string {
+	return fmt.Sprintf("%s-%s.rdb", snapshotName, name)
+}
+
+// snapshotBuiltinServices saves the redis-compatible service data next to
+// the database snapshot. Search services aren't snapshotted, their indexes
+// can be rebuilt from the database.
+func (app *DdevApp) snapshotBuiltinServices(snapshotName string) error {
+	name := app.GetCacheService()
+	if name == "" {
+		return nil
+	}
+	svc := builtinServices[name]
+	stdout, stderr, err := app.Exec(&ExecOpts{
+		Service: name,
+		Cmd:     svc.cli + " SAVE",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save %s data: %v, stdout=%s, stderr=%s", name, err, stdout, stderr)
+	}
+	tmpDir, err := os.MkdirTemp("", "ddev-"+name)
+	if err != nil {
+		return err
+	}
+	// nolint: errcheck
+	defer os.RemoveAll(tmpDir)
+	err = dockerutil.CopyFromContainer(GetContainerName(app, name), path.Join(svc.dataDir, "dump.rdb"), tmpDir)
+	if err != nil {
+		return fmt.Errorf("failed to copy %s data from container: %v", name, err)
+	}
+	return fileutil.CopyFile(filepath.Join(tmpDir, "dump.rdb"), app.GetConfigPath(filepath.Join("db_snapshots", getBuiltinServiceSnapshotFile(snapshotName, name))))
+}
+
+// restoreBuiltinServices removes the redis-compatible service container and
+// replaces its data with the snapshot data, so it's loaded by the next app.Start()
+func (app *DdevApp) restoreBuiltinServices(snapshotName string) error {
+	name := app.GetCacheService()
+	if name == "" {
+		return nil
+	}
+	snapshotFile := app.GetConfigPath(filepath.Join("db_snapshots", getBuiltinServiceSnapshotFile(snapshotName, name)))
+	if !fileutil.FileExists(snapshotFile) {
+		return nil
+	}
+	c, err := GetContainer(app, name)
+	if err == nil && c != nil {
+		err = dockerutil.RemoveContainer(c.ID)
+		if err != nil {
+			return fmt.Errorf("failed to remove %s container: %v", name, err)
+		}
+	}
+	tmpDir, err := os.MkdirTemp("", "ddev-"+name)
+	if err != nil {
+		return err
+	}
+	// nolint: errcheck
+	defer os.RemoveAll(tmpDir)
+	err = fileutil.CopyFile(snapshotFile, filepath.Join(tmpDir, "dump.rdb"))
+	if err != nil {
+		return err
+	}
+	err = dockerutil.CopyIntoVolume(tmpDir, app.GetBuiltinServiceVolumeName(name), "", "0", "", true)
+	if err != nil {
+		return fmt.Errorf("failed to restore %s data: %v", name, err)
+	}
+	util.Success("Restored %s data from snapshot %s", name, snapshotName)
+	return nil
+}
+
+// deleteBuiltinServiceSnapshots removes the built-in service data saved with a snapshot
+func (app *DdevApp) deleteBuiltinServiceSnapshots(snapshotName string) error {
+	for _, name := range []string{ServiceRedis, ServiceValkey} {
+		f := app.GetConfigPath(filepath.Join("db_snapshots", getBuiltinServiceSnapshotFile(snapshotName, name)))
+		if fileutil.FileExists(f) {
+			if err := os.Remove(f); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// getBuiltinServiceEnv returns the environment variables the cache and search
+// services are reached with, using the given variable names
+func (app *DdevApp) getBuiltinServiceEnv(redisHost, redisPort, redisURL, searchURL string) map[string]string {
+	env := map[string]string{}
+	if name := app.GetCacheService(); name != "" {
+		port := builtinServices[name].port
+		if redisHost != "" {
+			env[redisHost] = name
+		}
+		if redisPort != "" {
+			env[redisPort] = fmt.Sprint(port)
+		}
+		if redisURL != "" {
+			env[redisURL] = fmt.Sprintf("redis://%s:%d", name, port)
+		}
+	}
+	if url := app.GetSearchServiceURL(); url != "" && searchURL != "" {
+		env[searchURL] = url
+	}
+	return env
+}
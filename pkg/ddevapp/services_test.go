@@ -0,0 +1,57 @@
+package ddevapp
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestBuiltinServices checks validation, compose template data and the
+// settings environment of the built-in services
+func TestBuiltinServices(t *testing.T) {
+	app := &DdevApp{Name: "svc"}
+	require.NoError(t, app.validateBuiltinServices())
+	require.Empty(t, app.getBuiltinServiceTemplates())
+	require.Empty(t, app.getBuiltinServiceEnv("REDIS_HOST", "REDIS_PORT", "REDIS_URL", "OPENSEARCH_URL"))
+
+	app.Services = map[string]ServiceDesc{
+		ServiceRedis:         {},
+		ServiceElasticsearch: {Version: "7"},
+	}
+	require.NoError(t, app.validateBuiltinServices())
+	require.Equal(t, ServiceRedis, app.GetCacheService())
+	require.Equal(t, ServiceElasticsearch, app.GetSearchService())
+	require.Equal(t, "7", app.GetBuiltinServiceVersion(ServiceRedis))
+
+	templates := app.getBuiltinServiceTemplates()
+	require.Len(t, templates, 2)
+	require.Equal(t, "elasticsearch:7.17.28", templates[0].Image)
+	require.Equal(t, "http://elasticsearch:9200", templates[0].DescribeURL)
+	require.Equal(t, "redis:7", templates[1].Image)
+	require.Equal(t, "ddev-svc-redis", templates[1].VolumeName)
+
+	require.Equal(t, map[string]string{
+		"REDIS_HOST":     "redis",
+		"REDIS_PORT":     "6379",
+		"REDIS_URL":      "redis://redis:6379",
+		"OPENSEARCH_URL": "http://elasticsearch:9200",
+	}, app.getBuiltinServiceEnv("REDIS_HOST", "REDIS_PORT", "REDIS_URL", "OPENSEARCH_URL"))
+
+	app.Services = map[string]ServiceDesc{ServiceRedis: {}, ServiceValkey: {}}
+	require.ErrorContains(t, app.validateBuiltinServices(), "both redis and valkey")
+	app.Services = map[string]ServiceDesc{ServiceOpenSearch: {Version: "9"}}
+	require.ErrorContains(t, app.validateBuiltinServices(), "unsupported 'version: 9' for opensearch")
+	app.Services = map[string]ServiceDesc{"memcached": {}}
+	require.ErrorContains(t, app.validateBuiltinServices(), "unsupported service 'memcached'")
+
+	// An add-on defining the same service conflicts, an override doesn't
+	app.AppRoot = t.TempDir()
+	require.NoError(t, os.MkdirAll(app.GetConfigPath(""), 0755))
+	require.NoError(t, os.WriteFile(app.GetConfigPath("docker-compose.override.yaml"), []byte("services:\n  redis:\n    environment:\n      - TZ=UTC\n"), 0644))
+	app.Services = map[string]ServiceDesc{ServiceRedis: {}}
+	require.NoError(t, app.validateBuiltinServices())
+	require.NoError(t, os.WriteFile(app.GetConfigPath("docker-compose.redis.yaml"), []byte("services:\n  redis:\n    image: redis:7\n"), 0644))
+	require.ErrorContains(t, app.validateBuiltinServices(), filepath.Join(".ddev", "docker-compose.redis.yaml")+" also defines a 'redis' service")
+}
@@ -3,6 +3,7 @@ package ddevapp
 import (
 	"errors"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
 
@@ -146,6 +147,12 @@ func shopware6PostStartAction(app *DdevApp) error {
 		"APP_URL":      app.GetPrimaryURL(),
 		"MAILER_DSN":   `smtp://127.0.0.1:1025?encryption=&auth_mode=`,
 	}
+	// Use the built-in cache and search services if they are enabled
+	maps.Copy(envMap, app.getBuiltinServiceEnv("", "", "REDIS_URL", "OPENSEARCH_URL"))
+	if app.GetSearchService() != "" {
+		envMap["SHOPWARE_ES_ENABLED"] = "1"
+		envMap["SHOPWARE_ES_INDEXING_ENABLED"] = "1"
+	}
 	// If the .env.local doesn't exist, create it.
 	switch {
 	case err == nil:
@@ -0,0 +1,391 @@
+package ddevapp
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ddev/ddev/pkg/nodeps"
+	"github.com/ddev/ddev/pkg/util"
+)
+
+// SlowQueryLogDefaultThreshold is the default minimum query duration that
+// gets recorded once the slow query log is enabled.
+const SlowQueryLogDefaultThreshold = 500 * time.Millisecond
+
+// SlowQuery is one aggregated entry in the slow query digest.
+// Queries are grouped by their normalized form, with literals replaced by "?".
+type SlowQuery struct {
+	Query        string  `json:"query"`
+	Count        int64   `json:"count"`
+	TotalTime    float64 `json:"total_time_seconds"`
+	MeanTime     float64 `json:"mean_time_seconds"`
+	MaxTime      float64 `json:"max_time_seconds"`
+	RowsExamined int64   `json:"rows_examined"`
+	RowsSent     int64   `json:"rows_sent"`
+	// BlocksRead is the number of shared blocks hit or read, Postgres counts
+	// these instead of examined rows
+	BlocksRead int64 `json:"blocks_read,omitempty"`
+}
+
+// SlowQueryLogStatus describes the current slow query log settings in the db container.
+type SlowQueryLogStatus struct {
+	Enabled bool `json:"enabled"`
+	// Threshold is the minimum query duration that is logged
+	Threshold time.Duration `json:"threshold"`
+	// StatementsAvailable is true if the digest can be read: on Postgres
+	// when pg_stat_statements is loaded, on MariaDB/MySQL when log_output
+	// includes TABLE so that slow queries end up in mysql.slow_log
+	StatementsAvailable bool `json:"statements_available"`
+}
+
+// slowQueryLogOutputFile keeps the log_output of the db server from before
+// SlowQueryLogEnable, so that SlowQueryLogDisable can restore it. It lives
+// in the container like the runtime setting, so both go away on a restart.
+const slowQueryLogOutputFile = "/tmp/ddev-slowlog-log-output"
+
+// SlowQueryLogEnable turns on the slow query log in the db container at runtime.
+// On MariaDB/MySQL the log is written to the mysql.slow_log table so it can be
+// aggregated with SQL, the previous log_output is kept for SlowQueryLogDisable. On Postgres log_min_duration_statement is set and
+// pg_stat_statements is added to shared_preload_libraries; the returned bool
+// is true if the db container needs a restart before statistics are collected.
+func SlowQueryLogEnable(app *DdevApp, threshold time.Duration) (bool, error) {
+	if threshold < 0 {
+		return false, fmt.Errorf("slow query threshold must not be negative: %v", threshold)
+	}
+	switch app.Database.Type {
+	case nodeps.MySQL, nodeps.MariaDB:
+		// Only the first enable records log_output, later ones would see TABLE
+		_, _, err := app.Exec(&ExecOpts{
+			Service: "db",
+			Cmd:     fmt.Sprintf(`set -eu -o pipefail; [ -f %[3]s ] || %[1]s -N -B -e "SELECT @@GLOBAL.log_output;" >%[3]s; %[1]s -e "SET GLOBAL log_output='TABLE'; SET GLOBAL long_query_time=%[2]s; SET GLOBAL slow_query_log=ON;"`, app.GetDBClientCommand(), strconv.FormatFloat(threshold.Seconds(), 'f', 6, 64), slowQueryLogOutputFile),
+		})
+		return false, err
+	case nodeps.Postgres:
+		preloaded, err := postgresStatementsPreloaded(app)
+		if err != nil {
+			return false, err
+		}
+		sql := fmt.Sprintf(`ALTER SYSTEM SET log_min_duration_statement = %d;`, threshold.Milliseconds())
+		if !preloaded {
+			// Keep the libraries database.extensions and database.settings preload
+			libs := append(app.GetPostgresPreloadLibraries(), "pg_stat_statements")
+			sql += fmt.Sprintf(` ALTER SYSTEM SET shared_preload_libraries = '%s';`, strings.Join(libs, ","))
+		}
+		_, _, err = app.Exec(&ExecOpts{
+			Service: "db",
+			Cmd:     fmt.Sprintf(`set -eu -o pipefail; echo "%s SELECT pg_reload_conf();" | psql -q -v ON_ERROR_STOP=1 -d postgres >/dev/null`, sql),
+		})
+		if err != nil {
+			return false, err
+		}
+		if preloaded {
+			err = postgresCreateStatementsExtension(app)
+		}
+		return !preloaded, err
+	}
+	return false, fmt.Errorf("slow query log is not supported for database type '%s'", app.Database.Type)
+}
+
+// SlowQueryLogDisable turns off the slow query log in the db container.
+// On MariaDB/MySQL log_output goes back to its value from before
+// SlowQueryLogEnable. On Postgres shared_preload_libraries goes back to the value from the
+// project configuration, pg_stat_statements stays loaded until the next restart.
+func SlowQueryLogDisable(app *DdevApp) error {
+	var cmd string
+	switch app.Database.Type {
+	case nodeps.MySQL, nodeps.MariaDB:
+		cmd = fmt.Sprintf(`set -eu -o pipefail; %[1]s -e "SET GLOBAL slow_query_log=OFF;"; if [ -f %[2]s ]; then %[1]s -e "SET GLOBAL log_output='$(cat %[2]s)';"; rm -f %[2]s; fi`, app.GetDBClientCommand(), slowQueryLogOutputFile)
+	case nodeps.Postgres:
+		cmd = `set -eu -o pipefail; echo "ALTER SYSTEM RESET log_min_duration_statement; ALTER SYSTEM RESET shared_preload_libraries; SELECT pg_reload_conf();" | psql -q -v ON_ERROR_STOP=1 -d postgres >/dev/null`
+	default:
+		return fmt.Errorf("slow query log is not supported for database type '%s'", app.Database.Type)
+	}
+	_, _, err := app.Exec(&ExecOpts{
+		Service: "db",
+		Cmd:     cmd,
+	})
+	return err
+}
+
+// SlowQueryLogReset discards everything collected so far.
+func SlowQueryLogReset(app *DdevApp) error {
+	var cmd string
+	switch app.Database.Type {
+	case nodeps.MySQL, nodeps.MariaDB:
+		// mysql.slow_log can only be truncated while logging to it is off
+		cmd = fmt.Sprintf(`%s -e "SET @was_on=@@GLOBAL.slow_query_log; SET GLOBAL slow_query_log=OFF; TRUNCATE TABLE mysql.slow_log; SET GLOBAL slow_query_log=@was_on;"`, app.GetDBClientCommand())
+	case nodeps.Postgres:
+		preloaded, err := postgresStatementsPreloaded(app)
+		if err != nil || !preloaded {
+			return err
+		}
+		if err = postgresCreateStatementsExtension(app); err != nil {
+			return err
+		}
+		cmd = `psql -q -v ON_ERROR_STOP=1 -c "SELECT pg_stat_statements_reset();" >/dev/null`
+	default:
+		return fmt.Errorf("slow query log is not supported for database type '%s'", app.Database.Type)
+	}
+	_, _, err := app.Exec(&ExecOpts{
+		Service: "db",
+		Cmd:     cmd,
+	})
+	return err
+}
+
+// GetSlowQueryLogStatus reports whether the slow query log is currently enabled.
+func GetSlowQueryLogStatus(app *DdevApp) (SlowQueryLogStatus, error) {
+	status := SlowQueryLogStatus{}
+	switch app.Database.Type {
+	case nodeps.MySQL, nodeps.MariaDB:
+		out, _, err := app.Exec(&ExecOpts{
+			Service: "db",
+			Cmd:     fmt.Sprintf(`%s -N -B -e "SELECT @@GLOBAL.slow_query_log, @@GLOBAL.long_query_time, @@GLOBAL.log_output;"`, app.GetDBClientCommand()),
+		})
+		if err != nil {
+			return status, err
+		}
+		fields := strings.Split(strings.TrimSpace(out), "\t")
+		if len(fields) != 3 {
+			return status, fmt.Errorf("unexpected slow query log status output: '%s'", out)
+		}
+		seconds, _ := strconv.ParseFloat(fields[1], 64)
+		status.Threshold = time.Duration(seconds * float64(time.Second))
+		status.Enabled = fields[0] == "1"
+		status.StatementsAvailable = strings.Contains(strings.ToUpper(fields[2]), "TABLE")
+	case nodeps.Postgres:
+		out, _, err := app.Exec(&ExecOpts{
+			Service: "db",
+			Cmd:     `psql -t -A -c "SELECT setting FROM pg_settings WHERE name = 'log_min_duration_statement';"`,
+		})
+		if err != nil {
+			return status, err
+		}
+		ms, _ := strconv.Atoi(strings.TrimSpace(out))
+		status.Enabled = ms >= 0
+		status.Threshold = time.Duration(max(ms, 0)) * time.Millisecond
+		if status.StatementsAvailable, err = postgresStatementsPreloaded(app); err != nil {
+			return status, err
+		}
+	default:
+		return status, fmt.Errorf("slow query log is not supported for database type '%s'", app.Database.Type)
+	}
+	return status, nil
+}
+
+// GetSlowQueryDigest returns the slowest queries, aggregated by normalized
+// query text and sorted by sortBy ("time", "count" or "rows").
+// On Postgres only the queries whose max time reached the threshold are included.
+// A limit of 0 returns all queries.
+func GetSlowQueryDigest(app *DdevApp, sortBy string, limit int) ([]SlowQuery, error) {
+	var digest []SlowQuery
+	var err error
+	switch app.Database.Type {
+	case nodeps.MySQL, nodeps.MariaDB:
+		digest, err = mysqlSlowQueryDigest(app)
+	case nodeps.Postgres:
+		digest, err = postgresSlowQueryDigest(app)
+	default:
+		return nil, fmt.Errorf("slow query log is not supported for database type '%s'", app.Database.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err = SortSlowQueries(digest, sortBy); err != nil {
+		return nil, err
+	}
+	if limit > 0 && len(digest) > limit {
+		digest = digest[:limit]
+	}
+	return digest, nil
+}
+
+// SortSlowQueries sorts the digest in place, largest first.
+func SortSlowQueries(digest []SlowQuery, sortBy string) error {
+	var key func(q SlowQuery) float64
+	switch sortBy {
+	case "", "time":
+		key = func(q SlowQuery) float64 { return q.TotalTime }
+	case "count":
+		key = func(q SlowQuery) float64 { return float64(q.Count) }
+	case "rows":
+		// Only one of them is counted, depending on the database type
+		key = func(q SlowQuery) float64 { return float64(q.RowsExamined + q.BlocksRead) }
+	default:
+		return fmt.Errorf("invalid sort order '%s', must be one of time, count, rows", sortBy)
+	}
+	slices.SortStableFunc(digest, func(a, b SlowQuery) int {
+		switch ka, kb := key(a), key(b); {
+		case ka > kb:
+			return -1
+		case ka < kb:
+			return 1
+		}
+		return strings.Compare(a.Query, b.Query)
+	})
+	return nil
+}
+
+// mysqlSlowQueryDigest reads mysql.slow_log and aggregates it by normalized query.
+func mysqlSlowQueryDigest(app *DdevApp) ([]SlowQuery, error) {
+	out, _, err := app.Exec(&ExecOpts{
+		Service: "db",
+		Cmd:     fmt.Sprintf(`%s -N -B -e "SELECT query_time, rows_examined, rows_sent, CONVERT(sql_text USING utf8mb4) FROM mysql.slow_log;"`, app.GetDBClientCommand()),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return aggregateMySQLSlowLog(out), nil
+}
+
+// aggregateMySQLSlowLog groups the tab-separated rows of
+// "query_time, rows_examined, rows_sent, sql_text" by normalized query text.
+func aggregateMySQLSlowLog(out string) []SlowQuery {
+	byQuery := map[string]*SlowQuery{}
+	for line := range strings.SplitSeq(out, "\n") {
+		fields := strings.SplitN(line, "\t", 4)
+		if len(fields) != 4 {
+			continue
+		}
+		seconds, err := parseSlowLogQueryTime(fields[0])
+		if err != nil {
+			util.Debug("Skipping slow log row with invalid query_time '%s': %v", fields[0], err)
+			continue
+		}
+		examined, _ := strconv.ParseInt(fields[1], 10, 64)
+		sent, _ := strconv.ParseInt(fields[2], 10, 64)
+		query := NormalizeSlowQuery(fields[3])
+		if query == "" {
+			continue
+		}
+		q, ok := byQuery[query]
+		if !ok {
+			q = &SlowQuery{Query: query}
+			byQuery[query] = q
+		}
+		q.Count++
+		q.TotalTime += seconds
+		q.MaxTime = max(q.MaxTime, seconds)
+		q.RowsExamined += examined
+		q.RowsSent += sent
+	}
+
+	digest := make([]SlowQuery, 0, len(byQuery))
+	for _, q := range byQuery {
+		q.MeanTime = q.TotalTime / float64(q.Count)
+		digest = append(digest, *q)
+	}
+	return digest
+}
+
+// parseSlowLogQueryTime converts a TIME value like "00:00:01.500000" into seconds.
+func parseSlowLogQueryTime(s string) (float64, error) {
+	parts := strings.Split(strings.TrimSpace(s), ":")
+	if len(parts) != 3 {
+		return 0, fmt.Errorf("unexpected time format")
+	}
+	var seconds float64
+	for i, unit := range []float64{3600, 60, 1} {
+		v, err := strconv.ParseFloat(parts[i], 64)
+		if err != nil {
+			return 0, err
+		}
+		seconds += v * unit
+	}
+	return seconds, nil
+}
+
+// postgresSlowQueryDigest reads pg_stat_statements, which is already
+// normalized and aggregated by Postgres. pg_stat_statements records every
+// query, so the ones that never took log_min_duration_statement are left out.
+func postgresSlowQueryDigest(app *DdevApp) ([]SlowQuery, error) {
+	preloaded, err := postgresStatementsPreloaded(app)
+	if err != nil {
+		return nil, err
+	}
+	if !preloaded {
+		return nil, fmt.Errorf("pg_stat_statements is not loaded yet, run 'ddev db slowlog on' and restart the project with 'ddev restart'")
+	}
+	if err = postgresCreateStatementsExtension(app); err != nil {
+		return nil, err
+	}
+
+	// The *_exec_time columns replaced *_time in Postgres 13
+	timeColumn := "exec_time"
+	if isOld, _ := util.SemverValidate("< 13", app.Database.Version); isOld {
+		timeColumn = "time"
+	}
+	sql := fmt.Sprintf(`SELECT calls, total_%[1]s / 1000, max_%[1]s / 1000, rows, shared_blks_hit + shared_blks_read, regexp_replace(query, '\s+', ' ', 'g') FROM pg_stat_statements WHERE dbid = (SELECT oid FROM pg_database WHERE datname = current_database()) AND max_%[1]s >= (SELECT GREATEST(setting::float, 0) FROM pg_settings WHERE name = 'log_min_duration_statement')`, timeColumn)
+	out, _, err := app.Exec(&ExecOpts{
+		Service: "db",
+		RawCmd:  []string{"psql", "-t", "-A", "-F", "\t", "-c", sql},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var digest []SlowQuery
+	for line := range strings.SplitSeq(out, "\n") {
+		fields := strings.SplitN(line, "\t", 6)
+		if len(fields) != 6 {
+			continue
+		}
+		q := SlowQuery{Query: strings.TrimSpace(fields[5])}
+		q.Count, _ = strconv.ParseInt(fields[0], 10, 64)
+		q.TotalTime, _ = strconv.ParseFloat(fields[1], 64)
+		q.MaxTime, _ = strconv.ParseFloat(fields[2], 64)
+		q.RowsSent, _ = strconv.ParseInt(fields[3], 10, 64)
+		q.BlocksRead, _ = strconv.ParseInt(fields[4], 10, 64)
+		if q.Count > 0 {
+			q.MeanTime = q.TotalTime / float64(q.Count)
+		}
+		digest = append(digest, q)
+	}
+	return digest, nil
+}
+
+// postgresStatementsPreloaded returns true if pg_stat_statements is in shared_preload_libraries.
+func postgresStatementsPreloaded(app *DdevApp) (bool, error) {
+	out, _, err := app.Exec(&ExecOpts{
+		Service: "db",
+		Cmd:     `psql -t -A -c "SHOW shared_preload_libraries;"`,
+	})
+	if err != nil {
+		return false, err
+	}
+	return strings.Contains(out, "pg_stat_statements"), nil
+}
+
+// postgresCreateStatementsExtension makes the pg_stat_statements view available.
+func postgresCreateStatementsExtension(app *DdevApp) error {
+	_, _, err := app.Exec(&ExecOpts{
+		Service: "db",
+		Cmd:     `psql -q -v ON_ERROR_STOP=1 -c "CREATE EXTENSION IF NOT EXISTS pg_stat_statements;" >/dev/null`,
+	})
+	return err
+}
+
+var (
+	slowQueryEscapedWhitespaceRegex = regexp.MustCompile(`\\[ntr]`)
+	slowQueryStringRegex            = regexp.MustCompile(`'(?:[^'\\]|\\.|'')*'|"(?:[^"\\]|\\.)*"`)
+	slowQueryNumberRegex            = regexp.MustCompile(`\b\d+(?:\.\d+)?\b`)
+	slowQueryInListRegex            = regexp.MustCompile(`(?i)\bIN\s*\(\s*\?(?:\s*,\s*\?)*\s*\)`)
+	slowQueryValuesListRegex        = regexp.MustCompile(`(?i)\bVALUES\s*\(([?,\s]*)\)(?:\s*,\s*\([?,\s]*\))+`)
+	slowQueryWhitespaceRegex        = regexp.MustCompile(`\s+`)
+)
+
+// NormalizeSlowQuery turns a query into its digest form so that queries that
+// only differ in literal values are grouped together.
+func NormalizeSlowQuery(query string) string {
+	q := slowQueryEscapedWhitespaceRegex.ReplaceAllString(query, " ")
+	q = slowQueryStringRegex.ReplaceAllString(q, "?")
+	q = slowQueryNumberRegex.ReplaceAllString(q, "?")
+	q = slowQueryInListRegex.ReplaceAllString(q, "IN (?+)")
+	q = slowQueryValuesListRegex.ReplaceAllString(q, "VALUES ($1)+")
+	q = slowQueryWhitespaceRegex.ReplaceAllString(q, " ")
+	return strings.TrimRight(strings.TrimSpace(q), "; ")
+}
@@ -0,0 +1,56 @@
+package ddevapp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestNormalizeSlowQuery checks that queries differing only in literals share a digest
+func TestNormalizeSlowQuery(t *testing.T) {
+	tests := map[string]string{
+		"SELECT * FROM users WHERE id = 42":                            "SELECT * FROM users WHERE id = ?",
+		"SELECT * FROM users WHERE name = 'O''Brien';":                 "SELECT * FROM users WHERE name = ?",
+		`SELECT * FROM users WHERE name = "bob" AND age > 3.5`:         "SELECT * FROM users WHERE name = ? AND age > ?",
+		"SELECT nid FROM node WHERE nid IN (1, 2,3)":                   "SELECT nid FROM node WHERE nid IN (?+)",
+		"INSERT INTO t (a, b) VALUES (1, 'x'), (2, 'y'), (3, 'z')":     "INSERT INTO t (a, b) VALUES (?, ?)+",
+		"SELECT\\n  *\\tFROM   cache_2 WHERE cid = 'a:1'":              "SELECT * FROM cache_2 WHERE cid = ?",
+		"  UPDATE variable SET value = 'a\\'b' WHERE name = 'cron'  ;": "UPDATE variable SET value = ? WHERE name = ?",
+	}
+	for in, expected := range tests {
+		require.Equal(t, expected, NormalizeSlowQuery(in), "input: %s", in)
+	}
+}
+
+// TestAggregateMySQLSlowLog checks grouping and sorting of mysql.slow_log rows
+func TestAggregateMySQLSlowLog(t *testing.T) {
+	out := "00:00:01.500000\t100\t1\tSELECT * FROM users WHERE id = 1\n" +
+		"00:00:00.500000\t300\t1\tSELECT * FROM users WHERE id = 2\n" +
+		"00:01:00.000000\t10\t10\tSELECT SLEEP(60)\n" +
+		"garbage line\n" +
+		"00:00:00.100000\t5000\t0\tDELETE FROM cache WHERE expire < 1700000000\n"
+
+	digest := aggregateMySQLSlowLog(out)
+	require.Len(t, digest, 3)
+
+	require.NoError(t, SortSlowQueries(digest, "time"))
+	require.Equal(t, "SELECT SLEEP(?)", digest[0].Query)
+	require.Equal(t, 60.0, digest[0].TotalTime)
+
+	users := digest[1]
+	require.Equal(t, "SELECT * FROM users WHERE id = ?", users.Query)
+	require.Equal(t, int64(2), users.Count)
+	require.InDelta(t, 2.0, users.TotalTime, 0.0001)
+	require.InDelta(t, 1.0, users.MeanTime, 0.0001)
+	require.InDelta(t, 1.5, users.MaxTime, 0.0001)
+	require.Equal(t, int64(400), users.RowsExamined)
+	require.Equal(t, int64(2), users.RowsSent)
+
+	require.NoError(t, SortSlowQueries(digest, "count"))
+	require.Equal(t, users.Query, digest[0].Query)
+
+	require.NoError(t, SortSlowQueries(digest, "rows"))
+	require.Equal(t, "DELETE FROM cache WHERE expire < ?", digest[0].Query)
+
+	require.Error(t, SortSlowQueries(digest, "bogus"))
+}
@@ -91,6 +91,9 @@ func (app *DdevApp) DeleteSnapshot(snapshotName string) error {
 	if err = os.RemoveAll(hostSnapshot); err != nil {
 		return fmt.Errorf("failed to remove snapshot '%s': %v", hostSnapshot, err)
 	}
+	if err = app.deleteBuiltinServiceSnapshots(snapshotName); err != nil {
+		return fmt.Errorf("failed to remove service data for snapshot '%s': %v", snapshotName, err)
+	}
 
 	util.Success("Deleted database snapshot '%s'", snapshotName)
 	err = app.ProcessHooks("post-delete-snapshot")
@@ -331,6 +334,11 @@ func (app *DdevApp) RestoreSnapshot(snapshotName string) error {
 			restoreCmd = fmt.Sprintf(`bash -c 'chmod 700 %s && mkdir -p %s && rm -rf %s/* && tar -C %s %s /mnt/snapshots/%s && chmod 700 %s && touch %s/recovery.signal && echo "restore_command = 'true'" >>%s && postgres -c config_file=%s/postgresql.conf -c hba_file=%s/pg_hba.conf'`, postgresDataDir, confdDir, postgresDataDir, postgresDataDir, tarExtract, snapshotFile, postgresDataPath, postgresDataPath, targetConfName, nodeps.PostgresConfigDir, nodeps.PostgresConfigDir)
 		}
 	}
+	err = app.restoreBuiltinServices(snapshotName)
+	if err != nil {
+		return err
+	}
+
 	_ = os.Setenv("DDEV_DB_CONTAINER_COMMAND", restoreCmd)
 	// nolint: errcheck
 	defer os.Unsetenv("DDEV_DB_CONTAINER_COMMAND")
@@ -406,3 +414,25 @@ func GetSnapshotFileFromName(name string, app *DdevApp) (string, error) {
 
 	return "", fmt.Errorf("snapshot %s not found in %s", name, snapshotsDir)
 }
+
+// copySnapshotFiles copies the files of a snapshot, the database and the
+// builtin services, from one snapshots directory to another and returns the
+// copied files
+func copySnapshotFiles(srcDir string, destDir string, snapshotName string) ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(srcDir, snapshotName+"-*"))
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("snapshot %s not found in %s", snapshotName, srcDir)
+	}
+	if err = os.MkdirAll(destDir, 0755); err != nil {
+		return nil, err
+	}
+	for _, f := range files {
+		if err = fileutil.CopyFile(f, filepath.Join(destDir, filepath.Base(f))); err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
@@ -17,6 +17,12 @@ const ConfigInstructions = `
 
 # php_version: "8.4"  # PHP version to use, "5.6" through "8.5"
 
+# php_pools:
+#   - version: "7.4"
+#     path: /legacy
+# Run additional php-fpm pools with other PHP versions, each serving
+# requests below its path. Not available with webserver_type: frankenphp or generic
+
 # You can explicitly specify the webimage but this
 # is not recommended, as the images are often closely tied to DDEV's behavior,
 # so this can break upgrades.
@@ -31,6 +37,18 @@ const ConfigInstructions = `
 #   MariaDB versions can be 5.5-10.8, 10.11, 11.4, 11.8, 12.3
 #   MySQL versions can be 5.5-8.0, 8.4, 9.7
 #   PostgreSQL versions can be 9-18
+#   extensions: [postgis, pgvector] # PostgreSQL only, installed and created in every database
+#   settings:                       # PostgreSQL only, server settings
+#     shared_buffers: 256MB
+
+# services:
+#   redis:
+#     version: "7"
+#   opensearch:
+#     version: "2"
+# Built-in optional services, reachable from the web container by their name.
+# Can be redis (6, 7, 8) or valkey (7, 8),
+# and opensearch (1, 2, 3) or elasticsearch (7, 8)
 
 # You can explicitly specify the dbimage but this
 # is not recommended, as the images are often closely tied to DDEV's behavior,
@@ -48,6 +66,12 @@ const ConfigInstructions = `
 # "ddev xdebug" to enable Xdebug and "ddev xdebug off" to disable it work better,
 # as leaving Xdebug enabled all the time is a big performance hit.
 
+# xdebug_mode: debug,develop  # Xdebug mode when Xdebug is enabled,
+# one of debug, develop, coverage, profile, trace or a comma-separated list of them
+
+# xdebug_start_with_request: yes  # "trigger" starts Xdebug only for requests
+# or commands with a trigger like XDEBUG_SESSION, instead of with every request
+
 # xhgui_http_port: "8143"
 # xhgui_https_port: "8142"
 # The XHGui ports can be changed from the default 8143 and 8142
@@ -61,7 +85,11 @@ const ConfigInstructions = `
 # xhprof_mode: [prepend|xhgui|global]
 # Default is "xhgui"
 
-# webserver_type: nginx-fpm, apache-fpm, generic
+# webserver_type: nginx-fpm, apache-fpm, caddy-fpm, frankenphp, generic
+
+# frankenphp_worker: public/index.php
+# Run FrankenPHP in worker mode with this script, relative to the project root.
+# Only used with webserver_type: frankenphp
 
 # timezone: Europe/Berlin
 # If timezone is unset, DDEV will attempt to derive it from the host system timezone
@@ -261,6 +289,10 @@ const ConfigInstructions = `
 #- name: "http-2"
 #  command: "/var/www/html/node_modules/.bin/http-server /var/www/html/sub -p 3000"
 #  directory: /var/www/html
+#- name: "legacy-queue"
+#  command: "php bin/console messenger:consume"
+#  directory: /var/www/html/legacy
+#  php_version: "7.4" # must be php_version or one of the php_pools versions
 
 # override_config: false
 # By default, config.*.yaml files are *merged* into the configuration
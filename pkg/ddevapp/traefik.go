@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 	"text/template"
@@ -12,6 +13,7 @@ import (
 	exec2 "github.com/ddev/ddev/pkg/exec"
 	"github.com/ddev/ddev/pkg/fileutil"
 	"github.com/ddev/ddev/pkg/globalconfig"
+	"github.com/ddev/ddev/pkg/idlemanager"
 	"github.com/ddev/ddev/pkg/nodeps"
 	"github.com/ddev/ddev/pkg/util"
 )
@@ -204,6 +206,7 @@ func PushGlobalTraefikConfig(activeApps []*DdevApp) error {
 		LetsEncryptEmail   string
 		TraefikMonitorPort string
 		HasCAROOT          bool
+		IdleManager        bool
 	}
 	templateData := traefikData{
 		TargetCertsPath:    inContainerTargetCertsPath,
@@ -212,6 +215,7 @@ func PushGlobalTraefikConfig(activeApps []*DdevApp) error {
 		LetsEncryptEmail:   globalconfig.DdevGlobalConfig.LetsEncryptEmail,
 		TraefikMonitorPort: globalconfig.DdevGlobalConfig.TraefikMonitorPort,
 		HasCAROOT:          globalconfig.GetCAROOT() != "",
+		IdleManager:        idlemanager.IsEnabled(),
 	}
 
 	defaultConfigPath := filepath.Join(globalSourceConfigDir, "default_config.yaml")
@@ -369,6 +373,16 @@ func PushGlobalTraefikConfig(activeApps []*DdevApp) error {
 		}
 	}
 
+	// Route the requests for projects stopped by the idle manager to it
+	if idlemanager.IsEnabled() {
+		written, err := writeIdleWakeConfig(globalSourceConfigDir, activeApps)
+		if err != nil {
+			util.Warning("Failed to write the Traefik config of the idle manager: %v", err)
+		} else if written != "" {
+			expectedConfigs[written] = true
+		}
+	}
+
 	// Copy user-managed custom global config *.yaml files from ~/.ddev/traefik/custom-global-config/
 	customGlobalConfigDir := filepath.Join(globalTraefikDir, "custom-global-config")
 	if fileutil.IsDirectory(customGlobalConfigDir) {
@@ -453,6 +467,55 @@ func PushGlobalTraefikConfig(activeApps []*DdevApp) error {
 	return nil
 }
 
+// writeIdleWakeConfig writes the Traefik config that routes the hostnames of
+// the projects stopped by the idle manager to it, and returns its file name,
+// or "" if no project needs it
+func writeIdleWakeConfig(configDir string, activeApps []*DdevApp) (string, error) {
+	var hostnames []string
+	for _, name := range idlemanager.StoppedProjects() {
+		if slices.ContainsFunc(activeApps, func(a *DdevApp) bool { return a.Name == name }) {
+			continue
+		}
+		p := globalconfig.GetProject(name)
+		if p == nil {
+			idlemanager.ClearStopped(name)
+			continue
+		}
+		app, err := NewApp(p.AppRoot, true)
+		if err != nil {
+			continue
+		}
+		for _, h := range app.GetHostnames() {
+			// Wildcard hostnames can't be started on demand
+			if !strings.Contains(h, "*") {
+				hostnames = append(hostnames, h)
+			}
+		}
+	}
+	if len(hostnames) == 0 {
+		return "", nil
+	}
+
+	const fileName = "idle_wake.yaml"
+	f, err := os.Create(filepath.Join(configDir, fileName))
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	t, err := template.New("traefik_idle_wake_template.yaml").Funcs(getTemplateFuncMap()).ParseFS(bundledAssets, "traefik_idle_wake_template.yaml")
+	if err != nil {
+		return "", fmt.Errorf("could not create template from traefik_idle_wake_template.yaml: %v", err)
+	}
+	err = t.Execute(f, map[string]any{
+		"Hostnames": hostnames,
+		"WakePort":  idlemanager.DefaultWakePort,
+	})
+	if err != nil {
+		return "", fmt.Errorf("could not parse traefik_idle_wake_template.yaml: %v", err)
+	}
+	return fileName, nil
+}
+
 // CleanupGlobalTraefikStaging removes staging files from ~/.ddev/traefik/{config,certs}
 // after they have been pushed into the Docker volume. This is called on poweroff
 // to prevent issues when downgrading DDEV versions.
@@ -0,0 +1,69 @@
+package ddevapp
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ddev/ddev/pkg/config/types"
+)
+
+// xdebugExecScanDir is a directory in the web container with a directory
+// per PHP version that has only its Xdebug ini file, added to
+// PHP_INI_SCAN_DIR to load Xdebug for one command
+const xdebugExecScanDir = "/tmp/ddev-xdebug-exec"
+
+// GetXdebugMode returns the xdebug.mode used when Xdebug is enabled
+func (app *DdevApp) GetXdebugMode() types.XdebugMode {
+	if app.XdebugMode == types.XdebugModeEmpty {
+		return types.XdebugModeDefault
+	}
+	return strings.ReplaceAll(app.XdebugMode, " ", "")
+}
+
+// GetXdebugStartWithRequest returns the xdebug.start_with_request used
+// when Xdebug is enabled
+func (app *DdevApp) GetXdebugStartWithRequest() types.XdebugStartWithRequest {
+	if app.XdebugStartWithRequest == types.XdebugStartWithRequestEmpty {
+		return types.XdebugStartWithRequestYes
+	}
+	return app.XdebugStartWithRequest
+}
+
+// XdebugExecEnv returns the environment for a command in the web container
+// that runs with Xdebug in the given mode, whether Xdebug is enabled for
+// the container or not. phpVersion is the PHP version the command uses,
+// the project's php_version if empty.
+func (app *DdevApp) XdebugExecEnv(mode types.XdebugMode, phpVersion string) ([]string, error) {
+	if mode == types.XdebugModeEmpty {
+		mode = types.XdebugModeDebug
+	}
+	if err := types.CheckValidXdebugMode(mode); err != nil {
+		return nil, err
+	}
+	if phpVersion == "" {
+		phpVersion = app.PHPVersion
+	}
+
+	env := []string{
+		"XDEBUG_MODE=" + mode,
+		// Trigger the debugger, profiler or tracer even when
+		// xdebug_start_with_request is "trigger"
+		"XDEBUG_SESSION=1",
+		"XDEBUG_TRIGGER=1",
+	}
+
+	// If Xdebug isn't loaded, add a scan directory with only its ini file
+	// to the default one, so this command loads it
+	scanDir := xdebugExecScanDir + "/" + phpVersion
+	out, _, err := app.Exec(&ExecOpts{
+		Cmd:       fmt.Sprintf(`php%s -m | grep -qix xdebug && echo loaded || { mkdir -p %s && ln -sf /etc/php/%s/mods-available/xdebug.ini %s/20-xdebug.ini; }`, phpVersion, scanDir, phpVersion, scanDir),
+		SkipHooks: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to prepare Xdebug for PHP %s: %v", phpVersion, err)
+	}
+	if strings.TrimSpace(out) != "loaded" {
+		env = append(env, fmt.Sprintf("PHP_INI_SCAN_DIR=/etc/php/%s/cli/conf.d:%s", phpVersion, scanDir))
+	}
+	return env, nil
+}
@@ -1,13 +1,17 @@
 package ddevapp
 
 import (
+	"encoding/json"
 	"fmt"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/ddev/ddev/pkg/config/types"
 	"github.com/ddev/ddev/pkg/dockerutil"
 	"github.com/ddev/ddev/pkg/globalconfig"
 	"github.com/ddev/ddev/pkg/nodeps"
+	"github.com/ddev/ddev/pkg/xhprof"
 )
 
 // XHGuiSetup does prerequisite work to make XHGui work
@@ -204,3 +208,87 @@ func (app *DdevApp) GetXHGuiEnvVar(name string) string {
 	}
 	return ""
 }
+
+// XHGuiRun is a profiling run stored in the xhgui database
+type XHGuiRun struct {
+	ID          string         `json:"id"`
+	URL         string         `json:"url"`
+	RequestTime time.Time      `json:"request_time"`
+	WallTime    int64          `json:"wall_time"`
+	CPU         int64          `json:"cpu"`
+	Memory      int64          `json:"memory"`
+	Profile     xhprof.Profile `json:"-"`
+}
+
+// GetXHGuiRuns returns the most recent runs of the xhgui database, at most
+// limit of them, optionally only those with a URL containing urlPattern
+func GetXHGuiRuns(app *DdevApp, urlPattern string, limit int) ([]XHGuiRun, error) {
+	where := ""
+	if urlPattern != "" {
+		where = fmt.Sprintf(" WHERE url LIKE '%%%s%%'", sqlEscape(app, urlPattern))
+	}
+	return queryXHGuiRuns(app, fmt.Sprintf("%s ORDER BY request_ts DESC, request_ts_micro DESC LIMIT %d", where, limit))
+}
+
+// GetXHGuiRun returns the run of the xhgui database with the given ID
+func GetXHGuiRun(app *DdevApp, id string) (XHGuiRun, error) {
+	runs, err := queryXHGuiRuns(app, fmt.Sprintf(" WHERE id = '%s'", sqlEscape(app, id)))
+	if err != nil {
+		return XHGuiRun{}, err
+	}
+	if len(runs) == 0 {
+		return XHGuiRun{}, fmt.Errorf("no XHGui run with ID '%s', see 'ddev xhprof report'", id)
+	}
+	return runs[0], nil
+}
+
+// queryXHGuiRuns reads the runs selected by the given SQL clauses from the
+// results table of the xhgui database, with one tab-separated row per run
+func queryXHGuiRuns(app *DdevApp, clauses string) ([]XHGuiRun, error) {
+	query := "SELECT id, url, request_ts, main_wt, main_cpu, main_mu, profile FROM results" + clauses
+	var cmd []string
+	switch app.Database.Type {
+	case nodeps.Postgres:
+		cmd = []string{"psql", "-q", "-A", "-t", "-F", "\t", "-d", "xhgui", "-c", query}
+	case nodeps.MySQL, nodeps.MariaDB:
+		cmd = []string{app.GetDBClientCommand(), "-N", "-B", "--raw", "-D", "xhgui", "-e", query}
+	default:
+		return nil, fmt.Errorf("XHGui runs can't be read with database type '%s'", app.Database.Type)
+	}
+
+	out, stderr, err := app.Exec(&ExecOpts{
+		Service: "db",
+		RawCmd:  cmd,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to read XHGui runs, has 'ddev xhgui on' collected any? %v %s", err, stderr)
+	}
+
+	var runs []XHGuiRun
+	for line := range strings.SplitSeq(strings.TrimSpace(out), "\n") {
+		fields := strings.SplitN(line, "\t", 7)
+		if len(fields) != 7 {
+			continue
+		}
+		run := XHGuiRun{ID: fields[0], URL: fields[1]}
+		requestTS, _ := strconv.ParseInt(fields[2], 10, 64)
+		run.RequestTime = time.Unix(requestTS, 0)
+		run.WallTime, _ = strconv.ParseInt(fields[3], 10, 64)
+		run.CPU, _ = strconv.ParseInt(fields[4], 10, 64)
+		run.Memory, _ = strconv.ParseInt(fields[5], 10, 64)
+		if err = json.Unmarshal([]byte(fields[6]), &run.Profile); err != nil {
+			return nil, fmt.Errorf("unable to parse the profile of XHGui run %s: %v", run.ID, err)
+		}
+		runs = append(runs, run)
+	}
+	return runs, nil
+}
+
+// sqlEscape escapes a value for a single-quoted SQL string of the
+// project's database, MySQL and MariaDB also treat backslashes as escapes
+func sqlEscape(app *DdevApp, s string) string {
+	if app.Database.Type != nodeps.Postgres {
+		s = strings.ReplaceAll(s, `\`, `\\`)
+	}
+	return strings.ReplaceAll(s, `'`, `''`)
+}
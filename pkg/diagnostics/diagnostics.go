@@ -0,0 +1,256 @@
+// Package diagnostics runs DDEV's troubleshooting checks and reports their
+// results as text, JSON or JUnit XML.
+package diagnostics
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ddev/ddev/pkg/ddevapp"
+)
+
+// Severity is how serious a failure of a check is
+type Severity string
+
+// Severities of checks
+const (
+	SeverityInfo    Severity = "info"
+	SeverityWarning Severity = "warning"
+	SeverityError   Severity = "error"
+)
+
+// Status is the outcome of running a check
+type Status string
+
+// Statuses of check results
+const (
+	StatusPass Status = "pass"
+	StatusFail Status = "fail"
+	StatusSkip Status = "skip"
+)
+
+// Context is what a check gets to work with
+type Context struct {
+	// App is the project in the current directory, or nil outside a project
+	App *ddevapp.DdevApp
+}
+
+// Result is the outcome of a single check
+type Result struct {
+	ID          string   `json:"id"`
+	Category    string   `json:"category"`
+	Severity    Severity `json:"severity"`
+	Description string   `json:"description"`
+	Status      Status   `json:"status"`
+	Message     string   `json:"message,omitempty"`
+	Remediation string   `json:"remediation,omitempty"`
+	Output      string   `json:"output,omitempty"`
+	DurationMS  int64    `json:"duration_ms"`
+	// Fixes are the fixes offered by --fix mode
+	Fixes []FixOutcome `json:"fixes,omitempty"`
+}
+
+// Check is a single diagnostic
+type Check interface {
+	// ID is the unique name of the check, like "docker.provider"
+	ID() string
+	// Category groups related checks, like "docker" or "tls"
+	Category() string
+	// Severity is how serious a failure of the check is
+	Severity() Severity
+	// Description says what the check looks at
+	Description() string
+	// Run performs the check. The ID, category, severity, description and
+	// duration of the result are filled in by the runner.
+	Run(ctx *Context) Result
+}
+
+// FuncCheck is a Check implemented by a function
+type FuncCheck struct {
+	CheckID          string
+	CheckCategory    string
+	CheckSeverity    Severity
+	CheckDescription string
+	// Remediation is used for failed results that don't have their own
+	Remediation string
+	Fn          func(ctx *Context) Result
+	// FixFn returns the fixes for a failed result, it's optional
+	FixFn func(ctx *Context, r Result) []Fix
+}
+
+// ID implements Check
+func (c *FuncCheck) ID() string { return c.CheckID }
+
+// Category implements Check
+func (c *FuncCheck) Category() string { return c.CheckCategory }
+
+// Severity implements Check
+func (c *FuncCheck) Severity() Severity { return c.CheckSeverity }
+
+// Description implements Check
+func (c *FuncCheck) Description() string { return c.CheckDescription }
+
+// Run implements Check
+func (c *FuncCheck) Run(ctx *Context) Result {
+	r := c.Fn(ctx)
+	if r.Status == StatusFail && r.Remediation == "" {
+		r.Remediation = c.Remediation
+	}
+	return r
+}
+
+var (
+	registryLock sync.Mutex
+	registry     = map[string]Check{}
+)
+
+// Register adds a check to the registry. It panics on a duplicate ID, as
+// that's a programming error.
+func Register(c Check) {
+	registryLock.Lock()
+	defer registryLock.Unlock()
+	if _, ok := registry[c.ID()]; ok {
+		panic(fmt.Sprintf("diagnostics check %s is already registered", c.ID()))
+	}
+	registry[c.ID()] = c
+}
+
+// Registered returns the registered checks, sorted by category and ID
+func Registered() []Check {
+	registryLock.Lock()
+	defer registryLock.Unlock()
+	checks := make([]Check, 0, len(registry))
+	for _, c := range registry {
+		checks = append(checks, c)
+	}
+	sortChecks(checks)
+	return checks
+}
+
+// sortChecks sorts checks by category and ID
+func sortChecks(checks []Check) {
+	sort.SliceStable(checks, func(i, j int) bool {
+		if checks[i].Category() != checks[j].Category() {
+			return checks[i].Category() < checks[j].Category()
+		}
+		return checks[i].ID() < checks[j].ID()
+	})
+}
+
+// Select returns the checks matching any of the IDs or categories.
+// With no IDs and no categories, all checks are returned. An ID or
+// category that doesn't match any check is an error.
+func Select(checks []Check, ids []string, categories []string) ([]Check, error) {
+	if len(ids) == 0 && len(categories) == 0 {
+		return checks, nil
+	}
+	known := map[string]bool{}
+	knownCategories := map[string]bool{}
+	for _, c := range checks {
+		known[c.ID()] = true
+		knownCategories[c.Category()] = true
+	}
+	for _, id := range ids {
+		if !known[id] {
+			return nil, fmt.Errorf("unknown check '%s', use --list to see the available checks", id)
+		}
+	}
+	for _, category := range categories {
+		if !knownCategories[category] {
+			return nil, fmt.Errorf("unknown category '%s', use --list to see the available categories", category)
+		}
+	}
+	var selected []Check
+	for _, c := range checks {
+		if slices.Contains(ids, c.ID()) || slices.Contains(categories, c.Category()) {
+			selected = append(selected, c)
+		}
+	}
+	return selected, nil
+}
+
+// Run runs the checks in order and returns their results
+func Run(ctx *Context, checks []Check) []Result {
+	results := make([]Result, 0, len(checks))
+	for _, c := range checks {
+		start := time.Now()
+		r := runCheck(ctx, c)
+		r.ID = c.ID()
+		r.Category = c.Category()
+		r.Severity = c.Severity()
+		r.Description = c.Description()
+		r.DurationMS = time.Since(start).Milliseconds()
+		if r.Status == "" {
+			r.Status = StatusPass
+		}
+		results = append(results, r)
+	}
+	return results
+}
+
+// runCheck runs a single check, turning a panic into a failed result so
+// one broken check doesn't take the whole report down
+func runCheck(ctx *Context, c Check) (r Result) {
+	defer func() {
+		if p := recover(); p != nil {
+			r = Result{Status: StatusFail, Message: fmt.Sprintf("check panicked: %v", p)}
+		}
+	}()
+	return c.Run(ctx)
+}
+
+// Failed reports whether any check of at least the given severity failed
+func Failed(results []Result, minSeverity Severity) bool {
+	for _, r := range results {
+		if r.Status == StatusFail && severityRank(r.Severity) >= severityRank(minSeverity) {
+			return true
+		}
+	}
+	return false
+}
+
+// severityRank orders severities from info to error
+func severityRank(s Severity) int {
+	switch s {
+	case SeverityError:
+		return 2
+	case SeverityWarning:
+		return 1
+	}
+	return 0
+}
+
+// IsValidSeverity reports whether s is a known severity
+func IsValidSeverity(s string) bool {
+	return slices.Contains([]string{string(SeverityInfo), string(SeverityWarning), string(SeverityError)}, s)
+}
+
+// Pass returns a passing result with a message
+func Pass(format string, a ...any) Result {
+	return Result{Status: StatusPass, Message: fmt.Sprintf(format, a...)}
+}
+
+// Fail returns a failing result with a message
+func Fail(format string, a ...any) Result {
+	return Result{Status: StatusFail, Message: fmt.Sprintf(format, a...)}
+}
+
+// Skip returns a skipped result with the reason
+func Skip(format string, a ...any) Result {
+	return Result{Status: StatusSkip, Message: fmt.Sprintf(format, a...)}
+}
+
+// Categories returns the sorted unique categories of the checks
+func Categories(checks []Check) []string {
+	var categories []string
+	for _, c := range checks {
+		if !slices.Contains(categories, c.Category()) {
+			categories = append(categories, c.Category())
+		}
+	}
+	sort.Strings(categories)
+	return categories
+}
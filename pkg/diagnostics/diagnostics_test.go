@@ -0,0 +1,193 @@
+package diagnostics
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ddev/ddev/pkg/ddevapp"
+	"github.com/stretchr/testify/require"
+)
+
+// testChecks returns checks with fixed results for the runner and reports
+func testChecks() []Check {
+	return []Check{
+		&FuncCheck{CheckID: "a.pass", CheckCategory: "a", CheckSeverity: SeverityError, CheckDescription: "passes",
+			Fn: func(_ *Context) Result { return Pass("fine") }},
+		&FuncCheck{CheckID: "a.warn", CheckCategory: "a", CheckSeverity: SeverityWarning, CheckDescription: "fails as warning", Remediation: "do this",
+			Fn: func(_ *Context) Result {
+				r := Fail("not great")
+				r.Output = "first detail\nsecond detail"
+				return r
+			}},
+		&FuncCheck{CheckID: "b.skip", CheckCategory: "b", CheckSeverity: SeverityError, CheckDescription: "skips",
+			Fn: func(_ *Context) Result { return Skip("no project") }},
+		&FuncCheck{CheckID: "b.panic", CheckCategory: "b", CheckSeverity: SeverityError, CheckDescription: "panics",
+			Fn: func(_ *Context) Result { panic("boom") }},
+	}
+}
+
+// TestSelectAndRun checks selecting checks by id and category and running them
+func TestSelectAndRun(t *testing.T) {
+	checks := testChecks()
+
+	selected, err := Select(checks, nil, nil)
+	require.NoError(t, err)
+	require.Len(t, selected, 4)
+	selected, err = Select(checks, []string{"b.skip"}, []string{"a"})
+	require.NoError(t, err)
+	require.Len(t, selected, 3)
+	_, err = Select(checks, []string{"nope"}, nil)
+	require.ErrorContains(t, err, "unknown check 'nope'")
+	_, err = Select(checks, nil, []string{"nope"})
+	require.ErrorContains(t, err, "unknown category 'nope'")
+	require.Equal(t, []string{"a", "b"}, Categories(checks))
+
+	results := Run(&Context{}, checks)
+	require.Len(t, results, 4)
+	require.Equal(t, StatusPass, results[0].Status)
+	require.Equal(t, SeverityError, results[0].Severity)
+	require.Equal(t, StatusFail, results[1].Status)
+	require.Equal(t, "do this", results[1].Remediation)
+	require.Equal(t, StatusSkip, results[2].Status)
+	require.Equal(t, StatusFail, results[3].Status)
+	require.Contains(t, results[3].Message, "boom")
+
+	require.True(t, Failed(results, SeverityError))
+	require.True(t, Failed(results[:2], SeverityWarning))
+	require.False(t, Failed(results[:2], SeverityError))
+}
+
+// TestReports checks the JSON and JUnit reports
+func TestReports(t *testing.T) {
+	results := Run(&Context{}, testChecks())
+
+	var b bytes.Buffer
+	require.NoError(t, WriteReport(&b, FormatJSON, results))
+	var report jsonReport
+	require.NoError(t, json.Unmarshal(b.Bytes(), &report))
+	require.Equal(t, summary{Total: 4, Passed: 1, Failed: 2, Skipped: 1}, report.Summary)
+	require.Equal(t, "a.warn", report.Results[1].ID)
+
+	b.Reset()
+	require.NoError(t, WriteReport(&b, FormatJUnit, results))
+	var suites junitTestSuites
+	require.NoError(t, xml.Unmarshal(b.Bytes(), &suites))
+	require.Equal(t, 4, suites.Tests)
+	require.Equal(t, 2, suites.Failures)
+	require.Len(t, suites.Suites, 2)
+	require.Equal(t, "a", suites.Suites[0].Name)
+	require.Equal(t, "warning", suites.Suites[0].Cases[1].Failure.Type)
+	require.Contains(t, suites.Suites[0].Cases[1].Failure.Text, "Remediation: do this")
+	require.NotNil(t, suites.Suites[1].Cases[0].Skipped)
+
+	b.Reset()
+	require.NoError(t, WriteReport(&b, FormatText, results))
+	require.Contains(t, b.String(), "4 checks: 1 passed, 2 failed, 1 skipped")
+	require.Contains(t, b.String(), "        first detail\n        second detail\n")
+	require.Error(t, WriteReport(&b, "xml", results))
+}
+
+// TestLoadProjectChecks checks loading and validating checks from .ddev/diagnostics
+func TestLoadProjectChecks(t *testing.T) {
+	appRoot := t.TempDir()
+	checksDir := filepath.Join(appRoot, ".ddev", ProjectChecksDir)
+	require.NoError(t, os.MkdirAll(checksDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(checksDir, "addon.yaml"), []byte(`#ddev-generated
+checks:
+  - id: addon.ping
+    category: addon
+    severity: error
+    exec: redis-cli -h redis ping
+  - id: addon.host
+    category: addon
+    exec-host: "echo ok"
+  - id: a.pass
+    category: addon
+    exec: "true"
+  - id: addon.both
+    category: addon
+    exec: "true"
+    exec-host: "true"
+  - id: addon.badseverity
+    category: addon
+    severity: critical
+    exec: "true"
+`), 0644))
+
+	app := &ddevapp.DdevApp{AppRoot: appRoot}
+	checks, err := LoadProjectChecks(app, testChecks())
+	require.NoError(t, err)
+	require.Len(t, checks, 2)
+	require.Equal(t, "addon.ping", checks[0].ID())
+	require.Equal(t, SeverityError, checks[0].Severity())
+	require.Equal(t, SeverityWarning, checks[1].Severity())
+	require.Equal(t, "Check from .ddev/diagnostics/addon.yaml", checks[1].Description())
+
+	results := Run(&Context{App: app}, checks[1:])
+	require.Equal(t, StatusPass, results[0].Status, results[0].Message)
+	require.Equal(t, "ok", results[0].Output)
+
+	checks, err = LoadProjectChecks(nil, nil)
+	require.NoError(t, err)
+	require.Empty(t, checks)
+}
+
+// TestRunFixes checks offering, applying and re-checking fixes
+func TestRunFixes(t *testing.T) {
+	broken := true
+	checks := []Check{
+		&FuncCheck{CheckID: "a.fixable", CheckCategory: "a", CheckSeverity: SeverityError,
+			Fn: func(_ *Context) Result {
+				if broken {
+					return Fail("broken")
+				}
+				return Pass("fixed")
+			},
+			FixFn: func(_ *Context, _ Result) []Fix {
+				return []Fix{
+					{Action: "Repair it", Apply: func() error { broken = false; return nil }},
+					{Action: "Fail to repair it", Apply: func() error { return fmt.Errorf("no luck") }},
+				}
+			}},
+		&FuncCheck{CheckID: "a.declined", CheckCategory: "a", CheckSeverity: SeverityError,
+			Fn: func(_ *Context) Result { return Fail("broken") },
+			FixFn: func(_ *Context, _ Result) []Fix {
+				return []Fix{{Action: "Decline me", Apply: func() error { panic("should not be applied") }}}
+			}},
+		&FuncCheck{CheckID: "a.nofix", CheckCategory: "a", CheckSeverity: SeverityError,
+			Fn: func(_ *Context) Result { return Fail("broken") }},
+	}
+	results := Run(&Context{}, checks)
+	require.True(t, Failed(results, SeverityError))
+
+	var asked []string
+	results = RunFixes(&Context{}, checks, results, func(action string) bool {
+		asked = append(asked, action)
+		return action != "Decline me"
+	})
+	require.Equal(t, []string{"Repair it", "Fail to repair it", "Decline me"}, asked)
+
+	require.Equal(t, StatusPass, results[0].Status)
+	require.Equal(t, "a.fixable", results[0].ID)
+	require.Equal(t, []FixOutcome{
+		{Action: "Repair it", Applied: true},
+		{Action: "Fail to repair it", Error: "no luck"},
+	}, results[0].Fixes)
+
+	require.Equal(t, StatusFail, results[1].Status)
+	require.Equal(t, []FixOutcome{{Action: "Decline me"}}, results[1].Fixes)
+
+	require.Equal(t, StatusFail, results[2].Status)
+	require.Empty(t, results[2].Fixes)
+
+	var b bytes.Buffer
+	require.NoError(t, WriteText(&b, results))
+	require.Contains(t, b.String(), "Repair it (applied)")
+	require.Contains(t, b.String(), "Fail to repair it (failed: no luck)")
+	require.Contains(t, b.String(), "Decline me (declined)")
+}
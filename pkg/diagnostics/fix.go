@@ -0,0 +1,92 @@
+package diagnostics
+
+import (
+	"fmt"
+)
+
+// Fix is an action that remediates a failed check
+type Fix struct {
+	// Action says exactly what Apply will do, it's shown before asking
+	// for confirmation
+	Action string
+	Apply  func() error
+}
+
+// Fixer is implemented by checks that know how to fix some of their failures
+type Fixer interface {
+	// Fixes returns the fixes for a failed result of the check, if any
+	Fixes(ctx *Context, r Result) []Fix
+}
+
+// FixOutcome records a fix that was offered for a failed check
+type FixOutcome struct {
+	Action  string `json:"action"`
+	Applied bool   `json:"applied"`
+	Error   string `json:"error,omitempty"`
+}
+
+// Fixes implements Fixer
+func (c *FuncCheck) Fixes(ctx *Context, r Result) []Fix {
+	if c.FixFn == nil {
+		return nil
+	}
+	return c.FixFn(ctx, r)
+}
+
+// RunFixes offers the fixes of each failed check. Every fix is shown to
+// confirm, which decides whether it's applied. A check that had a fix
+// applied is run again, so the returned results show whether it worked.
+func RunFixes(ctx *Context, checks []Check, results []Result, confirm func(action string) bool) []Result {
+	byID := map[string]Check{}
+	for _, c := range checks {
+		byID[c.ID()] = c
+	}
+	fixed := make([]Result, 0, len(results))
+	for _, r := range results {
+		fixer, ok := byID[r.ID].(Fixer)
+		if r.Status != StatusFail || !ok {
+			fixed = append(fixed, r)
+			continue
+		}
+		var outcomes []FixOutcome
+		applied := false
+		for _, f := range fixesOf(ctx, fixer, r) {
+			outcome := FixOutcome{Action: f.Action}
+			if confirm(f.Action) {
+				if err := applyFix(f); err != nil {
+					outcome.Error = err.Error()
+				} else {
+					outcome.Applied = true
+					applied = true
+				}
+			}
+			outcomes = append(outcomes, outcome)
+		}
+		if applied {
+			r = Run(ctx, []Check{byID[r.ID]})[0]
+		}
+		r.Fixes = outcomes
+		fixed = append(fixed, r)
+	}
+	return fixed
+}
+
+// fixesOf gets the fixes for a result, treating a panic like no fixes
+func fixesOf(ctx *Context, fixer Fixer, r Result) (fixes []Fix) {
+	defer func() {
+		if p := recover(); p != nil {
+			fixes = nil
+		}
+	}()
+	return fixer.Fixes(ctx, r)
+}
+
+// applyFix applies a fix, turning a panic into an error
+func applyFix(f Fix) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("fix panicked: %v", p)
+		}
+	}()
+	return f.Apply()
+}
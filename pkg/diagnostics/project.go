@@ -0,0 +1,162 @@
+package diagnostics
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/ddev/ddev/pkg/ddevapp"
+	"github.com/ddev/ddev/pkg/exec"
+	"github.com/ddev/ddev/pkg/util"
+	"go.yaml.in/yaml/v4"
+)
+
+// ProjectChecksDir is the directory in .ddev where add-ons and projects
+// put their own checks, one or more per *.yaml file
+const ProjectChecksDir = "diagnostics"
+
+// checkIDRegex limits check IDs and categories to names that are safe in
+// flags and JUnit reports
+var checkIDRegex = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*$`)
+
+// projectChecksFile is the structure of a .ddev/diagnostics/*.yaml file
+type projectChecksFile struct {
+	Checks []ProjectCheck `yaml:"checks"`
+}
+
+// ProjectCheck is a check defined in .ddev/diagnostics, usually by an add-on.
+// It passes when its command exits with status 0.
+type ProjectCheck struct {
+	CheckID          string `yaml:"id"`
+	CheckCategory    string `yaml:"category"`
+	CheckSeverity    string `yaml:"severity,omitempty"`
+	CheckDescription string `yaml:"description,omitempty"`
+	Remediation      string `yaml:"remediation,omitempty"`
+	// Exec runs in the Service container of the running project
+	Exec    string `yaml:"exec,omitempty"`
+	Service string `yaml:"service,omitempty"`
+	// ExecHost runs on the host in the project root
+	ExecHost string `yaml:"exec-host,omitempty"`
+	// Source is the file the check was loaded from
+	Source string `yaml:"-"`
+}
+
+// ID implements Check
+func (c *ProjectCheck) ID() string { return c.CheckID }
+
+// Category implements Check
+func (c *ProjectCheck) Category() string { return c.CheckCategory }
+
+// Severity implements Check
+func (c *ProjectCheck) Severity() Severity {
+	if c.CheckSeverity == "" {
+		return SeverityWarning
+	}
+	return Severity(c.CheckSeverity)
+}
+
+// Description implements Check
+func (c *ProjectCheck) Description() string {
+	if c.CheckDescription == "" {
+		return "Check from " + c.Source
+	}
+	return c.CheckDescription
+}
+
+// Run implements Check
+func (c *ProjectCheck) Run(ctx *Context) Result {
+	if ctx.App == nil {
+		return Skip("not in a DDEV project")
+	}
+	var out string
+	var err error
+	if c.ExecHost != "" {
+		bashPath := util.FindBashPath()
+		out, err = exec.RunHostCommandWithOptions(bashPath, []exec.CmdOption{exec.WithDir(ctx.App.AppRoot)}, "-c", c.ExecHost)
+	} else {
+		if status, _ := ctx.App.SiteStatus(); status != ddevapp.SiteRunning {
+			return Skip("project %s is not running", ctx.App.Name)
+		}
+		service := c.Service
+		if service == "" {
+			service = "web"
+		}
+		var stdout, stderr string
+		stdout, stderr, err = ctx.App.Exec(&ddevapp.ExecOpts{Service: service, Cmd: c.Exec})
+		out = stdout + stderr
+	}
+	r := Result{Status: StatusPass, Output: strings.TrimSpace(out)}
+	if err != nil {
+		r.Status = StatusFail
+		r.Message = fmt.Sprintf("command failed: %v", err)
+		r.Remediation = c.Remediation
+	}
+	return r
+}
+
+// validate checks a project check definition
+func (c *ProjectCheck) validate() error {
+	if !checkIDRegex.MatchString(c.CheckID) {
+		return fmt.Errorf("invalid id '%s'", c.CheckID)
+	}
+	if !checkIDRegex.MatchString(c.CheckCategory) {
+		return fmt.Errorf("check %s has an invalid category '%s'", c.CheckID, c.CheckCategory)
+	}
+	if c.CheckSeverity != "" && !IsValidSeverity(c.CheckSeverity) {
+		return fmt.Errorf("check %s has an invalid severity '%s', use info, warning, or error", c.CheckID, c.CheckSeverity)
+	}
+	if (c.Exec == "") == (c.ExecHost == "") {
+		return fmt.Errorf("check %s needs exactly one of exec and exec-host", c.CheckID)
+	}
+	return nil
+}
+
+// LoadProjectChecks reads the checks in the project's .ddev/diagnostics
+// directory. Checks that are invalid or reuse an ID of the existing checks
+// are reported as warnings and left out.
+func LoadProjectChecks(app *ddevapp.DdevApp, existing []Check) ([]Check, error) {
+	if app == nil || app.AppRoot == "" {
+		return nil, nil
+	}
+	files, err := filepath.Glob(filepath.Join(app.GetConfigPath(ProjectChecksDir), "*.yaml"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	used := map[string]bool{}
+	for _, c := range existing {
+		used[c.ID()] = true
+	}
+	var checks []Check
+	for _, f := range files {
+		b, err := os.ReadFile(f)
+		if err != nil {
+			return nil, err
+		}
+		var parsed projectChecksFile
+		if err = yaml.Unmarshal(b, &parsed); err != nil {
+			util.Warning("Unable to parse %s: %v", f, err)
+			continue
+		}
+		source := filepath.Join(".ddev", ProjectChecksDir, filepath.Base(f))
+		for i := range parsed.Checks {
+			c := &parsed.Checks[i]
+			c.Source = source
+			if err = c.validate(); err != nil {
+				util.Warning("Ignoring check in %s: %v", source, err)
+				continue
+			}
+			if used[c.CheckID] {
+				util.Warning("Ignoring check %s in %s: a check with this id already exists", c.CheckID, source)
+				continue
+			}
+			used[c.CheckID] = true
+			checks = append(checks, c)
+		}
+	}
+	return checks, nil
+}
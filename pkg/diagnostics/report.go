@@ -0,0 +1,220 @@
+package diagnostics
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ddev/ddev/pkg/util"
+)
+
+// Report formats
+const (
+	FormatText  = "text"
+	FormatJSON  = "json"
+	FormatJUnit = "junit"
+)
+
+// ValidFormats are the formats WriteReport understands
+var ValidFormats = []string{FormatText, FormatJSON, FormatJUnit}
+
+// WriteReport writes the results in the given format
+func WriteReport(w io.Writer, format string, results []Result) error {
+	switch format {
+	case FormatText:
+		return WriteText(w, results)
+	case FormatJSON:
+		return WriteJSON(w, results)
+	case FormatJUnit:
+		return WriteJUnit(w, results)
+	}
+	return fmt.Errorf("unknown format '%s', valid formats are %s", format, strings.Join(ValidFormats, ", "))
+}
+
+// jsonReport is the document written by WriteJSON
+type jsonReport struct {
+	Summary summary  `json:"summary"`
+	Results []Result `json:"results"`
+}
+
+// summary counts results by status
+type summary struct {
+	Total   int `json:"total"`
+	Passed  int `json:"passed"`
+	Failed  int `json:"failed"`
+	Skipped int `json:"skipped"`
+}
+
+// summarize counts the results by status
+func summarize(results []Result) summary {
+	s := summary{Total: len(results)}
+	for _, r := range results {
+		switch r.Status {
+		case StatusPass:
+			s.Passed++
+		case StatusFail:
+			s.Failed++
+		case StatusSkip:
+			s.Skipped++
+		}
+	}
+	return s
+}
+
+// WriteJSON writes the results as a JSON document with a summary
+func WriteJSON(w io.Writer, results []Result) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(jsonReport{Summary: summarize(results), Results: results})
+}
+
+// junitTestSuites is the root of a JUnit XML report
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+// junitTestSuite holds the checks of one category
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Skipped  int             `xml:"skipped,attr"`
+	Time     string          `xml:"time,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+// junitTestCase is a single check
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Skipped   *junitSkipped `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+// junitFailure describes a failed check
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// junitSkipped describes a skipped check
+type junitSkipped struct {
+	Message string `xml:"message,attr"`
+}
+
+// junitSeconds formats a duration in milliseconds as JUnit seconds
+func junitSeconds(ms int64) string {
+	return fmt.Sprintf("%.3f", float64(ms)/1000)
+}
+
+// WriteJUnit writes the results as JUnit XML, with a test suite per category
+func WriteJUnit(w io.Writer, results []Result) error {
+	report := junitTestSuites{Name: "ddev diagnose"}
+	suiteIndex := map[string]int{}
+	suiteMS := map[string]int64{}
+	for _, r := range results {
+		i, ok := suiteIndex[r.Category]
+		if !ok {
+			report.Suites = append(report.Suites, junitTestSuite{Name: r.Category})
+			i = len(report.Suites) - 1
+			suiteIndex[r.Category] = i
+		}
+		suite := &report.Suites[i]
+		tc := junitTestCase{
+			Name:      r.ID,
+			ClassName: "ddev.diagnose." + r.Category,
+			Time:      junitSeconds(r.DurationMS),
+			SystemOut: r.Output,
+		}
+		switch r.Status {
+		case StatusFail:
+			text := r.Message
+			if r.Remediation != "" {
+				text = text + "\nRemediation: " + r.Remediation
+			}
+			tc.Failure = &junitFailure{Message: r.Message, Type: string(r.Severity), Text: text}
+			suite.Failures++
+			report.Failures++
+		case StatusSkip:
+			tc.Skipped = &junitSkipped{Message: r.Message}
+			suite.Skipped++
+			report.Skipped++
+		}
+		suite.Cases = append(suite.Cases, tc)
+		suite.Tests++
+		report.Tests++
+		suiteMS[r.Category] += r.DurationMS
+	}
+	for i := range report.Suites {
+		report.Suites[i].Time = junitSeconds(suiteMS[report.Suites[i].Name])
+	}
+
+	_, err := io.WriteString(w, xml.Header)
+	if err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err = enc.Encode(report); err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, "\n")
+	return err
+}
+
+// WriteText writes the results for people, grouped by category
+func WriteText(w io.Writer, results []Result) error {
+	var b strings.Builder
+	category := ""
+	for _, r := range results {
+		if r.Category != category {
+			category = r.Category
+			_, _ = fmt.Fprintf(&b, "\n%s\n", category)
+		}
+		mark := util.ColorizeText("✓", "green")
+		switch {
+		case r.Status == StatusSkip:
+			mark = "-"
+		case r.Status == StatusFail && r.Severity == SeverityError:
+			mark = util.ColorizeText("✗", "red")
+		case r.Status == StatusFail:
+			mark = util.ColorizeText("!", "yellow")
+		}
+		_, _ = fmt.Fprintf(&b, "  %s %s: %s\n", mark, r.ID, r.Description)
+		if r.Message != "" {
+			_, _ = fmt.Fprintf(&b, "      %s\n", r.Message)
+		}
+		if r.Status == StatusFail && r.Output != "" {
+			for line := range strings.SplitSeq(r.Output, "\n") {
+				_, _ = fmt.Fprintf(&b, "        %s\n", line)
+			}
+		}
+		if r.Status == StatusFail && r.Remediation != "" {
+			_, _ = fmt.Fprintf(&b, "      Fix: %s\n", r.Remediation)
+		}
+		for _, f := range r.Fixes {
+			state := "applied"
+			switch {
+			case f.Error != "":
+				state = "failed: " + f.Error
+			case !f.Applied:
+				state = "declined"
+			}
+			_, _ = fmt.Fprintf(&b, "      %s (%s)\n", f.Action, state)
+		}
+	}
+	s := summarize(results)
+	_, _ = fmt.Fprintf(&b, "\n%d checks: %d passed, %d failed, %d skipped\n", s.Total, s.Passed, s.Failed, s.Skipped)
+	_, err := io.WriteString(w, b.String())
+	return err
+}
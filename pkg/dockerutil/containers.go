@@ -891,6 +891,17 @@ func RemoveContainer(id string) error {
 	return err
 }
 
+// StopContainer stops a container, giving it the default timeout to shut down
+func StopContainer(id string) error {
+	ctx, apiClient, err := GetDockerClient()
+	if err != nil {
+		return err
+	}
+
+	_, err = apiClient.ContainerStop(ctx, id, client.ContainerStopOptions{})
+	return err
+}
+
 // RemoveContainersByLabels removes all containers that match a set of labels
 func RemoveContainersByLabels(labels map[string]string) error {
 	ctx, apiClient, err := GetDockerClient()
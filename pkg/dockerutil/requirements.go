@@ -81,6 +81,12 @@ func CanRunWithoutDocker() bool {
 	if slices.Contains([]string{"config", "help", "hostname", "version"}, os.Args[1]) {
 		return true
 	}
+	// `ddev utility diagnose` reports Docker problems itself,
+	// `ddev utility support-bundle` records them in the bundle, and
+	// `ddev utility xdebug-proxy` runs on the host only
+	if len(os.Args) > 2 && slices.Contains([]string{"utility", "ut", "d", "dbg", "debug"}, os.Args[1]) && slices.Contains([]string{"diagnose", "support-bundle", "xdebug-proxy"}, os.Args[2]) {
+		return true
+	}
 	return false
 }
 
@@ -28,6 +28,13 @@ func WithStdin(stdin io.Reader) CmdOption {
 	}
 }
 
+// WithDir sets the working directory for the host command
+func WithDir(dir string) CmdOption {
+	return func(cmd *exec.Cmd) {
+		cmd.Dir = dir
+	}
+}
+
 // WithEnv sets the environment variables for the host command
 func WithEnv(env []string) CmdOption {
 	return func(cmd *exec.Cmd) {
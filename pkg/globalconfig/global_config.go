@@ -42,11 +42,17 @@ type GlobalConfig struct {
 	DeveloperMode                    bool                        `yaml:"developer_mode,omitempty"`
 	DockerBuildxVersion              string                      `yaml:"docker_buildx_version,omitempty"`
 	FailOnHookFailGlobal             bool                        `yaml:"fail_on_hook_fail"`
+	IdleSnapshot                     bool                        `yaml:"idle_snapshot,omitempty"`
+	IdleTimeout                      string                      `yaml:"idle_timeout,omitempty"`
+	InstrumentationFile              string                      `yaml:"instrumentation_file,omitempty"`
 	InstrumentationOptIn             bool                        `yaml:"instrumentation_opt_in"`
+	InstrumentationOTLPEndpoint      string                      `yaml:"instrumentation_otlp_endpoint,omitempty"`
+	InstrumentationOTLPHeaders       map[string]string           `yaml:"instrumentation_otlp_headers,omitempty"`
 	InstrumentationQueueSize         int                         `yaml:"instrumentation_queue_size,omitempty"`
 	InstrumentationReportingInterval time.Duration               `yaml:"instrumentation_reporting_interval,omitempty"`
 	InstrumentationUser              string                      `yaml:"instrumentation_user,omitempty"`
 	InternetDetectionTimeout         int64                       `yaml:"internet_detection_timeout_ms"`
+	KeepRunning                      []string                    `yaml:"keep_running,omitempty,flow"`
 	LastStartedVersion               string                      `yaml:"last_started_version"`
 	LetsEncryptEmail                 string                      `yaml:"letsencrypt_email"`
 	Messages                         MessagesConfig              `yaml:"messages,omitempty"`
@@ -67,6 +73,7 @@ type GlobalConfig struct {
 	RouterMailpitHTTPSPort           string                      `yaml:"mailpit_https_port,omitempty"`
 	RouterXHGuiHTTPPort              string                      `yaml:"xhgui_http_port,omitempty"`
 	RouterXHGuiHTTPSPort             string                      `yaml:"xhgui_https_port,omitempty"`
+	SecretsCommand                   string                      `yaml:"secrets_command,omitempty"`
 	ShareDefaultProvider             string                      `yaml:"share_default_provider,omitempty"`
 	SimpleFormatting                 bool                        `yaml:"simple_formatting"`
 	TableStyle                       string                      `yaml:"table_style"`
@@ -76,6 +83,7 @@ type GlobalConfig struct {
 	WSL2NoWindowsHostsMgt            bool                        `yaml:"wsl2_no_windows_hosts_mgt"`
 	WebEnvironment                   []string                    `yaml:"web_environment"`
 	XdebugIDELocation                string                      `yaml:"xdebug_ide_location"`
+	XdebugProxy                      bool                        `yaml:"xdebug_proxy,omitempty"`
 	XHProfMode                       configTypes.XHProfMode      `yaml:"xhprof_mode,omitempty"`
 	ProjectList                      map[string]*ProjectInfo     `yaml:"project_info,omitempty"`
 }
@@ -127,6 +135,24 @@ func GetGlobalConfigPath() string {
 	return filepath.Join(GetGlobalDdevDir(), DdevGlobalConfigName)
 }
 
+// GetInstrumentationFilePath returns the path of instrumentation_file, or
+// an empty string if it's not set. Relative paths are in the global DDEV directory.
+func GetInstrumentationFilePath() string {
+	f := DdevGlobalConfig.InstrumentationFile
+	if f == "" {
+		return ""
+	}
+	if f == "~" || strings.HasPrefix(f, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			f = filepath.Join(home, strings.TrimPrefix(f, "~"))
+		}
+	}
+	if !filepath.IsAbs(f) {
+		f = filepath.Join(GetGlobalDdevDir(), f)
+	}
+	return f
+}
+
 // GetProjectListPath gets the path to global projects file
 func GetProjectListPath() string {
 	return filepath.Join(GetGlobalDdevDir(), DdevProjectListFileName)
@@ -220,9 +246,49 @@ func ValidateGlobalConfig() error {
 		return fmt.Errorf(`xdebug_ide_location must be IP address or one of %v`, ValidXdebugIDELocations)
 	}
 
+	if endpoint := DdevGlobalConfig.InstrumentationOTLPEndpoint; endpoint != "" && !strings.HasPrefix(endpoint, "http://") && !strings.HasPrefix(endpoint, "https://") {
+		return fmt.Errorf("instrumentation_otlp_endpoint must be an http:// or https:// URL, like http://localhost:4318, not '%s'", endpoint)
+	}
+
+	if DdevGlobalConfig.IdleTimeout != "" {
+		if timeout, err := time.ParseDuration(DdevGlobalConfig.IdleTimeout); err != nil || timeout < time.Minute {
+			return fmt.Errorf("idle_timeout must be a duration of at least a minute, like 30m or 2h, not '%s'", DdevGlobalConfig.IdleTimeout)
+		}
+	}
+
+	if err := CheckPolicyLocks(DdevGlobalConfig); err != nil {
+		return err
+	}
+
 	return nil
 }
 
+// loadGlobalConfigWithPolicy loads the global config file into
+// DdevGlobalConfig, with the organization policy, if any, merged in
+func loadGlobalConfigWithPolicy(globalConfigFile string) error {
+	policy, err := LoadPolicy()
+	if err != nil {
+		return err
+	}
+	DdevPolicy = policy
+	content, err := os.ReadFile(globalConfigFile)
+	if err != nil {
+		return err
+	}
+	content, err = applyPolicy(policy, content, globalConfigFile)
+	if err != nil {
+		return err
+	}
+	if policy == nil {
+		return settings.LoadGlobalConfig(globalConfigFile, &DdevGlobalConfig)
+	}
+	cfg := settings.NewConfigProvider()
+	if err = cfg.ReadConfigFromBytes(content); err != nil {
+		return err
+	}
+	return cfg.Unmarshal(&DdevGlobalConfig)
+}
+
 // ReadGlobalConfig reads the global config file into DdevGlobalConfig
 // Or creates the file
 func ReadGlobalConfig() error {
@@ -249,7 +315,7 @@ func ReadGlobalConfig() error {
 	}
 
 	// Load global config using unified settings loader.
-	err = settings.LoadGlobalConfig(globalConfigFile, &DdevGlobalConfig)
+	err = loadGlobalConfigWithPolicy(globalConfigFile)
 	if err != nil {
 		return fmt.Errorf("unable to load DDEV global config file %s: %v", globalConfigFile, err)
 	}
@@ -358,6 +424,10 @@ func WriteGlobalConfig(config GlobalConfig) error {
 	if err != nil {
 		return err
 	}
+	cfgbytes, err = removePolicyValues(cfgbytes)
+	if err != nil {
+		return err
+	}
 
 	// Append current image information
 	instructions := `
@@ -418,6 +488,13 @@ func WriteGlobalConfig(config GlobalConfig) error {
 # web_environment:
 #   - SOMEENV=somevalue
 #   - SOMEOTHERENV=someothervalue
+#   - API_TOKEN=secret:api-token
+
+# secrets_command: ""
+# Command printing the value of a secret referenced with 'secret:NAME' that
+# isn't in the local store managed with 'ddev secret', where {name} is
+# replaced with the name of the secret, like "pass show ddev/{name}" or
+# "op read op://dev/{name}/password".
 
 # Adjust the default table style used in ddev list and describe
 # table_style: default
@@ -477,6 +554,21 @@ func WriteGlobalConfig(config GlobalConfig) error {
 # If using VS Code Language Server, which listens inside the container
 # then set xdebug_ide_location: "container"
 
+# idle_timeout: 2h
+# keep_running: [important-project, client-*]
+# idle_snapshot: false
+# Stop the projects that haven't had an HTTP request, 'ddev exec' or 'ddev ssh'
+# for idle_timeout, except the ones named or matched in keep_running. An
+# idle manager runs in the background to do this, taking a database snapshot
+# first with idle_snapshot: true. A request to a project it stopped shows a
+# "starting" page and starts the project again.
+
+# xdebug_proxy: false
+# Run a DBGp proxy on the host, so several IDEs, projects or developers can
+# use Xdebug at the same time. Xdebug connects to the proxy on port 9003,
+# which routes each connection by its IDE key (the project name) to the IDE
+# registered for it on port 9001. The IDE must listen on another port than 9003.
+
 # Let's Encrypt:
 # This integration is entirely experimental; your mileage may vary.
 # * Your host must be directly internet-connected.
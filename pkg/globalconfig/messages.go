@@ -3,4 +3,7 @@ package globalconfig
 // MessagesConfig is the struct defining the messages config.
 type MessagesConfig struct {
 	TickerInterval int `yaml:"ticker_interval,omitempty"`
+	// Sources are extra files or URLs with messages in the format of the
+	// remote config, shown in addition to DDEV's own messages.
+	Sources []string `yaml:"sources,omitempty"`
 }
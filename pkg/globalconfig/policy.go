@@ -0,0 +1,522 @@
+package globalconfig
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ddev/ddev/pkg/nodeps"
+	"github.com/ddev/ddev/pkg/output"
+	"go.yaml.in/yaml/v4"
+)
+
+const (
+	// policyCacheFileName is the local copy of a policy downloaded from a URL
+	policyCacheFileName = ".policy-cache.yaml"
+	// policyUpdateInterval is how often a policy is downloaded, in hours,
+	// unless the policy sets update_interval
+	policyUpdateInterval = 10
+	// policyDownloadTimeout keeps an unreachable policy URL from blocking every command
+	policyDownloadTimeout = 5 * time.Second
+)
+
+// Policy is an organization-level layer of global configuration, managed
+// by an administrator in a system-wide file or at a URL. Its defaults are
+// merged under the user's global config, its locked values can't be
+// overridden by the user.
+type Policy struct {
+	// URL of a policy to download, values in the file override the downloaded ones
+	URL string `yaml:"url,omitempty"`
+	// UpdateInterval is how often the policy is downloaded from URL, in hours
+	UpdateInterval int `yaml:"update_interval,omitempty"`
+	// DdevVersionConstraint is a semver constraint DDEV must meet to start projects
+	DdevVersionConstraint string `yaml:"ddev_version_constraint,omitempty"`
+	// AllowedShareProviders are the only providers 'ddev share' may use
+	AllowedShareProviders []string `yaml:"allowed_share_providers,omitempty"`
+	// RequiredRegistryMirrors must be configured in the Docker provider
+	RequiredRegistryMirrors []string `yaml:"required_registry_mirrors,omitempty"`
+	// Defaults are global config values used unless the user sets their own
+	Defaults map[string]any `yaml:"defaults,omitempty"`
+	// Locked are global config values the user can't change
+	Locked map[string]any `yaml:"locked,omitempty"`
+
+	// Source describes where the policy was read from
+	Source string `yaml:"-"`
+	// DownloadError is why the policy at URL couldn't be downloaded, with
+	// no copy downloaded before. Projects can't start until it's fixed.
+	DownloadError error `yaml:"-"`
+}
+
+// Kinds of ConfigOrigin
+const (
+	OriginDefault = "default"
+	OriginUser    = "user"
+	OriginPolicy  = "policy"
+	OriginLocked  = "locked"
+)
+
+// ConfigOrigin describes where a global config value came from
+type ConfigOrigin struct {
+	Kind   string `json:"kind"`
+	Source string `json:"source,omitempty"`
+}
+
+// String returns the origin like "user:/home/me/.ddev/global_config.yaml"
+func (o ConfigOrigin) String() string {
+	if o.Source == "" {
+		return o.Kind
+	}
+	return o.Kind + ":" + o.Source
+}
+
+var (
+	// DdevPolicy is the active organization policy, nil if there is none
+	DdevPolicy *Policy
+	// configOrigins maps dotted global config keys to where their value came from
+	configOrigins = map[string]ConfigOrigin{}
+	// policyUserValues are the user's own values of keys the policy sets
+	policyUserValues = map[string]any{}
+	// policyConflicts describes user values that were overridden by locked ones
+	policyConflicts []string
+)
+
+// GetPolicyPath returns the location of the organization policy, which is
+// $DDEV_POLICY if set, a file or a URL, or else the system-wide policy file
+func GetPolicyPath() string {
+	if p := os.Getenv("DDEV_POLICY"); p != "" {
+		return p
+	}
+	if nodeps.IsWindows() {
+		return filepath.Join(os.Getenv("ProgramData"), "ddev", "policy.yaml")
+	}
+	return "/etc/ddev/policy.yaml"
+}
+
+// LoadPolicy reads the organization policy, downloading it if it has a URL.
+// It returns nil if there is no policy. A policy that can't be downloaded
+// falls back to the last downloaded copy, and without one it has a
+// DownloadError, so projects don't start without the organization's values.
+func LoadPolicy() (*Policy, error) {
+	location := GetPolicyPath()
+	p := &Policy{}
+	var sources []string
+	if isPolicyURL(location) {
+		p.URL = location
+	} else {
+		content, err := os.ReadFile(location)
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to read policy %s: %v", location, err)
+		}
+		if err = yaml.Unmarshal(content, p); err != nil {
+			return nil, fmt.Errorf("invalid policy %s: %v", location, err)
+		}
+		sources = append(sources, location)
+	}
+
+	if p.URL != "" {
+		remote, err := downloadPolicy(p.URL, p.UpdateInterval)
+		if err != nil {
+			output.UserErr.Warnf("Unable to load the policy from %s, projects can't be started until it can be downloaded: %v", p.URL, err)
+			p.DownloadError = err
+		} else {
+			p = remote.overriddenBy(p)
+		}
+		sources = append(sources, p.URL)
+	}
+	p.Source = strings.Join(sources, ", ")
+	if p.Source == "" {
+		return nil, nil
+	}
+	if err := p.validate(); err != nil {
+		return nil, fmt.Errorf("invalid policy %s: %v", p.Source, err)
+	}
+	return p, nil
+}
+
+// isPolicyURL reports whether the policy location is a URL rather than a file
+func isPolicyURL(location string) bool {
+	return strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://")
+}
+
+// downloadPolicy returns the policy at url, downloading it if the cached
+// copy is older than the update interval or was downloaded from another URL
+func downloadPolicy(url string, updateInterval int) (*Policy, error) {
+	if updateInterval <= 0 {
+		updateInterval = policyUpdateInterval
+	}
+	cacheFile := filepath.Join(GetGlobalDdevDir(), policyCacheFileName)
+	// The first line of the cache records the URL it was downloaded from
+	header := "# Downloaded from " + url + "\n"
+	cached, cacheErr := os.ReadFile(cacheFile)
+	if cacheErr == nil && !strings.HasPrefix(string(cached), header) {
+		cacheErr = fmt.Errorf("%s was downloaded from another URL", cacheFile)
+	}
+	stale := true
+	if stat, err := os.Stat(cacheFile); cacheErr == nil && err == nil {
+		stale = stat.ModTime().Add(time.Duration(updateInterval) * time.Hour).Before(time.Now())
+	}
+
+	content := cached
+	if stale {
+		downloaded, err := fetchPolicy(url)
+		switch {
+		case err == nil:
+			content = []byte(header + string(downloaded))
+			if writeErr := os.WriteFile(cacheFile, content, 0644); writeErr != nil {
+				output.UserErr.Debugf("Unable to cache policy in %s: %v", cacheFile, writeErr)
+			}
+		case cacheErr != nil:
+			return nil, err
+		default:
+			output.UserErr.Debugf("Unable to download policy from %s, using the copy from %s: %v", url, cacheFile, err)
+		}
+	}
+
+	p := &Policy{}
+	if err := yaml.Unmarshal(content, p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// fetchPolicy downloads the policy at url and checks that it can be parsed
+func fetchPolicy(url string) ([]byte, error) {
+	client := &http.Client{Timeout: policyDownloadTimeout}
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s returned %s", url, resp.Status)
+	}
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if err = yaml.Unmarshal(content, &Policy{}); err != nil {
+		return nil, fmt.Errorf("invalid policy at %s: %v", url, err)
+	}
+	return content, nil
+}
+
+// overriddenBy returns a copy of the policy with the values set in local
+func (p *Policy) overriddenBy(local *Policy) *Policy {
+	merged := *p
+	merged.URL = local.URL
+	merged.UpdateInterval = local.UpdateInterval
+	if local.DdevVersionConstraint != "" {
+		merged.DdevVersionConstraint = local.DdevVersionConstraint
+	}
+	if len(local.AllowedShareProviders) > 0 {
+		merged.AllowedShareProviders = local.AllowedShareProviders
+	}
+	if len(local.RequiredRegistryMirrors) > 0 {
+		merged.RequiredRegistryMirrors = local.RequiredRegistryMirrors
+	}
+	merged.Defaults = mergeConfigMaps(p.Defaults, local.Defaults)
+	merged.Locked = mergeConfigMaps(p.Locked, local.Locked)
+	return &merged
+}
+
+// mergeConfigMaps returns base with the values of override set on top
+func mergeConfigMaps(base map[string]any, override map[string]any) map[string]any {
+	merged := map[string]any{}
+	for _, m := range []map[string]any{base, override} {
+		for k, v := range flattenConfig(m) {
+			setConfigValue(merged, k, v)
+		}
+	}
+	return merged
+}
+
+// validate checks that the policy only sets known global config keys
+func (p *Policy) validate() error {
+	known := map[string]bool{}
+	t := reflect.TypeFor[GlobalConfig]()
+	for i := 0; i < t.NumField(); i++ {
+		known[strings.Split(t.Field(i).Tag.Get("yaml"), ",")[0]] = true
+	}
+	delete(known, "project_info")
+	for section, values := range map[string]map[string]any{"defaults": p.Defaults, "locked": p.Locked} {
+		for k := range values {
+			if !known[k] {
+				return fmt.Errorf("%s contains '%s', which is not a global config key", section, k)
+			}
+		}
+	}
+	return nil
+}
+
+// AllowsShareProvider reports whether 'ddev share' may use the provider
+func (p *Policy) AllowsShareProvider(provider string) bool {
+	return p == nil || len(p.AllowedShareProviders) == 0 || slices.Contains(p.AllowedShareProviders, provider)
+}
+
+// applyPolicy merges the policy into the content of the user's global
+// config file and records where each value came from. Policy defaults
+// replace values that aren't in the user's file.
+func applyPolicy(p *Policy, content []byte, configFile string) ([]byte, error) {
+	user := map[string]any{}
+	if err := yaml.Unmarshal(content, &user); err != nil {
+		return nil, fmt.Errorf("unable to parse %s: %v", configFile, err)
+	}
+	userValues := flattenConfig(user)
+	configOrigins = map[string]ConfigOrigin{}
+	for k := range userValues {
+		configOrigins[k] = ConfigOrigin{Kind: OriginUser, Source: configFile}
+	}
+	policyUserValues = map[string]any{}
+	policyConflicts = nil
+	if p == nil {
+		return content, nil
+	}
+
+	isUserSet := func(k string) bool {
+		v, ok := userValues[k]
+		if ok {
+			policyUserValues[k] = v
+		}
+		return ok
+	}
+	builtin := map[string]any{}
+	if b, err := yaml.Marshal(New()); err == nil {
+		_ = yaml.Unmarshal(b, &builtin)
+	}
+	builtinValues := flattenConfig(builtin)
+
+	for k, v := range flattenConfig(p.Defaults) {
+		if isUserSet(k) {
+			continue
+		}
+		setConfigValue(user, k, v)
+		configOrigins[k] = ConfigOrigin{Kind: OriginPolicy, Source: p.Source}
+	}
+	locked := flattenConfig(p.Locked)
+	keys := make([]string, 0, len(locked))
+	for k := range locked {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		v := locked[k]
+		// DDEV writes its defaults to the file too, only other values conflict
+		if isUserSet(k) && !sameConfigValue(userValues[k], v) && !isBuiltinConfigValue(builtinValues, k, userValues[k]) {
+			policyConflicts = append(policyConflicts, fmt.Sprintf("%s is locked to '%v' by the policy, but is set to '%v' in %s, please remove it", k, v, userValues[k], configFile))
+		}
+		setConfigValue(user, k, v)
+		configOrigins[k] = ConfigOrigin{Kind: OriginLocked, Source: p.Source}
+	}
+	return yaml.Marshal(user)
+}
+
+// removePolicyValues keeps values that come from the policy out of the
+// user's global config file, so later changes to the policy take effect.
+// The user's own values of those keys are written back as they were.
+// It edits the YAML document so the order of the keys is kept.
+func removePolicyValues(content []byte) ([]byte, error) {
+	if DdevPolicy == nil {
+		return content, nil
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return nil, err
+	}
+	if len(doc.Content) == 0 {
+		return content, nil
+	}
+	changed := false
+	for k, v := range flattenConfig(mergeConfigMaps(DdevPolicy.Defaults, DdevPolicy.Locked)) {
+		path := lookupConfigNode(doc.Content[0], strings.Split(k, "."))
+		if path == nil {
+			continue
+		}
+		last := path[len(path)-1]
+		var current any
+		if err := last.mapping.Content[last.index+1].Decode(&current); err != nil || !sameConfigValue(current, v) {
+			continue
+		}
+		changed = true
+		if userValue, ok := policyUserValues[k]; ok {
+			var n yaml.Node
+			if err := n.Encode(userValue); err != nil {
+				return nil, err
+			}
+			last.mapping.Content[last.index+1] = &n
+			continue
+		}
+		// Remove the key, and mappings that are left empty, like remote_config
+		for i := len(path) - 1; i >= 0; i-- {
+			p := path[i]
+			p.mapping.Content = append(p.mapping.Content[:p.index], p.mapping.Content[p.index+2:]...)
+			if len(p.mapping.Content) > 0 {
+				break
+			}
+		}
+	}
+	if !changed {
+		return content, nil
+	}
+	return yaml.Marshal(&doc)
+}
+
+// configNodeKey is a key in a YAML mapping node
+type configNodeKey struct {
+	mapping *yaml.Node
+	index   int
+}
+
+// lookupConfigNode returns the keys leading to a dotted key in a YAML
+// mapping node, or nil if it isn't there
+func lookupConfigNode(mapping *yaml.Node, parts []string) []configNodeKey {
+	var path []configNodeKey
+	for n, part := range parts {
+		if mapping.Kind != yaml.MappingNode {
+			return nil
+		}
+		found := -1
+		for i := 0; i+1 < len(mapping.Content); i += 2 {
+			if mapping.Content[i].Value == part {
+				found = i
+				break
+			}
+		}
+		if found < 0 {
+			return nil
+		}
+		path = append(path, configNodeKey{mapping: mapping, index: found})
+		if n < len(parts)-1 {
+			mapping = mapping.Content[found+1]
+		}
+	}
+	return path
+}
+
+// CheckPolicyLocks returns an error if the config changes a locked value
+func CheckPolicyLocks(config GlobalConfig) error {
+	if DdevPolicy == nil || len(DdevPolicy.Locked) == 0 {
+		return nil
+	}
+	b, err := yaml.Marshal(config)
+	if err != nil {
+		return err
+	}
+	current := map[string]any{}
+	if err = yaml.Unmarshal(b, &current); err != nil {
+		return err
+	}
+	for k, v := range flattenConfig(DdevPolicy.Locked) {
+		if value, ok := getConfigValue(current, k); ok && !sameConfigValue(value, v) {
+			return fmt.Errorf("%s is locked to '%v' by the policy in %s", k, v, DdevPolicy.Source)
+		}
+	}
+	return nil
+}
+
+// GetPolicyConflicts describes the values in the user's global config that
+// were overridden by locked values of the policy
+func GetPolicyConflicts() []string {
+	return policyConflicts
+}
+
+// GetConfigOrigin returns where the value of a global config key, like
+// use_hardened_images or remote_config.addon_data_url, came from. For a key
+// with nested values, like remote_config, a policy origin takes precedence.
+func GetConfigOrigin(key string) ConfigOrigin {
+	if o, ok := configOrigins[key]; ok {
+		return o
+	}
+	origin := ConfigOrigin{Kind: OriginDefault}
+	for k, o := range configOrigins {
+		if !strings.HasPrefix(k, key+".") {
+			continue
+		}
+		switch {
+		case o.Kind == OriginLocked:
+			return o
+		case o.Kind == OriginPolicy || origin.Kind == OriginDefault:
+			origin = o
+		}
+	}
+	return origin
+}
+
+// flattenConfig returns the leaf values of a nested config map, with dotted keys
+func flattenConfig(m map[string]any) map[string]any {
+	flat := map[string]any{}
+	for k, v := range m {
+		if nested, ok := v.(map[string]any); ok && len(nested) > 0 {
+			for nk, nv := range flattenConfig(nested) {
+				flat[k+"."+nk] = nv
+			}
+			continue
+		}
+		flat[k] = v
+	}
+	return flat
+}
+
+// getConfigValue returns the value of a dotted key in a nested config map
+func getConfigValue(m map[string]any, key string) (any, bool) {
+	parts := strings.Split(key, ".")
+	for _, part := range parts[:len(parts)-1] {
+		nested, ok := m[part].(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		m = nested
+	}
+	v, ok := m[parts[len(parts)-1]]
+	return v, ok
+}
+
+// setConfigValue sets a dotted key in a nested config map
+func setConfigValue(m map[string]any, key string, value any) {
+	parts := strings.Split(key, ".")
+	for _, part := range parts[:len(parts)-1] {
+		nested, ok := m[part].(map[string]any)
+		if !ok {
+			nested = map[string]any{}
+			m[part] = nested
+		}
+		m = nested
+	}
+	m[parts[len(parts)-1]] = value
+}
+
+// sameConfigValue compares config values loosely, so 80 and "80" are the same
+func sameConfigValue(a any, b any) bool {
+	return fmt.Sprint(a) == fmt.Sprint(b)
+}
+
+// isBuiltinConfigValue reports whether a value is DDEV's default for the key
+func isBuiltinConfigValue(builtinValues map[string]any, key string, value any) bool {
+	if d, ok := builtinValues[key]; ok {
+		return sameConfigValue(value, d)
+	}
+	return isZeroConfigValue(value)
+}
+
+// isZeroConfigValue reports whether a value is empty, like a field that
+// isn't written to the config file because of omitempty
+func isZeroConfigValue(v any) bool {
+	if v == nil {
+		return true
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Map:
+		return rv.Len() == 0
+	}
+	return rv.IsZero()
+}
@@ -0,0 +1,102 @@
+package globalconfig_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ddev/ddev/pkg/globalconfig"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPolicy checks that a policy from a file and a URL is merged with the
+// user's global config, and kept out of it when it's written
+func TestPolicy(t *testing.T) {
+	t.Cleanup(func() {
+		globalconfig.DdevPolicy = nil
+		globalconfig.EnsureGlobalConfig()
+	})
+
+	remotePolicy := `
+ddev_version_constraint: ">= v1.24.0"
+allowed_share_providers: [ngrok]
+defaults:
+  table_style: bright
+  simple_formatting: true
+  remote_config:
+    addon_data_url: https://addons.example.com/addons.json
+locked:
+  router_http_port: "80"
+  use_hardened_images: true
+`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(remotePolicy))
+	}))
+	defer server.Close()
+
+	tmpDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, "ddev"), 0755))
+	t.Setenv("DDEV_XDG_CONFIG_HOME", tmpDir)
+	policyFile := filepath.Join(tmpDir, "policy.yaml")
+	require.NoError(t, os.WriteFile(policyFile, []byte("url: "+server.URL+"\nallowed_share_providers: [cloudflared]\n"), 0644))
+	t.Setenv("DDEV_POLICY", policyFile)
+
+	userConfig := "router_http_port: \"8080\"\nsimple_formatting: false\nuse_hardened_images: false\n"
+	configFile := globalconfig.GetGlobalConfigPath()
+	require.NoError(t, os.WriteFile(configFile, []byte(userConfig), 0644))
+
+	require.NoError(t, globalconfig.ReadGlobalConfig())
+	policy := globalconfig.DdevPolicy
+	require.NotNil(t, policy)
+	require.Equal(t, policyFile+", "+server.URL, policy.Source)
+	require.Equal(t, ">= v1.24.0", policy.DdevVersionConstraint)
+	require.True(t, policy.AllowsShareProvider("cloudflared"))
+	require.False(t, policy.AllowsShareProvider("ngrok"))
+
+	// table_style isn't set by the user, so the policy default is used, but
+	// simple_formatting is, even though false is DDEV's default
+	require.Equal(t, "bright", globalconfig.DdevGlobalConfig.TableStyle)
+	require.False(t, globalconfig.DdevGlobalConfig.SimpleFormatting)
+	require.Equal(t, "https://addons.example.com/addons.json", globalconfig.DdevGlobalConfig.RemoteConfig.AddonDataURL)
+	require.Equal(t, "80", globalconfig.DdevGlobalConfig.RouterHTTPPort)
+	require.True(t, globalconfig.DdevGlobalConfig.UseHardenedImages)
+
+	require.Equal(t, globalconfig.ConfigOrigin{Kind: globalconfig.OriginPolicy, Source: policy.Source}, globalconfig.GetConfigOrigin("table_style"))
+	require.Equal(t, globalconfig.ConfigOrigin{Kind: globalconfig.OriginUser, Source: configFile}, globalconfig.GetConfigOrigin("simple_formatting"))
+	require.Equal(t, globalconfig.OriginLocked, globalconfig.GetConfigOrigin("use_hardened_images").Kind)
+	require.Equal(t, globalconfig.OriginPolicy, globalconfig.GetConfigOrigin("remote_config").Kind)
+	require.Equal(t, globalconfig.ConfigOrigin{Kind: globalconfig.OriginDefault}, globalconfig.GetConfigOrigin("xdebug_ide_location"))
+
+	// Only a value of a locked key that isn't DDEV's default conflicts with the policy
+	conflicts := globalconfig.GetPolicyConflicts()
+	require.Len(t, conflicts, 1)
+	require.Contains(t, conflicts[0], "router_http_port is locked to '80'")
+
+	globalconfig.DdevGlobalConfig.UseHardenedImages = false
+	require.ErrorContains(t, globalconfig.CheckPolicyLocks(globalconfig.DdevGlobalConfig), "use_hardened_images is locked to 'true'")
+	globalconfig.DdevGlobalConfig.UseHardenedImages = true
+
+	// Policy values aren't written to the user's config
+	require.NoError(t, globalconfig.WriteGlobalConfig(globalconfig.DdevGlobalConfig))
+	written, err := os.ReadFile(configFile)
+	require.NoError(t, err)
+	require.Contains(t, string(written), "router_http_port: \"8080\"")
+	require.Contains(t, string(written), "simple_formatting: false")
+	require.NotContains(t, string(written), "\ntable_style:")
+	require.Contains(t, string(written), "use_hardened_images: false")
+	require.NotContains(t, string(written), "addons.example.com")
+
+	// The downloaded policy is cached
+	server.Close()
+	require.NoError(t, globalconfig.ReadGlobalConfig())
+	require.Equal(t, "bright", globalconfig.DdevGlobalConfig.TableStyle)
+	require.NoError(t, globalconfig.DdevPolicy.DownloadError)
+
+	// A policy URL that can't be downloaded, and wasn't before, isn't ignored
+	t.Setenv("DDEV_POLICY", server.URL+"/other.yaml")
+	require.NoError(t, globalconfig.ReadGlobalConfig())
+	require.NotNil(t, globalconfig.DdevPolicy)
+	require.Error(t, globalconfig.DdevPolicy.DownloadError)
+}
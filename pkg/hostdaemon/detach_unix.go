@@ -0,0 +1,14 @@
+//go:build !windows
+
+package hostdaemon
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// detach starts the process in its own session, so it keeps running when
+// the terminal of the ddev command that started it is closed
+func detach(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+}
@@ -0,0 +1,15 @@
+//go:build windows
+
+package hostdaemon
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// detach starts the process without a console in its own process group, so
+// it keeps running when the console of the ddev command that started it is
+// closed
+func detach(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{CreationFlags: 0x00000008 | syscall.CREATE_NEW_PROCESS_GROUP} // DETACHED_PROCESS
+}
@@ -0,0 +1,104 @@
+// Package hostdaemon runs ddev commands like the idle manager and the Xdebug
+// proxy as background processes on the host
+package hostdaemon
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ddev/ddev/pkg/globalconfig"
+)
+
+// Daemon is a ddev command that runs in the background on the host
+type Daemon struct {
+	// Name is used in messages, like "idle manager"
+	Name string
+	// Args are the ddev arguments that run the daemon in the foreground
+	Args []string
+	// PidFile and LogFile are file names in the global ddev directory
+	PidFile string
+	LogFile string
+	// IsRunning returns true if the daemon answers on this machine
+	IsRunning func() bool
+	// StartHint is added to the error when the daemon doesn't start, like
+	// "port 10998 may be in use"
+	StartHint string
+}
+
+// pidFilePath returns the file with the process ID of the running daemon
+func (d *Daemon) pidFilePath() string {
+	return filepath.Join(globalconfig.GetGlobalDdevDir(), d.PidFile)
+}
+
+// LogFilePath returns the file the daemon logs to
+func (d *Daemon) LogFilePath() string {
+	return filepath.Join(globalconfig.GetGlobalDdevDir(), d.LogFile)
+}
+
+// Start starts the daemon as a background process of this ddev binary,
+// unless it's already running
+func (d *Daemon) Start() error {
+	if d.IsRunning() {
+		return nil
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	logFile, err := os.OpenFile(d.LogFilePath(), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer logFile.Close()
+
+	cmd := exec.Command(exe, d.Args...)
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	detach(cmd)
+	if err = cmd.Start(); err != nil {
+		return fmt.Errorf("unable to start the %s: %v", d.Name, err)
+	}
+	if err = os.WriteFile(d.pidFilePath(), []byte(strconv.Itoa(cmd.Process.Pid)), 0644); err != nil {
+		return err
+	}
+	_ = cmd.Process.Release()
+
+	for range 50 {
+		if d.IsRunning() {
+			return nil
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return fmt.Errorf("the %s didn't start, see %s, %s", d.Name, d.LogFilePath(), d.StartHint)
+}
+
+// Stop stops the background daemon started by Start, if any
+func (d *Daemon) Stop() error {
+	content, err := os.ReadFile(d.pidFilePath())
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	_ = os.Remove(d.pidFilePath())
+	pid, err := strconv.Atoi(strings.TrimSpace(string(content)))
+	if err != nil {
+		return nil
+	}
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return nil
+	}
+	// The process may have ended already, or its ID may have been reused,
+	// so only stop it if the daemon is still answering
+	if !d.IsRunning() {
+		return nil
+	}
+	return process.Kill()
+}
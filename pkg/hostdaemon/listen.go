@@ -0,0 +1,127 @@
+package hostdaemon
+
+import (
+	"errors"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ddev/ddev/pkg/dockerutil"
+	"github.com/ddev/ddev/pkg/globalconfig"
+	"github.com/ddev/ddev/pkg/netutil"
+)
+
+// ListenHosts returns the hosts daemons listen on. That's all interfaces if
+// router_bind_all_interfaces is set. Otherwise it's localhost, and the
+// address containers reach host.docker.internal at when it belongs to this
+// machine, like the Docker bridge with Docker CE on Linux. Elsewhere, the
+// Docker provider forwards host.docker.internal to localhost.
+func ListenHosts() []string {
+	if globalconfig.DdevGlobalConfig.RouterBindAllInterfaces {
+		return []string{""}
+	}
+	hosts := []string{"127.0.0.1"}
+	hostIP := dockerutil.GetHostDockerInternal().IPAddress
+	if hostIP == "" || hostIP == "127.0.0.1" {
+		return hosts
+	}
+	if localIPs, err := netutil.GetLocalIPs(); err == nil && slices.Contains(localIPs, hostIP) {
+		hosts = append(hosts, hostIP)
+	}
+	return hosts
+}
+
+// Listen listens on port on each of ListenHosts, returning a listener
+// that accepts the connections of all of them
+func Listen(port int) (net.Listener, error) {
+	m := &multiListener{
+		conns: make(chan net.Conn),
+		done:  make(chan struct{}),
+	}
+	for _, host := range ListenHosts() {
+		l, err := net.Listen("tcp", net.JoinHostPort(host, strconv.Itoa(port)))
+		if err != nil {
+			_ = m.Close()
+			return nil, err
+		}
+		m.listeners = append(m.listeners, l)
+	}
+	for _, l := range m.listeners {
+		go m.accept(l)
+	}
+	return m, nil
+}
+
+// multiListener accepts the connections of several listeners
+type multiListener struct {
+	listeners []net.Listener
+	conns     chan net.Conn
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// accept passes the connections of l on until the listener is closed
+func (m *multiListener) accept(l net.Listener) {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			_ = m.Close()
+			return
+		}
+		select {
+		case m.conns <- conn:
+		case <-m.done:
+			_ = conn.Close()
+			return
+		}
+	}
+}
+
+// Accept waits for the next connection on any of the listeners
+func (m *multiListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-m.conns:
+		return conn, nil
+	case <-m.done:
+		return nil, net.ErrClosed
+	}
+}
+
+// Close closes all the listeners
+func (m *multiListener) Close() error {
+	var errs []error
+	m.closeOnce.Do(func() {
+		close(m.done)
+		for _, l := range m.listeners {
+			if err := l.Close(); err != nil && !errors.Is(err, net.ErrClosed) {
+				errs = append(errs, err)
+			}
+		}
+	})
+	return errors.Join(errs...)
+}
+
+// Addr returns the addresses of all the listeners
+func (m *multiListener) Addr() net.Addr {
+	return multiAddr(m.listeners)
+}
+
+// multiAddr is the addresses of several listeners, like
+// "127.0.0.1:10998, 172.17.0.1:10998"
+type multiAddr []net.Listener
+
+// Network returns the network of the addresses
+func (a multiAddr) Network() string {
+	return "tcp"
+}
+
+// String returns the addresses separated by commas
+func (a multiAddr) String() string {
+	var addresses []string
+	for _, l := range a {
+		addresses = append(addresses, l.Addr().String())
+	}
+	return strings.Join(addresses, ", ")
+}
@@ -0,0 +1,104 @@
+package idlemanager
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ddev/ddev/pkg/globalconfig"
+)
+
+const (
+	activitySuffix = ".activity"
+	stoppedSuffix  = ".stopped"
+)
+
+// stateDir returns the directory with the activity of the projects and the
+// projects stopped by the idle manager
+func stateDir() string {
+	return filepath.Join(globalconfig.GetGlobalDdevDir(), "idle")
+}
+
+// RecordActivity records that the project is being used now, if the idle
+// manager is enabled. Errors are ignored, a missed activity only makes the
+// project stop earlier.
+func RecordActivity(name string) {
+	if !IsEnabled() {
+		return
+	}
+	if err := os.MkdirAll(stateDir(), 0755); err != nil {
+		return
+	}
+	_ = os.WriteFile(filepath.Join(stateDir(), name+activitySuffix), []byte(time.Now().UTC().Format(time.RFC3339)), 0644)
+}
+
+// LastActivity returns the last recorded activity of the project, and false
+// if none was recorded
+func LastActivity(name string) (time.Time, bool) {
+	content, err := os.ReadFile(filepath.Join(stateDir(), name+activitySuffix))
+	if err != nil {
+		return time.Time{}, false
+	}
+	last, err := time.Parse(time.RFC3339, strings.TrimSpace(string(content)))
+	if err != nil {
+		return time.Time{}, false
+	}
+	return last, true
+}
+
+// MarkStopped records that the idle manager stopped the project, so it
+// starts it when it's requested
+func MarkStopped(name string) error {
+	if err := os.MkdirAll(stateDir(), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(stateDir(), name+stoppedSuffix), []byte(time.Now().UTC().Format(time.RFC3339)), 0644)
+}
+
+// ClearStopped forgets that the idle manager stopped the project, when it's
+// started or stopped by other means
+func ClearStopped(name string) {
+	_ = os.Remove(filepath.Join(stateDir(), name+stoppedSuffix))
+}
+
+// StoppedProjects returns the names of the projects stopped by the idle
+// manager, sorted
+func StoppedProjects() []string {
+	files, err := filepath.Glob(filepath.Join(stateDir(), "*"+stoppedSuffix))
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for _, f := range files {
+		names = append(names, strings.TrimSuffix(filepath.Base(f), stoppedSuffix))
+	}
+	return names
+}
+
+// KeepActive records activity of the project now and then until the
+// returned function is called, so a long 'ddev ssh' session or 'ddev exec'
+// command doesn't get its project stopped
+func KeepActive(name string) (done func()) {
+	if !IsEnabled() {
+		return func() {}
+	}
+	RecordActivity(name)
+	stop := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(CheckInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				RecordActivity(name)
+			}
+		}
+	}()
+	return func() {
+		close(stop)
+		RecordActivity(name)
+	}
+}
@@ -0,0 +1,58 @@
+package idlemanager
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ddev/ddev/pkg/hostdaemon"
+)
+
+// daemon runs the idle manager in the background
+var daemon = &hostdaemon.Daemon{
+	Name:      "idle manager",
+	Args:      []string{"utility", "idle-manager", "serve"},
+	PidFile:   ".idle-manager.pid",
+	LogFile:   "idle-manager.log",
+	IsRunning: IsRunning,
+	StartHint: fmt.Sprintf("port %d may be in use", DefaultWakePort),
+}
+
+// Listen listens on the wake port where the router can reach it, but not
+// on the network unless router_bind_all_interfaces is set, since the idle
+// manager can start and stop projects
+func Listen() (net.Listener, error) {
+	return hostdaemon.Listen(DefaultWakePort)
+}
+
+// LogFilePath returns the file the idle manager logs the projects it stops
+// and starts to
+func LogFilePath() string {
+	return daemon.LogFilePath()
+}
+
+// IsRunning returns true if the idle manager answers on this machine
+func IsRunning() bool {
+	client := &http.Client{Timeout: time.Second}
+	resp, err := client.Get("http://" + net.JoinHostPort("127.0.0.1", strconv.Itoa(DefaultWakePort)) + PingPath)
+	if err != nil {
+		return false
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return resp.StatusCode == http.StatusOK
+}
+
+// Start starts the idle manager as a background process of this ddev
+// binary, unless it's already running
+func Start() error {
+	return daemon.Start()
+}
+
+// Stop stops the background idle manager started by Start, if any
+func Stop() error {
+	return daemon.Stop()
+}
@@ -0,0 +1,60 @@
+// Package idlemanager keeps track of the activity of projects, so the idle
+// manager can stop the ones that haven't been used for a while and start
+// them again when they're requested.
+package idlemanager
+
+import (
+	"path/filepath"
+	"time"
+
+	"github.com/ddev/ddev/pkg/globalconfig"
+)
+
+// DefaultWakePort is the port the idle manager answers the requests for the
+// projects it stopped on, and that the router sends them to
+const DefaultWakePort = 10998
+
+// PingPath is the path the idle manager answers on to show it's running
+const PingPath = "/.ddev-idle-manager/ping"
+
+// CheckInterval is how often the idle manager looks for idle projects
+const CheckInterval = time.Minute
+
+// Timeout returns the idle_timeout of the global configuration, or 0 when
+// the idle manager is disabled
+func Timeout() time.Duration {
+	if globalconfig.DdevGlobalConfig.IdleTimeout == "" {
+		return 0
+	}
+	timeout, err := time.ParseDuration(globalconfig.DdevGlobalConfig.IdleTimeout)
+	if err != nil {
+		return 0
+	}
+	return timeout
+}
+
+// IsEnabled returns true if idle_timeout is set
+func IsEnabled() bool {
+	return Timeout() > 0
+}
+
+// KeepRunning returns true if the project matches one of the names or
+// patterns like "client-*" of keep_running in the global configuration
+func KeepRunning(name string) bool {
+	for _, pattern := range globalconfig.DdevGlobalConfig.KeepRunning {
+		if matched, _ := filepath.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// IsIdle returns true if the project should be stopped because its last
+// activity is older than idle_timeout
+func IsIdle(name string, lastActivity time.Time, now time.Time) bool {
+	timeout := Timeout()
+	if timeout <= 0 || KeepRunning(name) {
+		return false
+	}
+	return now.Sub(lastActivity) >= timeout
+}
@@ -0,0 +1,83 @@
+package idlemanager
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ddev/ddev/pkg/globalconfig"
+	"github.com/stretchr/testify/require"
+)
+
+// setIdleConfig sets idle_timeout and keep_running for a test
+func setIdleConfig(t *testing.T, timeout string, keepRunning ...string) {
+	origTimeout, origKeepRunning := globalconfig.DdevGlobalConfig.IdleTimeout, globalconfig.DdevGlobalConfig.KeepRunning
+	t.Cleanup(func() {
+		globalconfig.DdevGlobalConfig.IdleTimeout, globalconfig.DdevGlobalConfig.KeepRunning = origTimeout, origKeepRunning
+	})
+	globalconfig.DdevGlobalConfig.IdleTimeout, globalconfig.DdevGlobalConfig.KeepRunning = timeout, keepRunning
+}
+
+// TestIsIdle checks idle_timeout and keep_running
+func TestIsIdle(t *testing.T) {
+	now := time.Now()
+
+	setIdleConfig(t, "")
+	require.False(t, IsEnabled())
+	require.False(t, IsIdle("site", now.Add(-24*time.Hour), now))
+
+	setIdleConfig(t, "2h", "important", "client-*")
+	require.True(t, IsEnabled())
+	require.Equal(t, 2*time.Hour, Timeout())
+	require.False(t, IsIdle("site", now.Add(-time.Hour), now))
+	require.True(t, IsIdle("site", now.Add(-2*time.Hour), now))
+	require.False(t, IsIdle("important", now.Add(-24*time.Hour), now))
+	require.False(t, IsIdle("client-a", now.Add(-24*time.Hour), now))
+	require.True(t, IsIdle("clients", now.Add(-24*time.Hour), now))
+}
+
+// TestActivity checks the recorded activity and stopped projects
+func TestActivity(t *testing.T) {
+	t.Setenv("DDEV_XDG_CONFIG_HOME", t.TempDir())
+	setIdleConfig(t, "1h")
+
+	_, ok := LastActivity("site")
+	require.False(t, ok)
+	RecordActivity("site")
+	last, ok := LastActivity("site")
+	require.True(t, ok)
+	require.WithinDuration(t, time.Now(), last, 2*time.Second)
+
+	require.Empty(t, StoppedProjects())
+	require.NoError(t, MarkStopped("site"))
+	require.NoError(t, MarkStopped("other"))
+	require.Equal(t, []string{"other", "site"}, StoppedProjects())
+	ClearStopped("site")
+	require.Equal(t, []string{"other"}, StoppedProjects())
+
+	// Nothing is recorded while the idle manager is disabled
+	setIdleConfig(t, "")
+	RecordActivity("new")
+	_, ok = LastActivity("new")
+	require.False(t, ok)
+}
+
+// TestParseRouterRequests checks that the requests are summed by router
+func TestParseRouterRequests(t *testing.T) {
+	metrics := `# HELP traefik_router_requests_total How many HTTP requests are processed on a router, partitioned by service, status code, protocol, and method.
+# TYPE traefik_router_requests_total counter
+traefik_router_requests_total{code="200",method="GET",protocol="http",router="site-web-80-http@file",service="site-web@file"} 3
+traefik_router_requests_total{code="404",method="GET",protocol="http",router="site-web-80-http@file",service="site-web@file"} 2
+traefik_router_requests_total{code="200",method="GET",protocol="http",router="site-extra-web-443-https@file",service="site-extra-web@file"} 1
+traefik_router_requests_bytes_total{code="200",method="GET",protocol="http",router="site-web-80-http@file",service="site-web@file"} 512
+traefik_entrypoint_requests_total{code="200",entrypoint="http-80",method="GET",protocol="http"} 6
+`
+	requests, err := parseRouterRequests(strings.NewReader(metrics))
+	require.NoError(t, err)
+	require.Equal(t, map[string]float64{"site-web-80-http": 5, "site-extra-web-443-https": 1}, requests)
+
+	names := []string{"site", "site-extra"}
+	require.Equal(t, "site", ProjectForRouter("site-web-80-http", names))
+	require.Equal(t, "site-extra", ProjectForRouter("site-extra-web-443-https", names))
+	require.Equal(t, "", ProjectForRouter("other-web-80-http", names))
+}
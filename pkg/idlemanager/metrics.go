@@ -0,0 +1,72 @@
+package idlemanager
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// routerRequestsMetric is the Traefik Prometheus counter of the requests
+// handled by each router
+const routerRequestsMetric = "traefik_router_requests_total"
+
+// RouterRequests returns the number of requests handled by each Traefik
+// router, from the metrics on the router's monitor port
+func RouterRequests(monitorPort string) (map[string]float64, error) {
+	client := &http.Client{Timeout: 5 * time.Second}
+	resp, err := client.Get("http://127.0.0.1:" + monitorPort + "/metrics")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("the router metrics returned %s, the router may need a restart with 'ddev poweroff' after enabling idle_timeout", resp.Status)
+	}
+	return parseRouterRequests(resp.Body)
+}
+
+// parseRouterRequests sums the request counters of the Prometheus text
+// format by router, like "myproject-web-80-http" for
+// traefik_router_requests_total{code="200",router="myproject-web-80-http@file"} 3
+func parseRouterRequests(r io.Reader) (map[string]float64, error) {
+	requests := map[string]float64{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, routerRequestsMetric+"{") {
+			continue
+		}
+		labels, value, found := strings.Cut(strings.TrimPrefix(line, routerRequestsMetric+"{"), "} ")
+		if !found {
+			continue
+		}
+		count, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			continue
+		}
+		_, router, found := strings.Cut(labels, `router="`)
+		if !found {
+			continue
+		}
+		router, _, _ = strings.Cut(router, `"`)
+		router, _, _ = strings.Cut(router, "@")
+		requests[router] += count
+	}
+	return requests, scanner.Err()
+}
+
+// ProjectForRouter returns the project a router like "myproject-web-80-http"
+// belongs to, the longest of the names it starts with, or "" if none
+func ProjectForRouter(router string, names []string) string {
+	project := ""
+	for _, name := range names {
+		if strings.HasPrefix(router, name+"-") && len(name) > len(project) {
+			project = name
+		}
+	}
+	return project
+}
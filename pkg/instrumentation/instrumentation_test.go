@@ -0,0 +1,115 @@
+package instrumentation
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestSpans checks span nesting, phases and outcomes
+func TestSpans(t *testing.T) {
+	Configure(nil)
+	require.False(t, Enabled())
+	require.Nil(t, StartSpan("ddev start", nil))
+
+	jsonl := filepath.Join(t.TempDir(), "spans.jsonl")
+	Configure(map[string]string{"service.name": "ddev"}, &JSONLSink{Path: jsonl})
+	require.True(t, Enabled())
+
+	cmd := StartSpan("ddev start", map[string]string{"ddev.command": "ddev start"})
+	start := StartSpan("app.start", nil)
+	SetRootAttribute("ddev.project.type", "drupal11")
+	start.Phase("pre-start-hooks")
+	start.Phase("compose-up")
+	start.End(errors.New("compose up failed"))
+	require.NoError(t, Shutdown(nil))
+
+	f, err := os.Open(jsonl)
+	require.NoError(t, err)
+	defer f.Close()
+	spans := map[string]jsonlRecord{}
+	var names []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var r jsonlRecord
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &r))
+		require.Equal(t, "ddev", r.Resource["service.name"])
+		spans[r.Name] = r
+		names = append(names, r.Name)
+	}
+	require.Equal(t, []string{"app.start.pre-start-hooks", "app.start.compose-up", "app.start", "ddev start"}, names)
+
+	for _, name := range names {
+		require.Equal(t, cmd.TraceID, spans[name].TraceID)
+	}
+	require.Empty(t, spans["ddev start"].ParentSpanID)
+	require.Equal(t, "drupal11", spans["ddev start"].Attributes["ddev.project.type"])
+	require.True(t, spans["ddev start"].Success)
+	require.Equal(t, cmd.SpanID, spans["app.start"].ParentSpanID)
+	require.False(t, spans["app.start"].Success)
+	require.Equal(t, "compose up failed", spans["app.start"].Error)
+	require.Equal(t, start.SpanID, spans["app.start.pre-start-hooks"].ParentSpanID)
+	require.True(t, spans["app.start.pre-start-hooks"].Success)
+	require.Equal(t, start.SpanID, spans["app.start.compose-up"].ParentSpanID)
+	require.False(t, spans["app.start.compose-up"].Success)
+}
+
+// TestOTLPSink exports spans to a stand-in for an OTLP collector
+func TestOTLPSink(t *testing.T) {
+	var received otlpTraces
+	var path, auth string
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		auth = r.Header.Get("Authorization")
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer collector.Close()
+
+	Configure(map[string]string{"service.name": "ddev", "os.type": "linux"}, &OTLPSink{
+		Endpoint: collector.URL,
+		Headers:  map[string]string{"Authorization": "Bearer abc"},
+	})
+	cmd := StartSpan("ddev stop", nil)
+	StartSpan("app.stop", map[string]string{"ddev.project.type": "php"})
+	require.NoError(t, Shutdown(errors.New("stop failed")))
+
+	require.Equal(t, "/v1/traces", path)
+	require.Equal(t, "Bearer abc", auth)
+	require.Len(t, received.ResourceSpans, 1)
+	rs := received.ResourceSpans[0]
+	require.Equal(t, []otlpKeyValue{
+		{Key: "os.type", Value: otlpAnyValue{StringValue: "linux"}},
+		{Key: "service.name", Value: otlpAnyValue{StringValue: "ddev"}},
+	}, rs.Resource.Attributes)
+	require.Len(t, rs.ScopeSpans, 1)
+	spans := rs.ScopeSpans[0].Spans
+	require.Len(t, spans, 2)
+	require.Equal(t, "app.stop", spans[0].Name)
+	require.Equal(t, cmd.SpanID, spans[0].ParentSpanID)
+	require.Equal(t, []otlpKeyValue{{Key: "ddev.project.type", Value: otlpAnyValue{StringValue: "php"}}}, spans[0].Attributes)
+	require.Equal(t, "ddev stop", spans[1].Name)
+	require.Equal(t, otlpStatus{Code: otlpStatusError, Message: "stop failed"}, spans[1].Status)
+	require.Len(t, spans[1].TraceID, 32)
+	require.Len(t, spans[1].SpanID, 16)
+	require.NotEmpty(t, spans[1].StartTimeUnixNano)
+
+	// A collector that rejects spans is reported
+	collector.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "quota exceeded", http.StatusTooManyRequests)
+	})
+	StartSpan("ddev list", nil)
+	err := Shutdown(nil)
+	require.ErrorContains(t, err, "quota exceeded")
+	require.ErrorContains(t, err, collector.URL+"/v1/traces")
+}
@@ -0,0 +1,63 @@
+package instrumentation
+
+import (
+	"errors"
+	"runtime"
+
+	"github.com/ddev/ddev/pkg/environment"
+	"github.com/ddev/ddev/pkg/globalconfig"
+	"github.com/ddev/ddev/pkg/util"
+	"github.com/ddev/ddev/pkg/version"
+	"github.com/ddev/ddev/pkg/versionconstants"
+	"github.com/sirupsen/logrus"
+)
+
+// errFatal is the outcome of commands that exit through util.Failed()
+var errFatal = errors.New("command exited with a fatal error")
+
+// Init configures the sinks enabled in the global config. Commands that
+// exit through util.Failed() are exported as failed by an exit handler.
+func Init() {
+	var sinks []Sink
+	if f := globalconfig.GetInstrumentationFilePath(); f != "" {
+		sinks = append(sinks, &JSONLSink{Path: f})
+	}
+	if endpoint := globalconfig.DdevGlobalConfig.InstrumentationOTLPEndpoint; endpoint != "" {
+		sinks = append(sinks, &OTLPSink{Endpoint: endpoint, Headers: globalconfig.DdevGlobalConfig.InstrumentationOTLPHeaders})
+	}
+	if len(sinks) == 0 {
+		return
+	}
+	Configure(map[string]string{
+		"service.name":     "ddev",
+		"service.version":  versionconstants.DdevVersion,
+		"os.type":          runtime.GOOS,
+		"host.arch":        runtime.GOARCH,
+		"ddev.environment": environment.GetDDEVEnvironment(),
+	}, sinks...)
+	logrus.RegisterExitHandler(func() {
+		Exit(errFatal)
+	})
+}
+
+// Exit ends the command span, which failed if err isn't nil, and exports
+// all spans. Export problems are only shown with DDEV_DEBUG=true so a
+// missing collector doesn't get in the way.
+func Exit(err error) {
+	if !Enabled() {
+		return
+	}
+	provider, platformErr := version.GetDockerPlatform()
+	if platformErr != nil {
+		provider = "unknown"
+	}
+	active.mu.Lock()
+	if active.resource != nil {
+		active.resource["docker.provider"] = provider
+	}
+	active.mu.Unlock()
+
+	if exportErr := Shutdown(err); exportErr != nil {
+		util.Debug("Unable to export instrumentation: %v", exportErr)
+	}
+}
@@ -0,0 +1,207 @@
+package instrumentation
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Sink receives the spans of a command when it exits
+type Sink interface {
+	// Name describes the sink in error messages
+	Name() string
+	// Export writes the spans, with the resource attributes describing
+	// the machine they were recorded on
+	Export(resource map[string]string, spans []*Span) error
+}
+
+// JSONLSink appends one JSON object per span to a file
+type JSONLSink struct {
+	Path string
+}
+
+// jsonlRecord is a line written by JSONLSink
+type jsonlRecord struct {
+	*Span
+	Resource map[string]string `json:"resource,omitempty"`
+}
+
+// Name implements Sink
+func (s *JSONLSink) Name() string { return "file " + s.Path }
+
+// Export implements Sink
+func (s *JSONLSink) Export(resource map[string]string, spans []*Span) error {
+	if err := os.MkdirAll(filepath.Dir(s.Path), 0755); err != nil {
+		return err
+	}
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	for _, span := range spans {
+		if err := enc.Encode(jsonlRecord{Span: span, Resource: resource}); err != nil {
+			return err
+		}
+	}
+	f, err := os.OpenFile(s.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err = f.Write(b.Bytes()); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// OTLPSink sends spans to an OpenTelemetry collector using OTLP/HTTP
+// with JSON encoding
+type OTLPSink struct {
+	// Endpoint is the base URL of the collector, like http://localhost:4318.
+	// Spans are sent to its /v1/traces path.
+	Endpoint string
+	Headers  map[string]string
+	Client   *http.Client
+}
+
+// otlpTimeout keeps a missing collector from slowing down every command
+const otlpTimeout = 3 * time.Second
+
+// Name implements Sink
+func (s *OTLPSink) Name() string { return "OTLP endpoint " + s.tracesURL() }
+
+// tracesURL returns the URL spans are posted to
+func (s *OTLPSink) tracesURL() string {
+	endpoint := strings.TrimSuffix(s.Endpoint, "/")
+	if strings.HasSuffix(endpoint, "/v1/traces") {
+		return endpoint
+	}
+	return endpoint + "/v1/traces"
+}
+
+// Export implements Sink
+func (s *OTLPSink) Export(resource map[string]string, spans []*Span) error {
+	body, err := json.Marshal(otlpRequest(resource, spans))
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, s.tracesURL(), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range s.Headers {
+		req.Header.Set(k, v)
+	}
+	client := s.Client
+	if client == nil {
+		client = &http.Client{Timeout: otlpTimeout}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s returned %s: %s", s.tracesURL(), resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// OTLP JSON structures, see
+// https://opentelemetry.io/docs/specs/otlp/#json-protobuf-encoding
+type (
+	otlpTraces struct {
+		ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+	}
+	otlpResourceSpans struct {
+		Resource   otlpResource     `json:"resource"`
+		ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+	}
+	otlpResource struct {
+		Attributes []otlpKeyValue `json:"attributes"`
+	}
+	otlpScopeSpans struct {
+		Scope otlpScope  `json:"scope"`
+		Spans []otlpSpan `json:"spans"`
+	}
+	otlpScope struct {
+		Name string `json:"name"`
+	}
+	otlpSpan struct {
+		TraceID           string         `json:"traceId"`
+		SpanID            string         `json:"spanId"`
+		ParentSpanID      string         `json:"parentSpanId,omitempty"`
+		Name              string         `json:"name"`
+		Kind              int            `json:"kind"`
+		StartTimeUnixNano string         `json:"startTimeUnixNano"`
+		EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+		Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+		Status            otlpStatus     `json:"status"`
+	}
+	otlpKeyValue struct {
+		Key   string       `json:"key"`
+		Value otlpAnyValue `json:"value"`
+	}
+	otlpAnyValue struct {
+		StringValue string `json:"stringValue"`
+	}
+	otlpStatus struct {
+		Code    int    `json:"code"`
+		Message string `json:"message,omitempty"`
+	}
+)
+
+// OTLP span kind and status codes
+const (
+	otlpSpanKindInternal = 1
+	otlpStatusOK         = 1
+	otlpStatusError      = 2
+)
+
+// otlpRequest converts spans to an OTLP export request
+func otlpRequest(resource map[string]string, spans []*Span) otlpTraces {
+	scope := otlpScopeSpans{Scope: otlpScope{Name: "ddev"}}
+	for _, s := range spans {
+		status := otlpStatus{Code: otlpStatusOK}
+		if !s.Success {
+			status = otlpStatus{Code: otlpStatusError, Message: s.Error}
+		}
+		scope.Spans = append(scope.Spans, otlpSpan{
+			TraceID:           s.TraceID,
+			SpanID:            s.SpanID,
+			ParentSpanID:      s.ParentSpanID,
+			Name:              s.Name,
+			Kind:              otlpSpanKindInternal,
+			StartTimeUnixNano: strconv.FormatInt(s.StartTime.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(s.EndTime.UnixNano(), 10),
+			Attributes:        otlpAttributes(s.Attributes),
+			Status:            status,
+		})
+	}
+	return otlpTraces{ResourceSpans: []otlpResourceSpans{{
+		Resource:   otlpResource{Attributes: otlpAttributes(resource)},
+		ScopeSpans: []otlpScopeSpans{scope},
+	}}}
+}
+
+// otlpAttributes converts attributes to OTLP key-values, sorted by key
+func otlpAttributes(attributes map[string]string) []otlpKeyValue {
+	keys := make([]string, 0, len(attributes))
+	for k := range attributes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	kvs := make([]otlpKeyValue, 0, len(keys))
+	for _, k := range keys {
+		kvs = append(kvs, otlpKeyValue{Key: k, Value: otlpAnyValue{StringValue: attributes[k]}})
+	}
+	return kvs
+}
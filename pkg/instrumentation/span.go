@@ -0,0 +1,200 @@
+// Package instrumentation records spans for DDEV commands and the phases of
+// project operations, and exports them to local sinks like a JSONL file or an
+// OpenTelemetry collector. Unlike pkg/amplitude, nothing is sent to a third
+// party, the sinks are configured by the user in the global config.
+package instrumentation
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Span is a timed operation, like a command or a phase of starting a project
+type Span struct {
+	TraceID      string            `json:"trace_id"`
+	SpanID       string            `json:"span_id"`
+	ParentSpanID string            `json:"parent_span_id,omitempty"`
+	Name         string            `json:"name"`
+	StartTime    time.Time         `json:"start"`
+	EndTime      time.Time         `json:"end"`
+	DurationMS   int64             `json:"duration_ms"`
+	Success      bool              `json:"success"`
+	Error        string            `json:"error,omitempty"`
+	Attributes   map[string]string `json:"attributes,omitempty"`
+
+	// phase is the open child span started by Phase()
+	phase *Span
+	ended bool
+}
+
+// tracer holds the spans of the running command
+type tracer struct {
+	mu       sync.Mutex
+	sinks    []Sink
+	resource map[string]string
+	traceID  string
+	// open is the stack of spans that haven't ended, innermost last
+	open     []*Span
+	finished []*Span
+}
+
+// active is the tracer of the running command
+var active = &tracer{}
+
+// Configure replaces the sinks and the resource attributes that describe
+// this machine, and drops any recorded spans
+func Configure(resource map[string]string, sinks ...Sink) {
+	active.mu.Lock()
+	defer active.mu.Unlock()
+	active.sinks = sinks
+	active.resource = resource
+	active.traceID = ""
+	active.open = nil
+	active.finished = nil
+}
+
+// Enabled reports whether any sink is configured
+func Enabled() bool {
+	active.mu.Lock()
+	defer active.mu.Unlock()
+	return len(active.sinks) > 0
+}
+
+// StartSpan starts a span as a child of the innermost open span. It returns
+// nil if no sink is configured, and all Span methods accept a nil span.
+func StartSpan(name string, attributes map[string]string) *Span {
+	active.mu.Lock()
+	defer active.mu.Unlock()
+	if len(active.sinks) == 0 {
+		return nil
+	}
+	if active.traceID == "" {
+		active.traceID = randomHex(16)
+	}
+	s := &Span{
+		TraceID:    active.traceID,
+		SpanID:     randomHex(8),
+		Name:       name,
+		StartTime:  time.Now(),
+		Attributes: map[string]string{},
+	}
+	if len(active.open) > 0 {
+		s.ParentSpanID = active.open[len(active.open)-1].SpanID
+	}
+	for k, v := range attributes {
+		s.Attributes[k] = v
+	}
+	active.open = append(active.open, s)
+	return s
+}
+
+// SetRootAttribute sets an attribute of the outermost open span, which is
+// the span of the running command
+func SetRootAttribute(key string, value string) {
+	active.mu.Lock()
+	defer active.mu.Unlock()
+	if len(active.open) > 0 {
+		active.open[0].Attributes[key] = value
+	}
+}
+
+// SetAttribute sets an attribute of the span
+func (s *Span) SetAttribute(key string, value string) {
+	if s == nil {
+		return
+	}
+	active.mu.Lock()
+	defer active.mu.Unlock()
+	s.Attributes[key] = value
+}
+
+// Phase ends the previous phase of the span, if any, and starts a child
+// span for the next one. The last phase ends with the span.
+func (s *Span) Phase(name string) {
+	if s == nil {
+		return
+	}
+	if s.phase != nil {
+		s.phase.End(nil)
+	}
+	s.phase = StartSpan(s.Name+"."+name, nil)
+}
+
+// End ends the span, which failed if err isn't nil. Spans that were started
+// inside it and are still open, like its current phase, end with it.
+func (s *Span) End(err error) {
+	if s == nil {
+		return
+	}
+	active.mu.Lock()
+	defer active.mu.Unlock()
+	active.end(s, err)
+}
+
+// end ends s and its open descendants, t.mu must be held
+func (t *tracer) end(s *Span, err error) {
+	if s.ended {
+		return
+	}
+	for i := len(t.open) - 1; i >= 0; i-- {
+		if t.open[i] != s {
+			continue
+		}
+		for _, child := range t.open[i+1:] {
+			finish(child, err)
+			t.finished = append(t.finished, child)
+		}
+		t.open = t.open[:i]
+		break
+	}
+	finish(s, err)
+	t.finished = append(t.finished, s)
+}
+
+// finish records the end time and outcome of a span
+func finish(s *Span, err error) {
+	s.ended = true
+	s.EndTime = time.Now()
+	s.DurationMS = s.EndTime.Sub(s.StartTime).Milliseconds()
+	s.Success = err == nil
+	if err != nil {
+		s.Error = err.Error()
+	}
+}
+
+// Shutdown ends all open spans, which failed if err isn't nil, and exports
+// the finished spans to every sink. Export errors are returned joined, but
+// don't stop the other sinks.
+func Shutdown(err error) error {
+	active.mu.Lock()
+	if len(active.open) > 0 {
+		active.end(active.open[0], err)
+	}
+	spans := active.finished
+	active.finished = nil
+	sinks := active.sinks
+	resource := active.resource
+	active.mu.Unlock()
+
+	if len(spans) == 0 {
+		return nil
+	}
+	var errs []error
+	for _, sink := range sinks {
+		if exportErr := sink.Export(resource, spans); exportErr != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", sink.Name(), exportErr))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// randomHex returns n random bytes as hex, as used for trace and span IDs
+func randomHex(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -25,11 +25,17 @@ const (
 
 // Webserver types
 const (
-	WebserverNginxFPM  = "nginx-fpm"
-	WebserverApacheFPM = "apache-fpm"
-	WebserverGeneric   = "generic"
+	WebserverNginxFPM   = "nginx-fpm"
+	WebserverApacheFPM  = "apache-fpm"
+	WebserverCaddyFPM   = "caddy-fpm"
+	WebserverFrankenPHP = "frankenphp"
+	WebserverGeneric    = "generic"
 )
 
+// FrankenPHPPHPVersion is the PHP version embedded in FrankenPHP, it runs
+// regardless of php_version
+const FrankenPHPPHPVersion = PHP85
+
 // ValidOmitContainers is the list of things that can be omitted
 var ValidOmitContainers = map[string]bool{
 	DBContainer:           true,
@@ -71,9 +77,11 @@ var GoroutineLimit = 10
 // ValidWebserverTypes should be updated whenever supported webserver types are added or
 // removed, and should be used to ensure user-supplied values are valid.
 var ValidWebserverTypes = map[string]bool{
-	WebserverNginxFPM:  true,
-	WebserverApacheFPM: true,
-	WebserverGeneric:   true,
+	WebserverNginxFPM:   true,
+	WebserverApacheFPM:  true,
+	WebserverCaddyFPM:   true,
+	WebserverFrankenPHP: true,
+	WebserverGeneric:    true,
 }
 
 const AppTypeDrupalLatestStable = AppTypeDrupal11
@@ -0,0 +1,102 @@
+package secrets
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	ddevexec "github.com/ddev/ddev/pkg/exec"
+	"github.com/ddev/ddev/pkg/nodeps"
+)
+
+// keychainService is the service the key of the store is saved under in
+// the OS keychain
+const keychainService = "ddev-secrets"
+
+// keyStore keeps the key of the local store of secrets
+type keyStore interface {
+	// GetKey returns the key of the store in dir
+	GetKey(dir string) ([]byte, error)
+	// SetKey saves the key of the store in dir
+	SetKey(dir string, key []byte) error
+}
+
+// keychain is where the key of the store is kept, tests replace it
+var keychain keyStore = osKeychain{}
+
+// osKeychain keeps the key in the keychain of macOS, in the Secret Service
+// of the Linux desktop with secret-tool, or protected with DPAPI for the
+// user on Windows. The key is passed to the tools on stdin, so it doesn't
+// show in the process list.
+type osKeychain struct{}
+
+// GetKey returns the key of the store in dir from the OS keychain
+func (osKeychain) GetKey(dir string) ([]byte, error) {
+	var out string
+	var err error
+	switch {
+	case nodeps.IsWindows():
+		var protected []byte
+		protected, err = os.ReadFile(filepath.Join(dir, windowsKeyFileName))
+		if err == nil {
+			out, err = runKeychainTool(string(protected), "powershell", "-NoProfile", "-NonInteractive", "-Command",
+				"$s = [Console]::In.ReadLine() | ConvertTo-SecureString; [Net.NetworkCredential]::new('', $s).Password")
+		}
+	case nodeps.IsMacOS():
+		out, err = runKeychainTool("", "security", "find-generic-password", "-s", keychainService, "-a", dir, "-w")
+	default:
+		out, err = runKeychainTool("", "secret-tool", "lookup", "service", keychainService, "account", dir)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(out) == "" {
+		return nil, errors.New("the OS keychain has no key for the secrets store")
+	}
+	return hex.DecodeString(strings.TrimSpace(out))
+}
+
+// SetKey saves the key of the store in dir in the OS keychain
+func (osKeychain) SetKey(dir string, key []byte) error {
+	encoded := hex.EncodeToString(key)
+	switch {
+	case nodeps.IsWindows():
+		protected, err := runKeychainTool(encoded, "powershell", "-NoProfile", "-NonInteractive", "-Command",
+			"ConvertTo-SecureString -String ([Console]::In.ReadLine()) -AsPlainText -Force | ConvertFrom-SecureString")
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(filepath.Join(dir, windowsKeyFileName), []byte(strings.TrimSpace(protected)), 0600)
+	case nodeps.IsMacOS():
+		// security -i reads the command from stdin
+		_, err := runKeychainTool(fmt.Sprintf("add-generic-password -U -s %s -a %q -w %s", keychainService, dir, encoded), "security", "-i")
+		return err
+	default:
+		_, err := runKeychainTool(encoded, "secret-tool", "store", "--label=DDEV secrets", "service", keychainService, "account", dir)
+		return err
+	}
+}
+
+// windowsKeyFileName is the file of the key protected with DPAPI on Windows
+const windowsKeyFileName = "secrets.key"
+
+// runKeychainTool runs a keychain tool with stdin as its input, returning
+// its output
+func runKeychainTool(stdin string, command string, args ...string) (string, error) {
+	cmd := ddevexec.HostCommand(command, args...)
+	cmd.Stdin = strings.NewReader(stdin + "\n")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%s failed: %v: %s", command, err, msg)
+		}
+		return "", fmt.Errorf("%s failed: %v", command, err)
+	}
+	return string(out), nil
+}
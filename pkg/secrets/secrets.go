@@ -0,0 +1,92 @@
+package secrets
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	ddevexec "github.com/ddev/ddev/pkg/exec"
+	"github.com/ddev/ddev/pkg/globalconfig"
+	"github.com/ddev/ddev/pkg/util"
+)
+
+// Prefix marks an environment variable value that is the name of a secret,
+// like API_TOKEN=secret:api-token
+const Prefix = "secret:"
+
+// validName matches the names of secrets, which may be paths in the password
+// manager used by secrets_command
+var validName = regexp.MustCompile(`^[A-Za-z0-9_.@/-]+$`)
+
+// ValidateName returns an error if name can't be the name of a secret
+func ValidateName(name string) error {
+	if !validName.MatchString(name) {
+		return fmt.Errorf("invalid secret name '%s', it may only contain letters, digits and the characters _.@/-", name)
+	}
+	return nil
+}
+
+// Reference returns the name of the secret referenced by an environment
+// variable value, and false if the value isn't a reference
+func Reference(value string) (string, bool) {
+	name, ok := strings.CutPrefix(value, Prefix)
+	if !ok || name == "" {
+		return "", false
+	}
+	return name, true
+}
+
+// Resolve returns the value of a secret, from the local store or else from
+// the output of secrets_command
+func Resolve(name string) (string, error) {
+	if err := ValidateName(name); err != nil {
+		return "", err
+	}
+	value, found, err := Get(name)
+	if err != nil {
+		return "", err
+	}
+	if found {
+		return value, nil
+	}
+	command := globalconfig.DdevGlobalConfig.SecretsCommand
+	if command == "" {
+		return "", fmt.Errorf("secret '%s' isn't in the local store, add it with 'ddev secret set %s' or configure secrets_command", name, name)
+	}
+	args, err := util.SplitShellWords(command)
+	if err != nil {
+		return "", fmt.Errorf("invalid secrets_command '%s': %v", command, err)
+	}
+	if len(args) == 0 {
+		return "", fmt.Errorf("secret '%s' isn't in the local store and secrets_command is empty", name)
+	}
+	for i := range args {
+		args[i] = strings.ReplaceAll(args[i], "{name}", name)
+	}
+	out, err := ddevexec.HostCommand(args[0], args[1:]...).Output()
+	if err != nil {
+		return "", fmt.Errorf("secrets_command failed for secret '%s': %v", name, err)
+	}
+	// Password managers end their output with a newline
+	return strings.TrimRight(string(out), "\r\n"), nil
+}
+
+// ResolveEnv returns env, a list of KEY=VALUE entries, with the values that
+// reference a secret replaced by the value of the secret
+func ResolveEnv(env []string) ([]string, error) {
+	resolved := make([]string, 0, len(env))
+	for _, e := range env {
+		key, value, found := strings.Cut(e, "=")
+		name, isSecret := Reference(value)
+		if !found || !isSecret {
+			resolved = append(resolved, e)
+			continue
+		}
+		secret, err := Resolve(name)
+		if err != nil {
+			return nil, fmt.Errorf("unable to resolve %s: %w", key, err)
+		}
+		resolved = append(resolved, key+"="+secret)
+	}
+	return resolved, nil
+}
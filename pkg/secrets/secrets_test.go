@@ -0,0 +1,156 @@
+package secrets
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/ddev/ddev/pkg/globalconfig"
+	"github.com/stretchr/testify/require"
+)
+
+// setSecretsCommand sets secrets_command for a test
+func setSecretsCommand(t *testing.T, command string) {
+	orig := globalconfig.DdevGlobalConfig.SecretsCommand
+	t.Cleanup(func() {
+		globalconfig.DdevGlobalConfig.SecretsCommand = orig
+	})
+	globalconfig.DdevGlobalConfig.SecretsCommand = command
+}
+
+// fakeKeychain keeps keys in memory instead of the OS keychain
+type fakeKeychain map[string][]byte
+
+func (k fakeKeychain) GetKey(dir string) ([]byte, error) {
+	key, ok := k[dir]
+	if !ok {
+		return nil, errors.New("no key")
+	}
+	return key, nil
+}
+
+func (k fakeKeychain) SetKey(dir string, key []byte) error {
+	k[dir] = key
+	return nil
+}
+
+// useFakeKeychain replaces the OS keychain for a test
+func useFakeKeychain(t *testing.T) fakeKeychain {
+	orig := keychain
+	t.Cleanup(func() {
+		keychain = orig
+	})
+	fake := fakeKeychain{}
+	keychain = fake
+	return fake
+}
+
+// TestStore checks the local encrypted store
+func TestStore(t *testing.T) {
+	t.Setenv("DDEV_XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv(passphraseEnv, "")
+	fake := useFakeKeychain(t)
+
+	names, err := List()
+	require.NoError(t, err)
+	require.Empty(t, names)
+
+	require.NoError(t, Set("api-token", "s3cr3t-value"))
+	require.NoError(t, Set("db/password", "other"))
+	require.Error(t, Set("bad name", "x"))
+
+	value, found, err := Get("api-token")
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, "s3cr3t-value", value)
+
+	names, err = List()
+	require.NoError(t, err)
+	require.Equal(t, []string{"api-token", "db/password"}, names)
+
+	// The values aren't readable in the store, the key is in the keychain
+	content, err := os.ReadFile(filepath.Join(storeDir(), storeFileName))
+	require.NoError(t, err)
+	require.NotContains(t, string(content), "s3cr3t-value")
+	require.Len(t, fake[storeDir()], keySize)
+
+	// Only the user can read the store
+	if runtime.GOOS != "windows" {
+		info, err := os.Stat(filepath.Join(storeDir(), storeFileName))
+		require.NoError(t, err)
+		require.Equal(t, os.FileMode(0600), info.Mode().Perm())
+		info, err = os.Stat(storeDir())
+		require.NoError(t, err)
+		require.Equal(t, os.FileMode(0700), info.Mode().Perm())
+	}
+
+	require.NoError(t, Delete("api-token"))
+	require.Error(t, Delete("api-token"))
+	_, found, err = Get("api-token")
+	require.NoError(t, err)
+	require.False(t, found)
+
+	// Without its key the store can't be read
+	delete(fake, storeDir())
+	_, _, err = Get("db/password")
+	require.ErrorContains(t, err, "unable to get the key of the secrets store")
+}
+
+// TestStorePassphrase checks the store encrypted with a passphrase
+func TestStorePassphrase(t *testing.T) {
+	t.Setenv("DDEV_XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv(passphraseEnv, "correct horse battery staple")
+	fake := useFakeKeychain(t)
+
+	require.NoError(t, Set("api-token", "s3cr3t-value"))
+	require.Empty(t, fake)
+	value, found, err := Get("api-token")
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, "s3cr3t-value", value)
+
+	t.Setenv(passphraseEnv, "wrong")
+	_, _, err = Get("api-token")
+	require.ErrorContains(t, err, "unable to decrypt")
+
+	t.Setenv(passphraseEnv, "")
+	_, _, err = Get("api-token")
+	require.ErrorContains(t, err, passphraseEnv)
+}
+
+// TestResolveEnv checks resolving secret references from the store and
+// secrets_command
+func TestResolveEnv(t *testing.T) {
+	t.Setenv("DDEV_XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv(passphraseEnv, "")
+	useFakeKeychain(t)
+	require.NoError(t, Set("api-token", "from-store"))
+
+	setSecretsCommand(t, "")
+	env, err := ResolveEnv([]string{"A=plain", "B=secret:api-token", "C=secret:", "D"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"A=plain", "B=from-store", "C=secret:", "D"}, env)
+
+	_, err = ResolveEnv([]string{"E=secret:missing"})
+	require.ErrorContains(t, err, "unable to resolve E")
+
+	if _, err = os.Stat("/bin/echo"); err != nil {
+		t.Skip("echo is needed to test secrets_command")
+	}
+	setSecretsCommand(t, "echo command-{name}")
+	env, err = ResolveEnv([]string{"B=secret:api-token", "E=secret:missing"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"B=from-store", "E=command-missing"}, env)
+
+	// Quoted arguments of secrets_command keep their spaces
+	setSecretsCommand(t, `echo "item {name}"`)
+	env, err = ResolveEnv([]string{"E=secret:missing"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"E=item missing"}, env)
+
+	setSecretsCommand(t, `echo "unclosed`)
+	_, err = ResolveEnv([]string{"E=secret:missing"})
+	require.ErrorContains(t, err, "invalid secrets_command")
+}
@@ -0,0 +1,241 @@
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/ddev/ddev/pkg/globalconfig"
+)
+
+const (
+	// storeFileName is the file of the encrypted local store
+	storeFileName = "secrets.enc"
+	// passphraseEnv is the environment variable with the passphrase the
+	// store is encrypted with instead of a key in the OS keychain
+	passphraseEnv = "DDEV_SECRETS_PASSPHRASE"
+	keySize       = 32
+	saltSize      = 16
+	// pbkdf2Iterations is the OWASP recommendation for PBKDF2-HMAC-SHA256
+	pbkdf2Iterations = 600000
+)
+
+// Sources of the key of the store
+const (
+	keySourceKeychain   = "keychain"
+	keySourcePassphrase = "passphrase"
+)
+
+// storeFile is the content of the store file
+type storeFile struct {
+	// KeySource is where the key comes from
+	KeySource string `json:"key_source"`
+	// Salt is used to derive the key from the passphrase
+	Salt []byte `json:"salt,omitempty"`
+	// Data is the JSON object of the secrets by name, sealed with AES-GCM
+	Data []byte `json:"data"`
+}
+
+// storeDir returns the directory of the local store of secrets
+func storeDir() string {
+	return filepath.Join(globalconfig.GetGlobalDdevDir(), "secrets")
+}
+
+// Get returns the value of a secret in the local store, and false if it
+// isn't there
+func Get(name string) (string, bool, error) {
+	store, err := readStore()
+	if err != nil {
+		return "", false, err
+	}
+	value, found := store[name]
+	return value, found, nil
+}
+
+// Set adds or replaces a secret in the local store
+func Set(name string, value string) error {
+	if err := ValidateName(name); err != nil {
+		return err
+	}
+	store, err := readStore()
+	if err != nil {
+		return err
+	}
+	store[name] = value
+	return writeStore(store)
+}
+
+// Delete removes a secret from the local store, it's an error if it isn't
+// there
+func Delete(name string) error {
+	store, err := readStore()
+	if err != nil {
+		return err
+	}
+	if _, found := store[name]; !found {
+		return fmt.Errorf("secret '%s' isn't in the local store", name)
+	}
+	delete(store, name)
+	return writeStore(store)
+}
+
+// List returns the names of the secrets in the local store, sorted
+func List() ([]string, error) {
+	store, err := readStore()
+	if err != nil {
+		return nil, err
+	}
+	return slices.Sorted(maps.Keys(store)), nil
+}
+
+// readStore decrypts the local store, which is empty if it doesn't exist
+func readStore() (map[string]string, error) {
+	store := map[string]string{}
+	storePath := filepath.Join(storeDir(), storeFileName)
+	content, err := os.ReadFile(storePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var f storeFile
+	if err = json.Unmarshal(content, &f); err != nil {
+		return nil, fmt.Errorf("unable to read the secrets store %s: %v", storePath, err)
+	}
+	var key []byte
+	switch f.KeySource {
+	case keySourcePassphrase:
+		passphrase := os.Getenv(passphraseEnv)
+		if passphrase == "" {
+			return nil, fmt.Errorf("the secrets store %s is encrypted with a passphrase, set it in %s", storePath, passphraseEnv)
+		}
+		key, err = passphraseKey(passphrase, f.Salt)
+	case keySourceKeychain:
+		key, err = keychain.GetKey(storeDir())
+	default:
+		err = fmt.Errorf("unknown key source '%s'", f.KeySource)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to get the key of the secrets store %s: %v", storePath, err)
+	}
+	plain, err := decrypt(key, f.Data)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decrypt the secrets store %s, is the key or passphrase right? %v", storePath, err)
+	}
+	if err = json.Unmarshal(plain, &store); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+// writeStore encrypts the local store, with a key derived from the
+// passphrase if there is one, or else the key in the OS keychain, which is
+// created the first time
+func writeStore(store map[string]string) error {
+	if err := os.MkdirAll(storeDir(), 0700); err != nil {
+		return err
+	}
+	f := storeFile{KeySource: keySourceKeychain}
+	var key []byte
+	var err error
+	if passphrase := os.Getenv(passphraseEnv); passphrase != "" {
+		f.KeySource = keySourcePassphrase
+		f.Salt = make([]byte, saltSize)
+		if _, err = rand.Read(f.Salt); err != nil {
+			return err
+		}
+		key, err = passphraseKey(passphrase, f.Salt)
+	} else {
+		key, err = keychainKey()
+	}
+	if err != nil {
+		return err
+	}
+	plain, err := json.Marshal(store)
+	if err != nil {
+		return err
+	}
+	if f.Data, err = encrypt(key, plain); err != nil {
+		return err
+	}
+	content, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	storePath := filepath.Join(storeDir(), storeFileName)
+	if err = os.WriteFile(storePath, content, 0600); err != nil {
+		return err
+	}
+	// WriteFile keeps the mode of an existing file
+	return os.Chmod(storePath, 0600)
+}
+
+// keychainKey returns the key of the store in the OS keychain, creating a
+// random one if there's none
+func keychainKey() ([]byte, error) {
+	key, err := keychain.GetKey(storeDir())
+	if err == nil && len(key) == keySize {
+		return key, nil
+	}
+	key = make([]byte, keySize)
+	if _, err = rand.Read(key); err != nil {
+		return nil, err
+	}
+	if err = keychain.SetKey(storeDir(), key); err != nil {
+		return nil, fmt.Errorf("unable to save the key of the secrets store in the OS keychain, set %s to encrypt the store with a passphrase instead: %v", passphraseEnv, err)
+	}
+	return key, nil
+}
+
+// passphraseKey derives the key of the store from a passphrase
+func passphraseKey(passphrase string, salt []byte) ([]byte, error) {
+	return pbkdf2.Key(sha256.New, passphrase, salt, pbkdf2Iterations, keySize)
+}
+
+// encrypt seals plain with AES-GCM, prefixing the nonce
+func encrypt(key []byte, plain []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, plain, nil), nil
+}
+
+// decrypt opens content sealed by encrypt
+func decrypt(key []byte, content []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(content) < gcm.NonceSize() {
+		return nil, errors.New("the content is too short")
+	}
+	nonce, sealed := content[:gcm.NonceSize()], content[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, sealed, nil)
+}
+
+// newGCM returns the AES-GCM cipher for key
+func newGCM(key []byte) (cipher.AEAD, error) {
+	if len(key) != keySize {
+		return nil, fmt.Errorf("the key must be %d bytes long, not %d", keySize, len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,152 @@
+// Package supportbundle collects troubleshooting information into a single
+// redacted tar.gz that can be attached to issue reports.
+package supportbundle
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"time"
+)
+
+// ManifestName is the name of the manifest inside the bundle
+const ManifestName = "manifest.json"
+
+// Entry describes one item of the bundle in the manifest. Items that could
+// not be collected have an Error and no file in the bundle.
+type Entry struct {
+	Path        string `json:"path"`
+	Description string `json:"description"`
+	Size        int    `json:"size"`
+	Redactions  int    `json:"redactions"`
+	Error       string `json:"error,omitempty"`
+}
+
+// Manifest lists what was collected into the bundle
+type Manifest struct {
+	DdevVersion string    `json:"ddev_version"`
+	Project     string    `json:"project,omitempty"`
+	Created     time.Time `json:"created"`
+	Entries     []Entry   `json:"entries"`
+}
+
+// Bundle holds the collected items in memory until it's written
+type Bundle struct {
+	Manifest Manifest
+	contents map[string][]byte
+}
+
+// New returns an empty bundle
+func New(ddevVersion string, project string) *Bundle {
+	return &Bundle{
+		Manifest: Manifest{DdevVersion: ddevVersion, Project: project, Created: time.Now().UTC()},
+		contents: map[string][]byte{},
+	}
+}
+
+// Add redacts content and adds it to the bundle as name
+func (b *Bundle) Add(name string, description string, content []byte) {
+	redacted, count := Redact(content)
+	b.contents[name] = redacted
+	b.Manifest.Entries = append(b.Manifest.Entries, Entry{Path: name, Description: description, Size: len(redacted), Redactions: count})
+}
+
+// AddError records in the manifest that name could not be collected
+func (b *Bundle) AddError(name string, description string, err error) {
+	b.Manifest.Entries = append(b.Manifest.Entries, Entry{Path: name, Description: description, Error: err.Error()})
+}
+
+// Collect adds the output of fn as name, or records its error. Output that
+// comes along with an error is still added.
+func (b *Bundle) Collect(name string, description string, fn func() (string, error)) {
+	out, err := fn()
+	if err != nil && out == "" {
+		b.AddError(name, description, err)
+		return
+	}
+	if err != nil {
+		out = fmt.Sprintf("%s\n\nError: %v\n", out, err)
+	}
+	b.Add(name, description, []byte(out))
+}
+
+// AddFile adds the contents of the file src as name. A missing file is
+// left out silently, other errors are recorded.
+func (b *Bundle) AddFile(name string, description string, src string) {
+	content, err := os.ReadFile(src)
+	if os.IsNotExist(err) {
+		return
+	}
+	if err != nil {
+		b.AddError(name, description, err)
+		return
+	}
+	b.Add(name, description, content)
+}
+
+// Has reports whether name was added to the bundle
+func (b *Bundle) Has(name string) bool {
+	_, ok := b.contents[name]
+	return ok
+}
+
+// Write writes the bundle as a tar.gz with all files in the top-level
+// directory root and the manifest first
+func (b *Bundle) Write(w io.Writer, root string) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	manifest, err := json.MarshalIndent(b.Manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = writeTarFile(tw, path.Join(root, ManifestName), append(manifest, '\n'), b.Manifest.Created); err != nil {
+		return err
+	}
+	for _, e := range b.Manifest.Entries {
+		content, ok := b.contents[e.Path]
+		if !ok {
+			continue
+		}
+		if err = writeTarFile(tw, path.Join(root, e.Path), content, b.Manifest.Created); err != nil {
+			return err
+		}
+	}
+	if err = tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// WriteFile writes the bundle to the file dest
+func (b *Bundle) WriteFile(dest string, root string) error {
+	f, err := os.OpenFile(dest, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if err = b.Write(f, root); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// writeTarFile writes a single regular file into the tar stream
+func writeTarFile(tw *tar.Writer, name string, content []byte, modTime time.Time) error {
+	err := tw.WriteHeader(&tar.Header{
+		Name:     name,
+		Mode:     0600,
+		Size:     int64(len(content)),
+		ModTime:  modTime,
+		Typeflag: tar.TypeReg,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = tw.Write(content)
+	return err
+}
@@ -3,11 +3,13 @@ package tui
 import (
 	"bufio"
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
 	"runtime"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -95,6 +97,12 @@ func loadDetailCmd(appRoot string) tea.Cmd {
 
 		detail.Addons = ddevapp.GetInstalledAddonNames(app)
 
+		if app.IsMutagenEnabled() && detail.Status == ddevapp.SiteRunning {
+			if conflicts, err := app.MutagenConflicts(); err == nil {
+				detail.MutagenConflicts = len(conflicts)
+			}
+		}
+
 		if services, ok := desc["services"].(map[string]map[string]any); ok {
 			for name, svc := range services {
 				status, _ := svc["status"].(string)
@@ -166,6 +174,109 @@ func waitForLogLineCmd(ch <-chan string) tea.Cmd {
 	}
 }
 
+// startServiceLogStreamCmd starts `ddev logs -f -j` for several services as a
+// background subprocess and streams its JSON lines into the TUI as log entries.
+func startServiceLogStreamCmd(appRoot string, services []string) tea.Cmd {
+	return func() tea.Msg {
+		ddevBin, err := os.Executable()
+		if err != nil {
+			return logStreamEndedMsg{}
+		}
+
+		cmd := exec.Command(ddevBin, "logs", "-f", "-j", "--tail", "100", "-s", strings.Join(services, ","))
+		cmd.Dir = appRoot
+		cmd.Env = append(os.Environ(), "DDEV_NO_TUI=true")
+
+		stdout, err := cmd.StdoutPipe()
+		if err != nil {
+			return logStreamEndedMsg{}
+		}
+
+		if err := cmd.Start(); err != nil {
+			return logStreamEndedMsg{}
+		}
+
+		ch := make(chan ddevapp.LogEntry, 100)
+		go func() {
+			scanner := bufio.NewScanner(stdout)
+			for scanner.Scan() {
+				// Warnings are JSON too, but they have no service
+				var entry ddevapp.LogEntry
+				if json.Unmarshal(scanner.Bytes(), &entry) == nil && entry.Service != "" {
+					ch <- entry
+				}
+			}
+			_ = cmd.Wait()
+			close(ch)
+		}()
+
+		return serviceLogStreamStartedMsg{entries: ch, process: cmd.Process}
+	}
+}
+
+// waitForServiceLogLineCmd waits for the next entry from the multi-service
+// log stream channel.
+func waitForServiceLogLineCmd(ch <-chan ddevapp.LogEntry) tea.Cmd {
+	if ch == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		entry, ok := <-ch
+		if !ok {
+			return logStreamEndedMsg{}
+		}
+		return serviceLogLineMsg{entry: entry}
+	}
+}
+
+// loadSnapshotsCmd lists the database snapshots of a project in the
+// background, newest first.
+func loadSnapshotsCmd(appRoot string) tea.Cmd {
+	return func() tea.Msg {
+		app, err := ddevapp.NewApp(appRoot, true)
+		if err != nil {
+			return snapshotsLoadedMsg{err: err}
+		}
+		snapshots, err := app.ListSnapshots()
+		if err != nil {
+			return snapshotsLoadedMsg{err: err}
+		}
+		sort.SliceStable(snapshots, func(i, j int) bool {
+			return snapshots[i].Created.After(snapshots[j].Created)
+		})
+		return snapshotsLoadedMsg{snapshots: snapshots}
+	}
+}
+
+// loadResourcesCmd samples the resource use of all projects in the
+// background, which takes about a second.
+func loadResourcesCmd() tea.Msg {
+	apps, err := ddevapp.GetProjects(false)
+	if err != nil {
+		return resourcesLoadedMsg{err: err}
+	}
+	resources, err := ddevapp.GetProjectResources(apps)
+	if err != nil {
+		return resourcesLoadedMsg{err: err}
+	}
+	return resourcesLoadedMsg{resources: resources}
+}
+
+// loadAddonsCmd lists the installed add-ons of a project in the background.
+func loadAddonsCmd(appRoot string) tea.Cmd {
+	return func() tea.Msg {
+		app, err := ddevapp.NewApp(appRoot, true)
+		if err != nil {
+			return addonsLoadedMsg{err: err}
+		}
+		addons := ddevapp.GetInstalledAddons(app)
+		sort.Slice(addons, func(i, j int) bool {
+			return addons[i].Name < addons[j].Name
+		})
+		return addonsLoadedMsg{addons: addons}
+	}
+}
+
 // startOperationStreamCmd starts a ddev subcommand as a background subprocess
 // and streams its output line-by-line into the TUI. Unlike startLogStreamCmd,
 // it captures the exit status via a separate error channel.
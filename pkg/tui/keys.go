@@ -30,6 +30,20 @@ type KeyMap struct {
 	Config   key.Binding
 	PageUp   key.Binding
 	PageDown key.Binding
+	// Detail view panels
+	ServiceLogs key.Binding
+	Exec        key.Binding
+	Snapshots   key.Binding
+	Addons      key.Binding
+	// Dashboard resource monitor
+	Resources key.Binding
+	Sort      key.Binding
+	// Actions inside the panels
+	Zoom    key.Binding
+	Create  key.Binding
+	Restore key.Binding
+	Delete  key.Binding
+	Upgrade key.Binding
 }
 
 // DefaultKeyMap returns the default key bindings.
@@ -139,5 +153,49 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("pgdown"),
 			key.WithHelp("pgdown", "page down"),
 		),
+		ServiceLogs: key.NewBinding(
+			key.WithKeys("v"),
+			key.WithHelp("v", "all logs"),
+		),
+		Exec: key.NewBinding(
+			key.WithKeys(":"),
+			key.WithHelp(":", "exec"),
+		),
+		Snapshots: key.NewBinding(
+			key.WithKeys("n"),
+			key.WithHelp("n", "snapshots"),
+		),
+		Addons: key.NewBinding(
+			key.WithKeys("o"),
+			key.WithHelp("o", "add-ons"),
+		),
+		Resources: key.NewBinding(
+			key.WithKeys("u"),
+			key.WithHelp("u", "resources"),
+		),
+		Sort: key.NewBinding(
+			key.WithKeys("o"),
+			key.WithHelp("o", "sort"),
+		),
+		Zoom: key.NewBinding(
+			key.WithKeys("tab"),
+			key.WithHelp("tab", "zoom pane"),
+		),
+		Create: key.NewBinding(
+			key.WithKeys("c"),
+			key.WithHelp("c", "create"),
+		),
+		Restore: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "restore"),
+		),
+		Delete: key.NewBinding(
+			key.WithKeys("D"),
+			key.WithHelp("D", "delete"),
+		),
+		Upgrade: key.NewBinding(
+			key.WithKeys("u"),
+			key.WithHelp("u", "upgrade"),
+		),
 	}
 }
@@ -55,6 +55,8 @@ type ProjectDetail struct {
 	Addons          []string
 	Services        []ServiceInfo
 	AppRoot         string
+	// MutagenConflicts is the number of Mutagen sync conflicts
+	MutagenConflicts int
 }
 
 // projectDetailLoadedMsg is sent when project detail has been fetched.
@@ -77,6 +79,36 @@ type logLineMsg struct {
 // logStreamEndedMsg is sent when the log stream closes.
 type logStreamEndedMsg struct{}
 
+// serviceLogStreamStartedMsg is sent when the multi-service log streaming
+// subprocess has started.
+type serviceLogStreamStartedMsg struct {
+	entries <-chan ddevapp.LogEntry
+	process *os.Process
+}
+
+// serviceLogLineMsg is sent for each new line of a service's log.
+type serviceLogLineMsg struct {
+	entry ddevapp.LogEntry
+}
+
+// snapshotsLoadedMsg is sent when the snapshots of a project have been listed.
+type snapshotsLoadedMsg struct {
+	snapshots []ddevapp.Snapshot
+	err       error
+}
+
+// addonsLoadedMsg is sent when the installed add-ons of a project have been listed.
+type addonsLoadedMsg struct {
+	addons []ddevapp.AddonManifest
+	err    error
+}
+
+// resourcesLoadedMsg is sent when the resource use of the projects has been sampled.
+type resourcesLoadedMsg struct {
+	resources []ddevapp.ProjectResources
+	err       error
+}
+
 // operationStreamStartedMsg is sent when an operation stream subprocess has started.
 type operationStreamStartedMsg struct {
 	lines   <-chan string
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 	"time"
@@ -25,6 +26,10 @@ const (
 	viewDetail
 	viewLogs
 	viewOperation
+	viewServiceLogs
+	viewSnapshots
+	viewAddons
+	viewResources
 )
 
 // AppModel is the root Bubble Tea model.
@@ -55,12 +60,36 @@ type AppModel struct {
 	logProcess *os.Process
 	logSub     <-chan string
 
+	// Multi-service log panes
+	logServices     []string
+	serviceLogLines map[string][]string
+	serviceLogSub   <-chan ddevapp.LogEntry
+	logZoom         int // 0 shows every pane, i+1 only logServices[i]
+
+	// Exec prompt in detail view
+	execPrompting bool
+	execInput     string
+	execService   string
+
+	// Snapshot and add-on panels
+	snapshots      []ddevapp.Snapshot
+	addons         []ddevapp.AddonManifest
+	panelCursor    int
+	panelLoading   bool
+	namingSnapshot bool
+	snapshotName   string
+
+	// Resource monitor
+	resources    []ddevapp.ProjectResources
+	resourceSort string
+
 	// Operation streaming
 	operationName       string
 	operationDone       bool
 	operationErr        error
 	operationReturnView int
 	operationErrCh      <-chan error
+	operationKeepOutput bool // don't auto-return, the output is the result
 
 	// Spinner
 	spinner spinner.Model
@@ -79,10 +108,11 @@ type AppModel struct {
 func NewAppModel() AppModel {
 	s := spinner.New(spinner.WithSpinner(spinner.Dot))
 	return AppModel{
-		keys:    DefaultKeyMap(),
-		styles:  NewStyles(),
-		loading: true,
-		spinner: s,
+		keys:         DefaultKeyMap(),
+		styles:       NewStyles(),
+		loading:      true,
+		spinner:      s,
+		resourceSort: "name",
 	}
 }
 
@@ -152,6 +182,7 @@ func (m AppModel) enterOperationView(name string, returnView int) AppModel {
 	m.operationErr = nil
 	m.operationReturnView = returnView
 	m.operationErrCh = nil
+	m.operationKeepOutput = false
 	m.statusMsg = ""
 	return m
 }
@@ -263,9 +294,58 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m, waitForLogLineCmd(m.logSub)
 
+	case serviceLogStreamStartedMsg:
+		m.logProcess = msg.process
+		m.serviceLogSub = msg.entries
+		return m, waitForServiceLogLineCmd(m.serviceLogSub)
+
+	case serviceLogLineMsg:
+		if m.serviceLogLines == nil {
+			m.serviceLogLines = map[string][]string{}
+		}
+		lines := append(m.serviceLogLines[msg.entry.Service], msg.entry.Message)
+		// Cap at 1000 lines per service to prevent unbounded growth
+		if len(lines) > 1000 {
+			lines = lines[len(lines)-500:]
+		}
+		m.serviceLogLines[msg.entry.Service] = lines
+		return m, waitForServiceLogLineCmd(m.serviceLogSub)
+
 	case logStreamEndedMsg:
 		m.logProcess = nil
 		m.logSub = nil
+		m.serviceLogSub = nil
+		return m, nil
+
+	case snapshotsLoadedMsg:
+		m.panelLoading = false
+		if msg.err != nil {
+			m.statusMsg = fmt.Sprintf("Error loading snapshots: %v", msg.err)
+			return m, nil
+		}
+		m.snapshots = msg.snapshots
+		m.panelCursor = min(m.panelCursor, max(0, len(m.snapshots)-1))
+		return m, nil
+
+	case addonsLoadedMsg:
+		m.panelLoading = false
+		if msg.err != nil {
+			m.statusMsg = fmt.Sprintf("Error loading add-ons: %v", msg.err)
+			return m, nil
+		}
+		m.addons = msg.addons
+		m.panelCursor = min(m.panelCursor, max(0, len(m.addons)-1))
+		return m, nil
+
+	case resourcesLoadedMsg:
+		m.panelLoading = false
+		if msg.err != nil {
+			m.statusMsg = fmt.Sprintf("Error loading resources: %v", msg.err)
+			return m, nil
+		}
+		m.resources = msg.resources
+		_ = ddevapp.SortProjectResources(m.resources, m.resourceSort)
+		m.panelCursor = min(m.panelCursor, max(0, len(m.resources)-1))
 		return m, nil
 
 	case operationStreamEndedMsg:
@@ -279,8 +359,11 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if m.operationReturnView == viewDetail && m.detail != nil {
 			cmds = append(cmds, loadDetailCmd(m.detail.AppRoot))
 		}
+		if cmd := m.panelLoadCmd(m.operationReturnView); cmd != nil {
+			cmds = append(cmds, cmd)
+		}
 		// Auto-return on success after a short delay; stay on error so user can read output
-		if msg.err == nil {
+		if msg.err == nil && !m.operationKeepOutput {
 			cmds = append(cmds, scheduleOperationAutoReturn())
 		}
 		return m, tea.Batch(cmds...)
@@ -329,11 +412,18 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, tea.Batch(loadDetailCmd(m.detail.AppRoot), loadRouterStatus, tickCmd())
 			}
 			return m, tea.Batch(loadRouterStatus, tickCmd())
-		case viewLogs, viewOperation:
+		case viewLogs, viewServiceLogs, viewOperation:
 			// No auto-refresh while streaming logs or operations
 			return m, tickCmd()
 		case viewDashboard:
 			return m, tea.Batch(loadProjects, loadRouterStatus, tickCmd())
+		case viewResources:
+			// Sampling takes a while, don't start another one meanwhile
+			if m.panelLoading {
+				return m, tickCmd()
+			}
+			m.panelLoading = true
+			return m, tea.Batch(loadResourcesCmd, tickCmd())
 		default:
 			return m, tickCmd()
 		}
@@ -354,6 +444,14 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m.handleLogKey(msg)
 		case viewOperation:
 			return m.handleOperationKey(msg)
+		case viewServiceLogs:
+			return m.handleServiceLogKey(msg)
+		case viewSnapshots:
+			return m.handleSnapshotKey(msg)
+		case viewAddons:
+			return m.handleAddonKey(msg)
+		case viewResources:
+			return m.handleResourceKey(msg)
 		default:
 			return m.handleDashboardKey(msg)
 		}
@@ -364,7 +462,7 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 // isLoading returns true if any loading state is active.
 func (m AppModel) isLoading() bool {
-	return m.loading || m.detailLoading || (m.viewMode == viewOperation && !m.operationDone)
+	return m.loading || m.detailLoading || m.panelLoading || (m.viewMode == viewOperation && !m.operationDone)
 }
 
 func (m AppModel) handleDashboardKey(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
@@ -548,6 +646,14 @@ func (m AppModel) handleDashboardKey(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 	case key.Matches(msg, m.keys.Config):
 		return m, ddevConfigCommand()
 
+	case key.Matches(msg, m.keys.Resources):
+		m.viewMode = viewResources
+		m.resources = nil
+		m.panelCursor = 0
+		m.panelLoading = true
+		m.statusMsg = ""
+		return m, tea.Batch(loadResourcesCmd, m.spinner.Tick)
+
 	case key.Matches(msg, m.keys.Refresh):
 		m.loading = true
 		m.statusMsg = "Refreshing..."
@@ -570,6 +676,11 @@ func (m AppModel) handleDashboardKey(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 }
 
 func (m AppModel) handleDetailKey(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	// The exec prompt takes all keys while it's open
+	if m.execPrompting {
+		return m.handleExecPromptKey(msg)
+	}
+
 	var cmd tea.Cmd
 
 	switch {
@@ -597,6 +708,54 @@ func (m AppModel) handleDetailKey(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
+	case key.Matches(msg, m.keys.ServiceLogs):
+		if m.detail != nil {
+			services := runningServices(m.detail.Services)
+			if len(services) == 0 {
+				m.statusMsg = "No running services to show logs for"
+				return m, nil
+			}
+			m.viewMode = viewServiceLogs
+			m.logServices = services
+			m.serviceLogLines = map[string][]string{}
+			m.logZoom = 0
+			return m, startServiceLogStreamCmd(m.detail.AppRoot, services)
+		}
+		return m, nil
+
+	case key.Matches(msg, m.keys.Exec):
+		if m.detail != nil && m.detail.Status == ddevapp.SiteRunning {
+			m.execPrompting = true
+			m.execInput = ""
+			m.execService = "web"
+			if services := runningServices(m.detail.Services); len(services) > 0 && !slices.Contains(services, "web") {
+				m.execService = services[0]
+			}
+		}
+		return m, nil
+
+	case key.Matches(msg, m.keys.Snapshots):
+		if m.detail != nil {
+			m.viewMode = viewSnapshots
+			m.snapshots = nil
+			m.panelCursor = 0
+			m.panelLoading = true
+			m.statusMsg = ""
+			return m, tea.Batch(loadSnapshotsCmd(m.detail.AppRoot), m.spinner.Tick)
+		}
+		return m, nil
+
+	case key.Matches(msg, m.keys.Addons):
+		if m.detail != nil {
+			m.viewMode = viewAddons
+			m.addons = nil
+			m.panelCursor = 0
+			m.panelLoading = true
+			m.statusMsg = ""
+			return m, tea.Batch(loadAddonsCmd(m.detail.AppRoot), m.spinner.Tick)
+		}
+		return m, nil
+
 	case key.Matches(msg, m.keys.Start):
 		if m.detail != nil {
 			m = m.enterOperationView(fmt.Sprintf("Starting %s", m.detail.Name), viewDetail)
@@ -704,6 +863,10 @@ func (m AppModel) handleOperationKey(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 			m.detailLoading = true
 			cmds = append(cmds, loadDetailCmd(m.detail.AppRoot))
 		}
+		if cmd := m.panelLoadCmd(returnView); cmd != nil {
+			m.panelLoading = true
+			cmds = append(cmds, cmd)
+		}
 		return m, tea.Batch(cmds...)
 
 	case key.Matches(msg, m.keys.Quit):
@@ -729,6 +892,14 @@ func (m AppModel) View() tea.View {
 		content = m.logView()
 	case m.viewMode == viewOperation:
 		content = m.operationView()
+	case m.viewMode == viewServiceLogs:
+		content = m.serviceLogView()
+	case m.viewMode == viewSnapshots:
+		content = m.snapshotView()
+	case m.viewMode == viewAddons:
+		content = m.addonView()
+	case m.viewMode == viewResources:
+		content = m.resourceView()
 	default:
 		content = m.dashboardView()
 	}
@@ -963,6 +1134,9 @@ func (m AppModel) buildDetailContent() string {
 	fmt.Fprintf(&content, " %s %s    %s %s\n", label("Webserver:"), val(fmt.Sprintf("%-14s", d.WebserverType)), label("Node.js:"), val(d.NodeJSVersion))
 	fmt.Fprintf(&content, " %s %s    %s %s\n", label("Docroot:"), val(fmt.Sprintf("%-14s", d.Docroot)), label("Perf:"), val(perfStr))
 	fmt.Fprintf(&content, " %s %s\n", label("Database:"), val(dbStr))
+	if d.MutagenConflicts > 0 {
+		fmt.Fprintf(&content, " %s %s\n", label("Mutagen:"), m.styles.Paused.Render(fmt.Sprintf("%d sync conflicts, see 'ddev mutagen conflicts'", d.MutagenConflicts)))
+	}
 	content.WriteString("\n")
 
 	// URLs
@@ -1119,11 +1293,20 @@ func (m AppModel) detailView() string {
 	// Use viewport for scrollable content
 	b.WriteString(m.detailViewport.View())
 
+	// Exec prompt
+	if m.execPrompting {
+		fmt.Fprintf(&b, "\n%s %s█", m.styles.HelpKey.Render(fmt.Sprintf("exec [%s]>", m.execService)), m.execInput)
+	}
+
 	// Bottom divider
 	b.WriteString("\n" + m.styles.Divider.Render(strings.Repeat("─", dividerWidth)) + "\n")
 
 	// Key hints
-	b.WriteString(m.detailKeyHints())
+	if m.execPrompting {
+		b.WriteString(m.execPromptKeyHints())
+	} else {
+		b.WriteString(m.detailKeyHints())
+	}
 
 	return b.String()
 }
@@ -1220,6 +1403,8 @@ func (m AppModel) operationView() string {
 	if m.operationDone {
 		if m.operationErr != nil {
 			b.WriteString(m.styles.Stopped.Render(fmt.Sprintf("Failed: %v", m.operationErr)) + "\n")
+		} else if m.operationKeepOutput {
+			b.WriteString(m.styles.Running.Render("Completed — press esc to return") + "\n")
 		} else {
 			b.WriteString(m.styles.Running.Render("Completed — returning shortly...") + "\n")
 		}
@@ -1327,6 +1512,7 @@ func (m AppModel) dashboardKeyHints() string {
 		{"m", "mailpit"},
 		{"x", "xhgui"},
 		{"C", "config"},
+		{"u", "resources"},
 		{"enter", "detail"},
 		{"/", "filter"},
 		{"?", "help"},
@@ -1349,7 +1535,11 @@ func (m AppModel) detailKeyHints() string {
 		{"X", "xdebug"},
 		{"c", "copy url"},
 		{"e", "ssh"},
+		{":", "exec"},
 		{"L", "logs"},
+		{"v", "all logs"},
+		{"n", "snapshots"},
+		{"o", "add-ons"},
 		{"R", "refresh"},
 		{"esc", "back"},
 	}
@@ -1407,6 +1597,8 @@ Actions:
   A               Stop all projects
   P               Poweroff all DDEV projects and containers
   C               Run ddev config interactively
+  u               Show CPU, memory and disk use of projects
+  o               Sort by name, CPU, memory or disk (from resources view)
   l               Launch project URL in browser
   m               Launch Mailpit in browser
   x               Launch XHGui (enable xhprof + open UI)
@@ -1414,6 +1606,10 @@ Actions:
   c               Copy primary URL to clipboard (from detail view)
   e               SSH into web container (from detail view)
   L               Follow logs (from detail view)
+  v               Follow logs of all services in panes (from detail view)
+  :               Run a command with ddev exec (from detail view)
+  n               Manage database snapshots (from detail view)
+  o               Manage add-ons (from detail view)
   R               Refresh
 
 Other:
@@ -616,6 +616,23 @@ func TestDetailViewRendering(t *testing.T) {
 	require.Contains(t, view, "back", "should contain back key hint")
 }
 
+func TestDetailViewMutagenConflicts(t *testing.T) {
+	m := NewAppModel()
+	m.viewMode = viewDetail
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 80, Height: 30})
+	m = updated.(AppModel)
+
+	updated, _ = m.Update(projectDetailLoadedMsg{detail: sampleDetail()})
+	m = updated.(AppModel)
+	require.NotContains(t, m.View().Content, "sync conflicts", "should not warn without conflicts")
+
+	detail := sampleDetail()
+	detail.MutagenConflicts = 2
+	updated, _ = m.Update(projectDetailLoadedMsg{detail: detail})
+	m = updated.(AppModel)
+	require.Contains(t, m.View().Content, "2 sync conflicts, see 'ddev mutagen conflicts'", "should warn about conflicts")
+}
+
 func TestDetailViewLoadingRendering(t *testing.T) {
 	m := NewAppModel()
 	m.viewMode = viewDetail
@@ -1771,3 +1788,316 @@ func TestOperationAutoReturnIgnoredIfAlreadyLeft(t *testing.T) {
 
 	require.Equal(t, viewDashboard, model.viewMode, "should stay on dashboard")
 }
+
+// typeText sends the keys of a string to the model.
+func typeText(t *testing.T, m AppModel, s string) AppModel {
+	t.Helper()
+	for _, r := range s {
+		msg := tea.KeyPressMsg{Code: r, Text: string(r)}
+		if r == ' ' {
+			msg = tea.KeyPressMsg{Code: tea.KeySpace, Text: " "}
+		}
+		updated, _ := m.Update(msg)
+		m = updated.(AppModel)
+	}
+	return m
+}
+
+func TestDetailActionServiceLogs(t *testing.T) {
+	m := NewAppModel()
+	m.viewMode = viewDetail
+	detail := sampleDetail()
+	detail.Services = append(detail.Services, ServiceInfo{Name: "redis", Status: ddevapp.SiteRunning}, ServiceInfo{Name: "solr", Status: ddevapp.SiteStopped})
+	m.detail = &detail
+
+	updated, cmd := m.Update(tea.KeyPressMsg{Code: 'v', Text: "v"})
+	model := updated.(AppModel)
+
+	require.Equal(t, viewServiceLogs, model.viewMode, "should switch to service log view")
+	require.Equal(t, []string{"web", "db", "redis"}, model.logServices, "should stream the running services")
+	require.NotNil(t, cmd, "v should return a command to start the log stream")
+}
+
+func TestServiceLogPanes(t *testing.T) {
+	m := NewAppModel()
+	m.viewMode = viewServiceLogs
+	m.width = 80
+	m.height = 30
+	detail := sampleDetail()
+	m.detail = &detail
+	m.logServices = []string{"web", "db"}
+
+	ch := make(chan ddevapp.LogEntry, 1)
+	updated, cmd := m.Update(serviceLogStreamStartedMsg{entries: ch})
+	m = updated.(AppModel)
+	require.NotNil(t, m.serviceLogSub)
+	require.NotNil(t, cmd)
+
+	updated, _ = m.Update(serviceLogLineMsg{entry: ddevapp.LogEntry{Service: "web", Message: "GET /index.php 200"}})
+	m = updated.(AppModel)
+	updated, _ = m.Update(serviceLogLineMsg{entry: ddevapp.LogEntry{Service: "db", Message: "ready for connections"}})
+	m = updated.(AppModel)
+
+	view := m.View().Content
+	require.Contains(t, view, "DDEV Logs: mysite (web, db)")
+	require.Contains(t, view, "GET /index.php 200")
+	require.Contains(t, view, "ready for connections")
+
+	// Tab zooms on the first pane, then the second, then shows all again
+	updated, _ = m.Update(tea.KeyPressMsg{Code: tea.KeyTab})
+	m = updated.(AppModel)
+	view = m.View().Content
+	require.Contains(t, view, "GET /index.php 200")
+	require.NotContains(t, view, "ready for connections")
+	updated, _ = m.Update(tea.KeyPressMsg{Code: tea.KeyTab})
+	m = updated.(AppModel)
+	require.Contains(t, m.View().Content, "DDEV Logs: mysite (db)")
+	updated, _ = m.Update(tea.KeyPressMsg{Code: tea.KeyTab})
+	m = updated.(AppModel)
+	require.Equal(t, 0, m.logZoom)
+
+	updated, _ = m.Update(tea.KeyPressMsg{Code: tea.KeyEscape})
+	m = updated.(AppModel)
+	require.Equal(t, viewDetail, m.viewMode, "esc should return to detail")
+	require.Nil(t, m.serviceLogLines)
+}
+
+func TestExecPrompt(t *testing.T) {
+	m := NewAppModel()
+	m.viewMode = viewDetail
+	m.width = 80
+	detail := sampleDetail()
+	m.detail = &detail
+
+	updated, _ := m.Update(tea.KeyPressMsg{Code: ':', Text: ":"})
+	m = updated.(AppModel)
+	require.True(t, m.execPrompting)
+	require.Equal(t, "web", m.execService)
+
+	// Keys that are actions in detail view are typed into the prompt
+	m = typeText(t, m, "ls -la")
+	require.Equal(t, "ls -la", m.execInput)
+	view := m.View().Content
+	require.Contains(t, view, "exec [web]>")
+	require.Contains(t, view, "ls -la█")
+
+	updated, _ = m.Update(tea.KeyPressMsg{Code: tea.KeyTab})
+	m = updated.(AppModel)
+	require.Equal(t, "db", m.execService, "tab should switch to the next running service")
+
+	updated, cmd := m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	m = updated.(AppModel)
+	require.False(t, m.execPrompting)
+	require.Equal(t, viewOperation, m.viewMode)
+	require.Equal(t, "ddev exec -s db ls -la", m.operationName)
+	require.Equal(t, viewDetail, m.operationReturnView)
+	require.True(t, m.operationKeepOutput)
+	require.NotNil(t, cmd)
+
+	// The output of exec stays on screen
+	updated, _ = m.Update(operationStreamEndedMsg{})
+	m = updated.(AppModel)
+	require.Contains(t, m.View().Content, "press esc to return")
+}
+
+func TestExecPromptCancel(t *testing.T) {
+	m := NewAppModel()
+	m.viewMode = viewDetail
+	detail := sampleDetail()
+	m.detail = &detail
+
+	updated, _ := m.Update(tea.KeyPressMsg{Code: ':', Text: ":"})
+	m = updated.(AppModel)
+	m = typeText(t, m, "pwd")
+	updated, _ = m.Update(tea.KeyPressMsg{Code: tea.KeyEscape})
+	m = updated.(AppModel)
+	require.False(t, m.execPrompting)
+	require.Empty(t, m.execInput)
+	require.Equal(t, viewDetail, m.viewMode, "esc should only close the prompt")
+}
+
+func TestSnapshotPanel(t *testing.T) {
+	m := NewAppModel()
+	m.viewMode = viewDetail
+	m.width = 100
+	detail := sampleDetail()
+	m.detail = &detail
+
+	updated, cmd := m.Update(tea.KeyPressMsg{Code: 'n', Text: "n"})
+	m = updated.(AppModel)
+	require.Equal(t, viewSnapshots, m.viewMode)
+	require.True(t, m.panelLoading)
+	require.NotNil(t, cmd)
+
+	updated, _ = m.Update(snapshotsLoadedMsg{snapshots: []ddevapp.Snapshot{
+		{Name: "before-update", Size: 2048, DBVersion: "mariadb_10.11"},
+		{Name: "initial", Size: 1024, DBVersion: "mariadb_10.11"},
+	}})
+	m = updated.(AppModel)
+	require.False(t, m.panelLoading)
+	view := m.View().Content
+	require.Contains(t, view, "DDEV Snapshots: mysite")
+	require.Contains(t, view, "before-update")
+	require.Contains(t, view, "initial")
+	require.Contains(t, view, "restore")
+
+	// Restore the second snapshot after confirmation
+	updated, _ = m.Update(tea.KeyPressMsg{Code: 'j', Text: "j"})
+	m = updated.(AppModel)
+	updated, _ = m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	m = updated.(AppModel)
+	require.True(t, m.confirming)
+	require.Contains(t, m.statusMsg, "Restore snapshot initial?")
+	updated, cmd = m.Update(tea.KeyPressMsg{Code: 'y', Text: "y"})
+	m = updated.(AppModel)
+	require.Equal(t, viewOperation, m.viewMode)
+	require.Equal(t, "Restoring snapshot initial", m.operationName)
+	require.Equal(t, viewSnapshots, m.operationReturnView)
+	require.NotNil(t, cmd)
+}
+
+func TestSnapshotPanelCreateAndDelete(t *testing.T) {
+	m := NewAppModel()
+	m.viewMode = viewSnapshots
+	detail := sampleDetail()
+	m.detail = &detail
+	m.snapshots = []ddevapp.Snapshot{{Name: "initial"}}
+
+	// Any key but y cancels the deletion
+	updated, _ := m.Update(tea.KeyPressMsg{Code: 'D', Text: "D"})
+	m = updated.(AppModel)
+	require.Contains(t, m.statusMsg, "Delete snapshot initial?")
+	updated, _ = m.Update(tea.KeyPressMsg{Code: 'x', Text: "x"})
+	m = updated.(AppModel)
+	require.False(t, m.confirming)
+	require.Equal(t, viewSnapshots, m.viewMode)
+
+	updated, _ = m.Update(tea.KeyPressMsg{Code: 'c', Text: "c"})
+	m = updated.(AppModel)
+	require.True(t, m.namingSnapshot)
+	m = typeText(t, m, "before update")
+	require.Equal(t, "beforeupdate", m.snapshotName, "spaces aren't allowed in snapshot names")
+	require.Contains(t, m.View().Content, "Snapshot name (empty for a generated one): beforeupdate")
+
+	updated, cmd := m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	m = updated.(AppModel)
+	require.Equal(t, viewOperation, m.viewMode)
+	require.Equal(t, "Creating a snapshot of mysite", m.operationName)
+	require.NotNil(t, cmd)
+}
+
+func TestAddonPanel(t *testing.T) {
+	m := NewAppModel()
+	m.viewMode = viewDetail
+	m.width = 100
+	detail := sampleDetail()
+	m.detail = &detail
+
+	updated, cmd := m.Update(tea.KeyPressMsg{Code: 'o', Text: "o"})
+	m = updated.(AppModel)
+	require.Equal(t, viewAddons, m.viewMode)
+	require.NotNil(t, cmd)
+
+	updated, _ = m.Update(addonsLoadedMsg{addons: []ddevapp.AddonManifest{
+		{Name: "redis", Version: "v2.1.0", Repository: "ddev/ddev-redis"},
+		{Name: "solr", Version: "v1.0.0", Repository: "ddev/ddev-solr"},
+	}})
+	m = updated.(AppModel)
+	view := m.View().Content
+	require.Contains(t, view, "DDEV Add-ons: mysite")
+	require.Contains(t, view, "ddev/ddev-redis")
+	require.Contains(t, view, "upgrade")
+
+	updated, _ = m.Update(tea.KeyPressMsg{Code: 'D', Text: "D"})
+	m = updated.(AppModel)
+	require.Contains(t, m.statusMsg, "Remove add-on redis?")
+	updated, _ = m.Update(tea.KeyPressMsg{Code: 'y', Text: "y"})
+	m = updated.(AppModel)
+	require.Equal(t, "Removing add-on redis", m.operationName)
+	require.Equal(t, viewAddons, m.operationReturnView)
+
+	// Back in the panel after the operation, upgrade the second add-on
+	m.viewMode = viewAddons
+	m.panelCursor = 1
+	updated, cmd = m.Update(tea.KeyPressMsg{Code: 'u', Text: "u"})
+	m = updated.(AppModel)
+	require.Equal(t, "Upgrading add-on solr", m.operationName)
+	require.NotNil(t, cmd)
+
+	m.viewMode = viewAddons
+	updated, _ = m.Update(tea.KeyPressMsg{Code: tea.KeyEscape})
+	m = updated.(AppModel)
+	require.Equal(t, viewDetail, m.viewMode)
+	require.Nil(t, m.addons)
+}
+
+func TestDetailHintsForPanels(t *testing.T) {
+	m := NewAppModel()
+	m.viewMode = viewDetail
+	m.width = 200
+	detail := sampleDetail()
+	m.detail = &detail
+
+	view := m.View().Content
+	require.Contains(t, view, "exec")
+	require.Contains(t, view, "all logs")
+	require.Contains(t, view, "snapshots")
+	require.Contains(t, view, "add-ons")
+}
+
+func TestResourceView(t *testing.T) {
+	m := NewAppModel()
+	m.width = 100
+
+	updated, cmd := m.Update(tea.KeyPressMsg{Code: 'u', Text: "u"})
+	m = updated.(AppModel)
+	require.Equal(t, viewResources, m.viewMode)
+	require.True(t, m.panelLoading)
+	require.NotNil(t, cmd)
+
+	updated, _ = m.Update(resourcesLoadedMsg{resources: []ddevapp.ProjectResources{
+		{Name: "zeta", Status: ddevapp.SiteRunning, CPUPercent: 12.5, MemoryBytes: 300 << 20, VolumeBytes: 1 << 30,
+			Services: []ddevapp.ServiceResources{{Service: "db", CPUPercent: 2.5, MemoryBytes: 100 << 20}, {Service: "web", CPUPercent: 10, MemoryBytes: 200 << 20}}},
+		{Name: "alpha", Status: ddevapp.SiteStopped, VolumeBytes: 3 << 30},
+	}})
+	m = updated.(AppModel)
+	require.False(t, m.panelLoading)
+	require.Equal(t, "alpha", m.resources[0].Name, "sorted by name by default")
+	view := m.View().Content
+	require.Contains(t, view, "DDEV Resources (sorted by name)")
+	require.NotContains(t, view, "web", "only the selected project shows its services")
+
+	// The selected project stays selected when the order changes
+	updated, _ = m.Update(tea.KeyPressMsg{Code: 'j', Text: "j"})
+	m = updated.(AppModel)
+	updated, _ = m.Update(tea.KeyPressMsg{Code: 'o', Text: "o"})
+	m = updated.(AppModel)
+	require.Equal(t, "cpu", m.resourceSort)
+	require.Equal(t, "zeta", m.resources[m.panelCursor].Name)
+	view = m.View().Content
+	require.Contains(t, view, "DDEV Resources (sorted by cpu)")
+	require.Contains(t, view, "12.5%")
+	require.Contains(t, view, "web")
+
+	updated, _ = m.Update(tea.KeyPressMsg{Code: tea.KeyEscape})
+	m = updated.(AppModel)
+	require.Equal(t, viewDashboard, m.viewMode)
+	require.Nil(t, m.resources)
+}
+
+func TestResourceViewTick(t *testing.T) {
+	m := NewAppModel()
+	m.viewMode = viewResources
+
+	// A sample still in progress isn't started again
+	m.panelLoading = true
+	updated, _ := m.Update(tickMsg{})
+	m = updated.(AppModel)
+	require.True(t, m.panelLoading)
+
+	m.panelLoading = false
+	updated, cmd := m.Update(tickMsg{})
+	m = updated.(AppModel)
+	require.True(t, m.panelLoading)
+	require.NotNil(t, cmd)
+}
@@ -0,0 +1,558 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"charm.land/bubbles/v2/key"
+	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/ddev/ddev/pkg/ddevapp"
+	"github.com/ddev/ddev/pkg/util"
+)
+
+// runningServices returns the names of the running services, web first,
+// db second, rest alphabetical.
+func runningServices(services []ServiceInfo) []string {
+	var names []string
+	for _, svc := range sortServices(services) {
+		if svc.Status == ddevapp.SiteRunning {
+			names = append(names, svc.Name)
+		}
+	}
+	return names
+}
+
+// panelLoadCmd returns the command that reloads the snapshot or add-on
+// panel, or nil for other views.
+func (m AppModel) panelLoadCmd(view int) tea.Cmd {
+	if m.detail == nil {
+		return nil
+	}
+	switch view {
+	case viewSnapshots:
+		return loadSnapshotsCmd(m.detail.AppRoot)
+	case viewAddons:
+		return loadAddonsCmd(m.detail.AppRoot)
+	}
+	return nil
+}
+
+// leavePanel returns to the detail view from a panel.
+func (m AppModel) leavePanel() AppModel {
+	m.viewMode = viewDetail
+	m.snapshots = nil
+	m.addons = nil
+	m.panelCursor = 0
+	m.panelLoading = false
+	m.namingSnapshot = false
+	m.confirming = false
+	m.confirmAction = ""
+	m.statusMsg = ""
+	return m
+}
+
+// moveCursor moves the panel cursor within n items.
+func (m AppModel) moveCursor(msg tea.KeyPressMsg, n int) AppModel {
+	switch {
+	case key.Matches(msg, m.keys.Up):
+		m.panelCursor = max(0, m.panelCursor-1)
+	case key.Matches(msg, m.keys.Down):
+		m.panelCursor = max(0, min(n-1, m.panelCursor+1))
+	}
+	return m
+}
+
+func (m AppModel) handleExecPromptKey(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, key.NewBinding(key.WithKeys("esc"))):
+		m.execPrompting = false
+		m.execInput = ""
+		return m, nil
+
+	case key.Matches(msg, key.NewBinding(key.WithKeys("enter"))):
+		command := strings.TrimSpace(m.execInput)
+		m.execPrompting = false
+		m.execInput = ""
+		if command == "" || m.detail == nil {
+			return m, nil
+		}
+		m = m.enterOperationView(fmt.Sprintf("ddev exec -s %s %s", m.execService, command), viewDetail)
+		m.operationKeepOutput = true
+		return m, startOperationStreamCmd(m.detail.AppRoot, "exec", "-s", m.execService, "--", command)
+
+	case key.Matches(msg, m.keys.Zoom):
+		// Cycle through the running services
+		if m.detail != nil {
+			if services := runningServices(m.detail.Services); len(services) > 0 {
+				m.execService = services[(slices.Index(services, m.execService)+1)%len(services)]
+			}
+		}
+		return m, nil
+
+	case key.Matches(msg, key.NewBinding(key.WithKeys("backspace"))):
+		if len(m.execInput) > 0 {
+			m.execInput = m.execInput[:len(m.execInput)-1]
+		}
+		return m, nil
+
+	default:
+		m.execInput += msg.Text
+		return m, nil
+	}
+}
+
+func (m AppModel) execPromptKeyHints() string {
+	hints := []struct {
+		key  string
+		desc string
+	}{
+		{"enter", "run"},
+		{"tab", "service"},
+		{"esc", "cancel"},
+	}
+	return m.renderHints(hints)
+}
+
+func (m AppModel) handleServiceLogKey(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Zoom):
+		m.logZoom = (m.logZoom + 1) % (len(m.logServices) + 1)
+		return m, nil
+
+	case key.Matches(msg, m.keys.Back):
+		if m.logProcess != nil {
+			_ = m.logProcess.Kill()
+		}
+		m.logProcess = nil
+		m.serviceLogSub = nil
+		m.serviceLogLines = nil
+		m.logServices = nil
+		m.logZoom = 0
+		m.viewMode = viewDetail
+		return m, nil
+
+	case key.Matches(msg, m.keys.Quit):
+		if m.logProcess != nil {
+			_ = m.logProcess.Kill()
+		}
+		return m, tea.Quit
+	}
+
+	return m, nil
+}
+
+// serviceLogView renders one pane per service, stacked, each showing the
+// last lines that fit.
+func (m AppModel) serviceLogView() string {
+	var b strings.Builder
+
+	dividerWidth := m.width
+	if dividerWidth <= 0 {
+		dividerWidth = 60
+	}
+
+	name := ""
+	if m.detail != nil {
+		name = m.detail.Name
+	}
+	services := m.logServices
+	if m.logZoom > 0 && m.logZoom <= len(m.logServices) {
+		services = m.logServices[m.logZoom-1 : m.logZoom]
+	}
+
+	b.WriteString(m.styles.Title.Render(fmt.Sprintf("DDEV Logs: %s (%s)", name, strings.Join(services, ", "))) + "\n")
+	b.WriteString(m.styles.Divider.Render(strings.Repeat("─", dividerWidth)) + "\n")
+
+	// Share the height between the panes, each has a header line
+	height := m.height - 4 // title, divider, bottom divider, hints
+	if height < 5 {
+		height = 20
+	}
+	paneHeight := max(1, height/max(1, len(services))-1)
+
+	for _, service := range services {
+		header := " " + service + " "
+		b.WriteString(m.styles.ProjectName.Render(header) + m.styles.Divider.Render(strings.Repeat("─", max(0, dividerWidth-len(header)))) + "\n")
+		lines := m.serviceLogLines[service]
+		if len(lines) == 0 {
+			fmt.Fprintf(&b, "  %s Waiting for log output...\n", m.spinner.View())
+			for range paneHeight - 1 {
+				b.WriteString("\n")
+			}
+			continue
+		}
+		start := max(0, len(lines)-paneHeight)
+		for _, line := range lines[start:] {
+			if m.width > 0 {
+				line = ansi.Truncate(line, m.width, "")
+			}
+			b.WriteString(line + "\n")
+		}
+		for range paneHeight - (len(lines) - start) {
+			b.WriteString("\n")
+		}
+	}
+
+	b.WriteString(m.styles.Divider.Render(strings.Repeat("─", dividerWidth)) + "\n")
+	hints := []struct {
+		key  string
+		desc string
+	}{
+		{"tab", "zoom pane"},
+		{"esc", "back"},
+		{"q", "quit"},
+	}
+	b.WriteString(m.renderHints(hints))
+
+	return b.String()
+}
+
+// handlePanelConfirm runs or cancels the action awaiting confirmation in a panel.
+func (m AppModel) handlePanelConfirm(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	action := m.confirmAction
+	m.confirming = false
+	m.confirmAction = ""
+	m.statusMsg = ""
+	if !key.Matches(msg, m.keys.Confirm) || m.detail == nil {
+		return m, nil
+	}
+	switch action {
+	case "restore-snapshot":
+		if m.panelCursor < len(m.snapshots) {
+			name := m.snapshots[m.panelCursor].Name
+			m = m.enterOperationView(fmt.Sprintf("Restoring snapshot %s", name), viewSnapshots)
+			return m, startOperationStreamCmd(m.detail.AppRoot, "snapshot", "restore", name)
+		}
+	case "delete-snapshot":
+		if m.panelCursor < len(m.snapshots) {
+			name := m.snapshots[m.panelCursor].Name
+			m = m.enterOperationView(fmt.Sprintf("Deleting snapshot %s", name), viewSnapshots)
+			return m, startOperationStreamCmd(m.detail.AppRoot, "snapshot", "--cleanup", "--name", name, "--yes")
+		}
+	case "remove-addon":
+		if m.panelCursor < len(m.addons) {
+			name := m.addons[m.panelCursor].Name
+			m = m.enterOperationView(fmt.Sprintf("Removing add-on %s", name), viewAddons)
+			return m, startOperationStreamCmd(m.detail.AppRoot, "add-on", "remove", name)
+		}
+	}
+	return m, nil
+}
+
+func (m AppModel) handleSnapshotKey(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	if m.confirming {
+		return m.handlePanelConfirm(msg)
+	}
+
+	// Typing the name of a new snapshot
+	if m.namingSnapshot {
+		switch {
+		case key.Matches(msg, key.NewBinding(key.WithKeys("esc"))):
+			m.namingSnapshot = false
+			m.snapshotName = ""
+		case key.Matches(msg, key.NewBinding(key.WithKeys("enter"))):
+			m.namingSnapshot = false
+			args := []string{"snapshot"}
+			if name := strings.TrimSpace(m.snapshotName); name != "" {
+				args = append(args, "--name", name)
+			}
+			m.snapshotName = ""
+			if m.detail != nil {
+				m = m.enterOperationView(fmt.Sprintf("Creating a snapshot of %s", m.detail.Name), viewSnapshots)
+				return m, startOperationStreamCmd(m.detail.AppRoot, args...)
+			}
+		case key.Matches(msg, key.NewBinding(key.WithKeys("backspace"))):
+			if len(m.snapshotName) > 0 {
+				m.snapshotName = m.snapshotName[:len(m.snapshotName)-1]
+			}
+		default:
+			// Snapshot names become file names, leave out spaces
+			if msg.Text != " " {
+				m.snapshotName += msg.Text
+			}
+		}
+		return m, nil
+	}
+
+	switch {
+	case key.Matches(msg, m.keys.Up), key.Matches(msg, m.keys.Down):
+		return m.moveCursor(msg, len(m.snapshots)), nil
+
+	case key.Matches(msg, m.keys.Back):
+		return m.leavePanel(), nil
+
+	case key.Matches(msg, m.keys.Quit):
+		return m, tea.Quit
+
+	case key.Matches(msg, m.keys.Create):
+		m.namingSnapshot = true
+		m.snapshotName = ""
+		m.statusMsg = ""
+		return m, nil
+
+	case key.Matches(msg, m.keys.Restore):
+		if m.panelCursor < len(m.snapshots) {
+			m.confirming = true
+			m.confirmAction = "restore-snapshot"
+			m.statusMsg = fmt.Sprintf("Restore snapshot %s? This replaces the current database. (y to confirm, any key to cancel)", m.snapshots[m.panelCursor].Name)
+		}
+		return m, nil
+
+	case key.Matches(msg, m.keys.Delete):
+		if m.panelCursor < len(m.snapshots) {
+			m.confirming = true
+			m.confirmAction = "delete-snapshot"
+			m.statusMsg = fmt.Sprintf("Delete snapshot %s? (y to confirm, any key to cancel)", m.snapshots[m.panelCursor].Name)
+		}
+		return m, nil
+
+	case key.Matches(msg, m.keys.Refresh):
+		m.panelLoading = true
+		return m, tea.Batch(m.panelLoadCmd(viewSnapshots), m.spinner.Tick)
+	}
+
+	return m, nil
+}
+
+func (m AppModel) handleAddonKey(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	if m.confirming {
+		return m.handlePanelConfirm(msg)
+	}
+
+	switch {
+	case key.Matches(msg, m.keys.Up), key.Matches(msg, m.keys.Down):
+		return m.moveCursor(msg, len(m.addons)), nil
+
+	case key.Matches(msg, m.keys.Back):
+		return m.leavePanel(), nil
+
+	case key.Matches(msg, m.keys.Quit):
+		return m, tea.Quit
+
+	case key.Matches(msg, m.keys.Upgrade):
+		if m.panelCursor < len(m.addons) && m.detail != nil {
+			addon := m.addons[m.panelCursor]
+			// Add-ons installed from a local directory have no repository
+			source := addon.Repository
+			if source == "" {
+				source = addon.Name
+			}
+			m = m.enterOperationView(fmt.Sprintf("Upgrading add-on %s", addon.Name), viewAddons)
+			return m, startOperationStreamCmd(m.detail.AppRoot, "add-on", "get", source)
+		}
+		return m, nil
+
+	case key.Matches(msg, m.keys.Delete):
+		if m.panelCursor < len(m.addons) {
+			m.confirming = true
+			m.confirmAction = "remove-addon"
+			m.statusMsg = fmt.Sprintf("Remove add-on %s? (y to confirm, any key to cancel)", m.addons[m.panelCursor].Name)
+		}
+		return m, nil
+
+	case key.Matches(msg, m.keys.Refresh):
+		m.panelLoading = true
+		return m, tea.Batch(m.panelLoadCmd(viewAddons), m.spinner.Tick)
+	}
+
+	return m, nil
+}
+
+// panelView renders the frame shared by the snapshot and add-on panels
+// around the given rows.
+func (m AppModel) panelView(title string, rows []string, empty string, prompt string, hints []struct {
+	key  string
+	desc string
+}) string {
+	var b strings.Builder
+
+	dividerWidth := m.width
+	if dividerWidth <= 0 {
+		dividerWidth = 60
+	}
+
+	b.WriteString(m.styles.Title.Render(title) + "\n")
+	b.WriteString(m.styles.Divider.Render(strings.Repeat("─", dividerWidth)) + "\n\n")
+
+	switch {
+	case m.panelLoading && len(rows) == 0:
+		fmt.Fprintf(&b, "  %s Loading...\n", m.spinner.View())
+	case len(rows) == 0:
+		b.WriteString("  " + empty + "\n")
+	default:
+		for i, row := range rows {
+			cursor := "  "
+			if i == m.panelCursor {
+				cursor = m.styles.Cursor.Render("> ")
+			}
+			// Rows can span lines, the later ones aren't selectable
+			for j, line := range strings.Split(row, "\n") {
+				if m.width > 0 {
+					line = ansi.Truncate(line, m.width-2, "...")
+				}
+				if j > 0 {
+					cursor = "  "
+				}
+				b.WriteString(cursor + line + "\n")
+			}
+		}
+	}
+
+	if prompt != "" {
+		b.WriteString("\n" + prompt + "█\n")
+	}
+	if m.statusMsg != "" {
+		msg := m.statusMsg
+		if m.width > 0 {
+			msg = ansi.Wrap(msg, m.width, "")
+		}
+		b.WriteString("\n" + msg + "\n")
+	}
+
+	b.WriteString("\n" + m.styles.Divider.Render(strings.Repeat("─", dividerWidth)) + "\n")
+	b.WriteString(m.renderHints(hints))
+
+	return b.String()
+}
+
+func (m AppModel) snapshotView() string {
+	name := ""
+	if m.detail != nil {
+		name = m.detail.Name
+	}
+
+	nameWidth := 20
+	for _, s := range m.snapshots {
+		nameWidth = max(nameWidth, len(s.Name))
+	}
+	var rows []string
+	for _, s := range m.snapshots {
+		rows = append(rows, fmt.Sprintf("%-*s  %s  %9s  %s", nameWidth, s.Name, s.Created.Format("2006-01-02 15:04"), util.FormatBytes(s.Size), s.DBVersion))
+	}
+
+	prompt := ""
+	if m.namingSnapshot {
+		prompt = "Snapshot name (empty for a generated one): " + m.snapshotName
+	}
+
+	hints := []struct {
+		key  string
+		desc string
+	}{
+		{"c", "create"},
+		{"enter", "restore"},
+		{"D", "delete"},
+		{"R", "refresh"},
+		{"esc", "back"},
+	}
+	return m.panelView(fmt.Sprintf("DDEV Snapshots: %s", name), rows, "No snapshots. Press 'c' to create one.", prompt, hints)
+}
+
+func (m AppModel) addonView() string {
+	name := ""
+	if m.detail != nil {
+		name = m.detail.Name
+	}
+
+	nameWidth := 20
+	for _, a := range m.addons {
+		nameWidth = max(nameWidth, len(a.Name))
+	}
+	var rows []string
+	for _, a := range m.addons {
+		rows = append(rows, fmt.Sprintf("%-*s  %-10s  %s", nameWidth, a.Name, a.Version, a.Repository))
+	}
+
+	hints := []struct {
+		key  string
+		desc string
+	}{
+		{"u", "upgrade"},
+		{"D", "remove"},
+		{"R", "refresh"},
+		{"esc", "back"},
+	}
+	return m.panelView(fmt.Sprintf("DDEV Add-ons: %s", name), rows, "No add-ons installed. Use 'ddev add-on get' to install one.", "", hints)
+}
+
+func (m AppModel) handleResourceKey(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Up), key.Matches(msg, m.keys.Down):
+		return m.moveCursor(msg, len(m.resources)), nil
+
+	case key.Matches(msg, m.keys.Back):
+		m.viewMode = viewDashboard
+		m.resources = nil
+		m.panelCursor = 0
+		m.panelLoading = false
+		m.statusMsg = ""
+		m.updateDashboardViewport()
+		return m, nil
+
+	case key.Matches(msg, m.keys.Quit):
+		return m, tea.Quit
+
+	case key.Matches(msg, m.keys.Sort):
+		// Keep the selected project selected in the new order
+		selected := ""
+		if m.panelCursor < len(m.resources) {
+			selected = m.resources[m.panelCursor].Name
+		}
+		sortKeys := ddevapp.ResourceSortKeys
+		m.resourceSort = sortKeys[(slices.Index(sortKeys, m.resourceSort)+1)%len(sortKeys)]
+		_ = ddevapp.SortProjectResources(m.resources, m.resourceSort)
+		m.panelCursor = max(0, slices.IndexFunc(m.resources, func(r ddevapp.ProjectResources) bool { return r.Name == selected }))
+		return m, nil
+
+	case key.Matches(msg, m.keys.Refresh):
+		if !m.panelLoading {
+			m.panelLoading = true
+			return m, tea.Batch(loadResourcesCmd, m.spinner.Tick)
+		}
+		return m, nil
+	}
+
+	return m, nil
+}
+
+// resourceView lists the CPU, memory and disk use of every project, with
+// the services of the selected project below it.
+func (m AppModel) resourceView() string {
+	nameWidth := 16
+	for _, r := range m.resources {
+		nameWidth = max(nameWidth, len(r.Name))
+	}
+
+	var rows []string
+	for i, r := range m.resources {
+		cpu, memory := "-", "-"
+		if r.Status == ddevapp.SiteRunning {
+			cpu = fmt.Sprintf("%.1f%%", r.CPUPercent)
+			memory = util.FormatBytes(r.MemoryBytes)
+		}
+		rows = append(rows, fmt.Sprintf("%-*s  %s  %7s  %9s  %9s", nameWidth, r.Name, m.renderStatus(r.Status), cpu, memory, util.FormatBytes(r.DiskBytes())))
+		if i != m.panelCursor {
+			continue
+		}
+		for _, svc := range r.Services {
+			rows[len(rows)-1] += fmt.Sprintf("\n    %-*s  %12s  %7s  %9s", nameWidth-2, svc.Service, "", fmt.Sprintf("%.1f%%", svc.CPUPercent), util.FormatBytes(svc.MemoryBytes))
+		}
+		if r.DiskBytes() > 0 {
+			rows[len(rows)-1] += "\n    " + m.styles.DetailLabel.Render(fmt.Sprintf("volumes %s, mutagen %s, snapshots %s",
+				util.FormatBytes(r.VolumeBytes), util.FormatBytes(r.MutagenBytes), util.FormatBytes(r.SnapshotBytes)))
+		}
+	}
+
+	hints := []struct {
+		key  string
+		desc string
+	}{
+		{"o", "sort"},
+		{"R", "refresh"},
+		{"esc", "back"},
+		{"q", "quit"},
+	}
+	return m.panelView(fmt.Sprintf("DDEV Resources (sorted by %s)", m.resourceSort), rows, "No DDEV projects found.", "", hints)
+}
@@ -0,0 +1,71 @@
+package util
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SplitShellWords splits a command line into its arguments like a POSIX
+// shell, without expanding anything: arguments are separated by whitespace,
+// single quotes keep everything literally, double quotes keep everything
+// but backslash escapes of ", \, $ and `, and a backslash outside quotes
+// escapes the next character.
+func SplitShellWords(line string) ([]string, error) {
+	var args []string
+	var arg strings.Builder
+	inArg := false
+	var quote rune
+	escaped := false
+
+	for _, r := range line {
+		switch {
+		case escaped:
+			// A backslash in double quotes only escapes some characters
+			if quote == '"' && !strings.ContainsRune("\"\\$`\n", r) {
+				arg.WriteRune('\\')
+			}
+			// A backslash before a newline continues the line
+			if r != '\n' {
+				arg.WriteRune(r)
+				inArg = true
+			}
+			escaped = false
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				arg.WriteRune(r)
+			}
+		case r == '\\':
+			escaped = true
+		case quote == '"':
+			if r == '"' {
+				quote = 0
+			} else {
+				arg.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inArg = true
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			if inArg {
+				args = append(args, arg.String())
+				arg.Reset()
+				inArg = false
+			}
+		default:
+			arg.WriteRune(r)
+			inArg = true
+		}
+	}
+	if escaped {
+		return nil, fmt.Errorf("unfinished backslash escape at the end of '%s'", line)
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("missing closing %c quote in '%s'", quote, line)
+	}
+	if inArg {
+		args = append(args, arg.String())
+	}
+	return args, nil
+}
@@ -0,0 +1,40 @@
+package util_test
+
+import (
+	"testing"
+
+	"github.com/ddev/ddev/pkg/util"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSplitShellWords checks splitting command lines like a shell
+func TestSplitShellWords(t *testing.T) {
+	testCases := []struct {
+		line     string
+		expected []string
+	}{
+		{"", nil},
+		{"   ", nil},
+		{"composer install", []string{"composer", "install"}},
+		{"  composer \t install\n", []string{"composer", "install"}},
+		{`op read "op://dev/my item/password"`, []string{"op", "read", "op://dev/my item/password"}},
+		{`echo 'single "quoted" $HOME'`, []string{"echo", `single "quoted" $HOME`}},
+		{`echo "double \"quoted\" \$HOME \n"`, []string{"echo", `double "quoted" $HOME \n`}},
+		{`echo escaped\ space \'`, []string{"echo", "escaped space", "'"}},
+		{`echo '' ""`, []string{"echo", "", ""}},
+		{`drush site:install --account-name='admin user'`, []string{"drush", "site:install", "--account-name=admin user"}},
+		{"echo 'it''s'", []string{"echo", "its"}},
+		{"line \\\ncontinued", []string{"line", "continued"}},
+		{"line \\\n  continued", []string{"line", "continued"}},
+	}
+	for _, tc := range testCases {
+		args, err := util.SplitShellWords(tc.line)
+		require.NoError(t, err, tc.line)
+		require.Equal(t, tc.expected, args, tc.line)
+	}
+
+	for _, line := range []string{`echo "unclosed`, `echo 'unclosed`, `echo trailing\`} {
+		_, err := util.SplitShellWords(line)
+		require.Error(t, err, line)
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"path/filepath"
 	"regexp"
 	"runtime"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -350,6 +351,24 @@ func Killall(processName string) {
 	}
 }
 
+// RunDdevCommand runs this ddev executable in dir, connected to the
+// terminal, with args followed by the arguments of commandLine, which are
+// split like a shell does, so "composer create-project 'vendor/my project'"
+// works.
+func RunDdevCommand(dir string, args []string, commandLine string) error {
+	lineArgs, err := SplitShellWords(commandLine)
+	if err != nil {
+		return err
+	}
+	ddevBin, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("unable to find the ddev executable: %v", err)
+	}
+	c := exec.HostCommand(ddevBin, slices.Concat(args, lineArgs)...)
+	c.Dir, c.Stdin, c.Stdout, c.Stderr = dir, os.Stdin, os.Stdout, os.Stderr
+	return c.Run()
+}
+
 // InterfaceSliceToStringSlice converts a slice of interfaces to a slice of strings
 func InterfaceSliceToStringSlice(input any) ([]string, error) {
 	switch v := input.(type) {
@@ -28,10 +28,10 @@ var AmplitudeAPIKey = ""
 var WebImg = "ddev/ddev-webserver"
 
 // WebTag defines the default web image tag
-var WebTag = "c202e92108" // 20260814_rfay_docker_update_phase_2-c202e92108
+var WebTag = "564cd87e0b" // master-564cd87e0b
 
 // WebTagBranch is the branch WebTag's content was built from.
-var WebTagBranch = "20260814_rfay_docker_update_phase_2"
+var WebTagBranch = "master"
 
 // DBImg defines the default db image used for applications.
 var DBImg = "ddev/ddev-dbserver"
@@ -0,0 +1,156 @@
+// Package xhprof analyzes XHProf profiles as stored by XHGui, to report the
+// most expensive functions of a run and to compare two runs.
+package xhprof
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+)
+
+// Metrics are the measurements of a call, in microseconds and bytes
+type Metrics struct {
+	Calls      int64 `json:"ct"`
+	WallTime   int64 `json:"wt"`
+	CPU        int64 `json:"cpu"`
+	Memory     int64 `json:"mu"`
+	PeakMemory int64 `json:"pmu"`
+}
+
+// Profile maps "parent==>child" call edges, and "main()" for the root, to
+// their metrics, as recorded by xhprof_disable()
+type Profile map[string]Metrics
+
+// Function has the inclusive and exclusive metrics of a function over a run
+type Function struct {
+	Name              string `json:"name"`
+	Calls             int64  `json:"calls"`
+	WallTime          int64  `json:"wall_time"`
+	ExclusiveWallTime int64  `json:"exclusive_wall_time"`
+	CPU               int64  `json:"cpu"`
+	ExclusiveCPU      int64  `json:"exclusive_cpu"`
+	Memory            int64  `json:"memory"`
+	ExclusiveMemory   int64  `json:"exclusive_memory"`
+}
+
+// splitEdge returns the parent and the child of a call edge, the parent
+// is empty for the root
+func splitEdge(edge string) (parent string, child string) {
+	if parent, child, found := strings.Cut(edge, "==>"); found {
+		return parent, child
+	}
+	return "", edge
+}
+
+// Functions returns the metrics of every function of the profile. The
+// inclusive metrics sum the calls of the function, the exclusive ones
+// subtract what the functions it calls used.
+func (p Profile) Functions() []Function {
+	functions := map[string]*Function{}
+	get := func(name string) *Function {
+		f, ok := functions[name]
+		if !ok {
+			f = &Function{Name: name}
+			functions[name] = f
+		}
+		return f
+	}
+
+	for edge, m := range p {
+		_, child := splitEdge(edge)
+		f := get(child)
+		f.Calls += m.Calls
+		f.WallTime += m.WallTime
+		f.CPU += m.CPU
+		f.Memory += m.Memory
+	}
+	for _, f := range functions {
+		f.ExclusiveWallTime = f.WallTime
+		f.ExclusiveCPU = f.CPU
+		f.ExclusiveMemory = f.Memory
+	}
+	for edge, m := range p {
+		parent, _ := splitEdge(edge)
+		if parent == "" {
+			continue
+		}
+		f := get(parent)
+		f.ExclusiveWallTime -= m.WallTime
+		f.ExclusiveCPU -= m.CPU
+		f.ExclusiveMemory -= m.Memory
+	}
+
+	result := make([]Function, 0, len(functions))
+	for _, f := range functions {
+		result = append(result, *f)
+	}
+	slices.SortFunc(result, func(a, b Function) int {
+		return cmp.Or(cmp.Compare(b.WallTime, a.WallTime), strings.Compare(a.Name, b.Name))
+	})
+	return result
+}
+
+// Top returns at most n of the functions, sorted by the given metric,
+// highest first
+func Top(functions []Function, n int, metric func(Function) int64) []Function {
+	sorted := slices.Clone(functions)
+	slices.SortStableFunc(sorted, func(a, b Function) int {
+		return cmp.Compare(metric(b), metric(a))
+	})
+	if n > 0 && len(sorted) > n {
+		sorted = sorted[:n]
+	}
+	return sorted
+}
+
+// Delta compares the inclusive metrics of a function in two runs
+type Delta struct {
+	Name     string  `json:"name"`
+	Before   Metrics `json:"before"`
+	After    Metrics `json:"after"`
+	WallTime int64   `json:"wall_time_delta"`
+	CPU      int64   `json:"cpu_delta"`
+	Memory   int64   `json:"memory_delta"`
+	OnlyIn   string  `json:"only_in,omitempty"`
+}
+
+// Diff compares the functions of two profiles, sorted by the largest
+// change of wall time first. OnlyIn is "before" or "after" for a function
+// that is called in one of the profiles only.
+func Diff(before Profile, after Profile) []Delta {
+	toMetrics := func(functions []Function) map[string]Metrics {
+		m := make(map[string]Metrics, len(functions))
+		for _, f := range functions {
+			m[f.Name] = Metrics{Calls: f.Calls, WallTime: f.WallTime, CPU: f.CPU, Memory: f.Memory}
+		}
+		return m
+	}
+	beforeMetrics := toMetrics(before.Functions())
+	afterMetrics := toMetrics(after.Functions())
+
+	var deltas []Delta
+	for name, b := range beforeMetrics {
+		a, ok := afterMetrics[name]
+		d := Delta{Name: name, Before: b, After: a}
+		if !ok {
+			d.OnlyIn = "before"
+		}
+		deltas = append(deltas, d)
+	}
+	for name, a := range afterMetrics {
+		if _, ok := beforeMetrics[name]; !ok {
+			deltas = append(deltas, Delta{Name: name, After: a, OnlyIn: "after"})
+		}
+	}
+	for i := range deltas {
+		deltas[i].WallTime = deltas[i].After.WallTime - deltas[i].Before.WallTime
+		deltas[i].CPU = deltas[i].After.CPU - deltas[i].Before.CPU
+		deltas[i].Memory = deltas[i].After.Memory - deltas[i].Before.Memory
+	}
+
+	abs := func(v int64) int64 { return max(v, -v) }
+	slices.SortFunc(deltas, func(a, b Delta) int {
+		return cmp.Or(cmp.Compare(abs(b.WallTime), abs(a.WallTime)), strings.Compare(a.Name, b.Name))
+	})
+	return deltas
+}
@@ -0,0 +1,63 @@
+package xhprof_test
+
+import (
+	"testing"
+
+	"github.com/ddev/ddev/pkg/xhprof"
+	"github.com/stretchr/testify/require"
+)
+
+// TestFunctions checks the inclusive and exclusive metrics of a profile
+func TestFunctions(t *testing.T) {
+	profile := xhprof.Profile{
+		"main()":              {Calls: 1, WallTime: 1000, CPU: 800, Memory: 500},
+		"main()==>load":       {Calls: 1, WallTime: 300, CPU: 200, Memory: 300},
+		"main()==>render":     {Calls: 2, WallTime: 600, CPU: 500, Memory: 100},
+		"render==>strtolower": {Calls: 10, WallTime: 50, CPU: 40, Memory: 0},
+		"load==>strtolower":   {Calls: 5, WallTime: 20, CPU: 10, Memory: 0},
+	}
+
+	functions := profile.Functions()
+	require.Equal(t, []string{"main()", "render", "load", "strtolower"}, names(functions))
+	require.Equal(t, xhprof.Function{Name: "main()", Calls: 1, WallTime: 1000, ExclusiveWallTime: 100, CPU: 800, ExclusiveCPU: 100, Memory: 500, ExclusiveMemory: 100}, functions[0])
+	require.Equal(t, xhprof.Function{Name: "render", Calls: 2, WallTime: 600, ExclusiveWallTime: 550, CPU: 500, ExclusiveCPU: 460, Memory: 100, ExclusiveMemory: 100}, functions[1])
+	require.Equal(t, xhprof.Function{Name: "strtolower", Calls: 15, WallTime: 70, ExclusiveWallTime: 70, CPU: 50, ExclusiveCPU: 50}, functions[3])
+
+	top := xhprof.Top(functions, 2, func(f xhprof.Function) int64 { return f.ExclusiveWallTime })
+	require.Equal(t, []string{"render", "load"}, names(top))
+}
+
+// TestDiff checks the deltas between two profiles
+func TestDiff(t *testing.T) {
+	before := xhprof.Profile{
+		"main()":          {Calls: 1, WallTime: 1000, CPU: 800, Memory: 500},
+		"main()==>render": {Calls: 1, WallTime: 600, CPU: 500, Memory: 100},
+		"main()==>cache":  {Calls: 1, WallTime: 100, CPU: 100, Memory: 50},
+	}
+	after := xhprof.Profile{
+		"main()":          {Calls: 1, WallTime: 1500, CPU: 1000, Memory: 700},
+		"main()==>render": {Calls: 1, WallTime: 650, CPU: 520, Memory: 100},
+		"main()==>query":  {Calls: 3, WallTime: 700, CPU: 300, Memory: 200},
+	}
+
+	deltas := xhprof.Diff(before, after)
+	require.Len(t, deltas, 4)
+	require.Equal(t, xhprof.Delta{Name: "query", After: xhprof.Metrics{Calls: 3, WallTime: 700, CPU: 300, Memory: 200}, WallTime: 700, CPU: 300, Memory: 200, OnlyIn: "after"}, deltas[0])
+	require.Equal(t, "main()", deltas[1].Name)
+	require.Equal(t, int64(500), deltas[1].WallTime)
+	require.Equal(t, int64(200), deltas[1].CPU)
+	require.Equal(t, int64(200), deltas[1].Memory)
+	require.Equal(t, "cache", deltas[2].Name)
+	require.Equal(t, int64(-100), deltas[2].WallTime)
+	require.Equal(t, "before", deltas[2].OnlyIn)
+	require.Equal(t, "render", deltas[3].Name)
+	require.Equal(t, int64(50), deltas[3].WallTime)
+}
+
+func names(functions []xhprof.Function) []string {
+	var result []string
+	for _, f := range functions {
+		result = append(result, f.Name)
+	}
+	return result
+}